@@ -16,6 +16,11 @@
 // @BasePath /go-api
 //
 // @schemes http https
+//
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Admin routes require "Bearer <app.server.admin.token>"
 package main
 
 import (
@@ -32,6 +37,7 @@ import (
 	"go-api/internal/application/processor"
 	"go-api/internal/application/schedule"
 	"go-api/internal/domain/gateway/api"
+	"go-api/internal/domain/gateway/cache"
 	"go-api/internal/domain/gateway/db"
 	"go-api/internal/domain/gateway/queue"
 	"go-api/internal/domain/usecase/health"
@@ -61,22 +67,41 @@ func main() {
 	shortUrlRepository := db.NewSQLCShortUrlGateway(sqlc.Db)
 	cityGateway := db.NewSQLCCityGateway(sqlc.Db)
 
-	// Init AWS Resources
-	sqsClient := aws.NewSqsClient()
-	queueSender := aws.NewSQSSenderAdapter(sqsClient)
-
-	// Init Queue Health Gateway
-	queueHealthGateway := queue.NewQueueHealthGateway()
-
 	// Init Redis Client
+	redisMode, err := redis.ParseMode(resource.GetString("app.cache.redis.mode"))
+	if err != nil {
+		log.Fatalf("Invalid Redis configuration: %v", err)
+	}
 	redisConfig := redis.NewRedisConfig().
+		WithMode(redisMode).
 		WithHost(resource.GetString("app.cache.redis.host")).
 		WithPort(resource.GetInt("app.cache.redis.port")).
+		WithUsername(resource.GetString("app.cache.redis.username")).
 		WithPassword(resource.GetString("app.cache.redis.password")).
 		WithDatabase(resource.GetInt("app.cache.redis.db")).
 		WithMinIdleConns(resource.GetInt("app.cache.redis.pool.min-idle-conns")).
 		WithMaxIdleConns(resource.GetInt("app.cache.redis.pool.max-idle-conns")).
-		WithMaxActive(resource.GetInt("app.cache.redis.pool.max-active"))
+		WithMaxActive(resource.GetInt("app.cache.redis.pool.max-active")).
+		WithDefaultCacheTTL(resource.GetDuration("app.cache.redis.ttl.default")).
+		WithCacheTTL(shorturl.CacheName, resource.GetDuration("app.cache.redis.ttl.short-url")).
+		WithCacheTTL(weather.CacheName, resource.GetDuration("app.cache.redis.ttl.weather")).
+		WithTLS(resource.GetBool("app.cache.redis.tls.enabled")).
+		WithTLSServerName(resource.GetString("app.cache.redis.tls.server-name"))
+	switch redisMode {
+	case redis.ModeSentinel:
+		redisConfig.
+			WithSentinel(resource.GetString("app.cache.redis.sentinel.master-name"),
+				redis.ParseAddrs(resource.GetString("app.cache.redis.sentinel.addresses"))...).
+			WithSentinelAuth("", resource.GetString("app.cache.redis.sentinel.password"))
+	case redis.ModeCluster:
+		redisConfig.WithCluster(redis.ParseAddrs(resource.GetString("app.cache.redis.cluster.addresses"))...)
+	}
+	if caFile := resource.GetString("app.cache.redis.tls.ca-file"); caFile != "" {
+		redisConfig.WithTLSCAFile(caFile)
+	}
+	if certFile := resource.GetString("app.cache.redis.tls.cert-file"); certFile != "" {
+		redisConfig.WithTLSClientCert(certFile, resource.GetString("app.cache.redis.tls.key-file"))
+	}
 
 	redisClient := redis.NewClient(redisConfig)
 	defer func(client *redis.Client) {
@@ -85,6 +110,57 @@ func main() {
 		}
 	}(redisClient)
 
+	// Preload the Lua scripts of pkg/redis, they are loaded again on demand if this fails
+	if err := redis.LoadScripts(context.Background(), redisClient); err != nil {
+		log.Warnf("Error loading Redis scripts: %v", err)
+	}
+
+	// Init AWS Resources
+	sqsClient := aws.NewQueueClient(redisClient)
+	payloadStore, err := aws.NewPayloadStore()
+	if err != nil {
+		log.Fatalf("Failed to create SQS payload store: %v", err)
+	}
+	queueSender := aws.NewSQSSenderAdapter(sqsClient, &sqs.SenderConfig{
+		DeduplicationMode: sqs.ParseDeduplicationMode(resource.GetString("app.cloud.sqs.deduplication-mode")),
+		Producer:          resource.GetString("app.name"),
+		PayloadStore:      payloadStore,
+	})
+
+	// Init Queue Health Gateway
+	queueHealthGateway := queue.NewQueueHealthGateway()
+
+	// Rate limit the routes configured in app.server.rate-limit.policies
+	appmw.SetupRateLimiter(e, redisClient)
+
+	// Replay the responses of the routes configured in app.server.idempotency.routes by Idempotency-Key
+	appmw.SetupIdempotency(e, redisClient)
+
+	// Init Use Case Cache (read-through, tag-based invalidation shared by every cache name)
+	cacheEnabled := resource.GetBool("app.cache.enabled")
+	var cacheInvalidation cache.InvalidationGateway
+	var localCache *redis.LocalCache
+	if cacheEnabled {
+		if resource.GetBool("app.cache.local.enabled") {
+			localCache = redis.NewLocalCache(redisClient, redis.NewLocalCacheOptions().
+				WithMaxEntries(resource.GetInt("app.cache.local.max-entries")).
+				WithEvictionPolicy(redis.ParseEvictionPolicy(resource.GetString("app.cache.local.eviction-policy"))).
+				WithCacheTTL(shorturl.CacheName, resource.GetDuration("app.cache.local.ttl.short-url")).
+				WithCacheTTL(weather.CacheName, resource.GetDuration("app.cache.local.ttl.weather")))
+			if err := localCache.Start(context.Background()); err != nil {
+				log.Fatalf("Failed to start local cache: %v", err)
+			}
+			defer func() {
+				if err := localCache.Close(); err != nil {
+					log.Errorf("Error closing local cache: %v", err)
+				}
+			}()
+		}
+
+		// Invalidation evicts the local tier of every instance too, localCache is nil when it is disabled
+		cacheInvalidation = redis.NewCache(redisClient, redis.NewCacheOptions().WithLocalCache(localCache))
+	}
+
 	// Init External API Gateways
 	httpClientOptions := http.ClientOptions{
 		FollowRedirect:      resource.GetBool("weather.follow-redirect"),
@@ -96,44 +172,106 @@ func main() {
 		ReadTimeout:         resource.GetDuration("weather.read-timeout"),
 		DefaultContentType:  resource.GetString("weather.default-content-type"),
 	}
+	if resource.GetBool("weather.circuit-breaker.enabled") {
+		httpClientOptions.CircuitBreaker = &http.CircuitBreakerConfig{
+			FailureRateThreshold: resource.GetFloat64("weather.circuit-breaker.failure-rate-threshold"),
+			MinimumRequests:      resource.GetInt("weather.circuit-breaker.minimum-requests"),
+			Window:               resource.GetDuration("weather.circuit-breaker.window"),
+			CoolDown:             resource.GetDuration("weather.circuit-breaker.cool-down"),
+			HalfOpenRequests:     resource.GetInt("weather.circuit-breaker.half-open-requests"),
+			OnStateChange: func(host string, from, to http.CircuitState) {
+				log.Warn(msg.GetMessage("app.circuit-breaker.state-change", host, from, to))
+			},
+		}
+		// Share open circuits, so BrasilAPI failing on one instance stops the calls of all of them
+		if resource.GetBool("weather.circuit-breaker.shared") {
+			httpClientOptions.CircuitBreaker.SharedState = redis.NewCircuitBreakerStore(redisClient,
+				resource.GetString("weather.circuit-breaker.namespace"))
+		}
+	}
 	weatherGateway := api.NewWeatherGateway(resource.GetString("weather.base-url"), httpClientOptions)
 
+	// Cap concurrent BrasilAPI calls across all instances
+	if maxCalls := resource.GetInt("weather.concurrency.max-calls"); maxCalls > 0 {
+		weatherGateway = api.NewLimitedWeatherGateway(weatherGateway, redis.NewSemaphore(redisClient, "brasilapi", maxCalls,
+			redis.NewLockOptions().
+				WithTTL(resource.GetDuration("weather.concurrency.lease-ttl")).
+				WithRetryDelay(resource.GetDuration("weather.concurrency.retry-delay")).
+				WithMaxRetries(resource.GetInt("weather.concurrency.max-retries")).
+				WithLockNamespace("semaphores").
+				WithCacheName("brasilapi-semaphore")))
+	}
+
 	// Init UseCases
-	healthUseCase := health.NewHealthUseCase(dbGatewaySQLC, queueHealthGateway)
 	shortUrlUseCase := shorturl.NewShortUrlUseCase(shortUrlRepository)
 	weatherUseCase := weather.NewWeatherUseCase(resource.GetString("weather.queue-name"),
 		resource.GetInt("weather.batch-size"),
 		queueSender,
 		weatherGateway,
-		cityGateway)
+		cityGateway,
+		cacheInvalidation)
+	if cacheEnabled {
+		shortUrlUseCase = shorturl.NewCachedShortUrlUseCase(shortUrlUseCase, redisClient, localCache)
+		weatherUseCase = weather.NewCachedWeatherUseCase(weatherUseCase, redisClient, localCache)
+	}
+
+	// Init Schedulers, their cron only runs on the instance elected leader
+	shortUrlScheduler := schedule.NewShortUrlScheduler(shortUrlUseCase, redisClient)
+	weatherScheduler := schedule.NewWeatherScheduler(
+		weatherUseCase,
+		redisClient,
+		resource.GetString("weather.schedule.cron"),
+		resource.GetInt("weather.schedule.lock-ttl"),
+		resource.GetInt("weather.schedule.refresh-interval"),
+	)
+
+	// Init Health UseCase, the readiness probe fails when a critical contributor, Postgres or Redis, is down
+	healthOptions := func(criticality health.Criticality) *health.ContributorOptions {
+		return health.NewContributorOptions().
+			WithTimeout(resource.GetDuration("app.health.timeout")).
+			WithRefreshInterval(resource.GetDuration("app.health.refresh-interval")).
+			WithCriticality(criticality)
+	}
+	healthRegistry := health.NewRegistry()
+	healthRegistry.Register("database", dbGatewaySQLC, healthOptions(health.Critical))
+	healthRegistry.Register("redis", cache.NewRedisHealthGateway(redisClient), healthOptions(health.Critical))
+	healthRegistry.Register("queue", queueHealthGateway, healthOptions(health.DegradedOnly))
+	healthRegistry.Register("locks", cache.NewLockHealthGateway(), healthOptions(health.DegradedOnly))
+	healthRegistry.Register("rate-limiters", cache.NewRateLimiterHealthGateway(), healthOptions(health.DegradedOnly))
+	healthRegistry.Register("weather-scheduler", cache.NewLeaderHealthGateway(weatherScheduler.Elector()), healthOptions(health.DegradedOnly))
+	healthRegistry.Register("circuit-breakers", api.NewCircuitBreakerHealthGateway(), healthOptions(health.DegradedOnly))
+	healthUseCase := health.NewHealthUseCase(healthRegistry)
 
 	// Init Controllers
 	healthController := controller.NewHealthController(apiGroup, healthUseCase)
 	shortUrlController := controller.NewShortUrlController(apiGroup, shortUrlUseCase)
 	weatherController := controller.NewWeatherController(apiGroup, weatherUseCase)
+	metricsController := controller.NewMetricsController(apiGroup, queueHealthGateway)
 
 	// Init Routes
 	healthController.InitHealthRoutes()
 	shortUrlController.InitShortUrlRoutes()
 	weatherController.InitWeatherRoutes()
+	metricsController.InitMetricsRoutes()
+
+	// Admin routes, authenticated by the app.server.admin.token Bearer token
+	if resource.GetBool("app.server.admin.enabled") {
+		adminToken := resource.GetString("app.server.admin.token")
+		if adminToken == "" {
+			log.Fatalf("Admin routes are enabled without app.server.admin.token")
+		}
+		adminController := controller.NewAdminController(apiGroup.Group("/admin", appmw.AdminAuth(adminToken)),
+			redis.NewKeyspaceAdmin(redisClient, localCache))
+		adminController.InitAdminRoutes()
+	}
 
 	// Swagger route
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	// Init Schedule
-	shortUrlScheduler := schedule.NewShortUrlScheduler(shortUrlUseCase)
-	shortUrlScheduler.InitShortUrlScheduleTasks()
-
-	// Init Weather Schedule with distributed locking
-	weatherScheduler := schedule.NewWeatherScheduler(
-		weatherUseCase,
-		redisClient,
-		resource.GetString("weather.schedule.cron"),
-		resource.GetInt("weather.schedule.lock-ttl"),
-		resource.GetInt("weather.schedule.refresh-interval"),
-	)
+	shortUrlScheduler.InitShortUrlScheduleTasks(context.Background())
 
-	// Initialize scheduler in background (goroutine handles lock acquisition)
+	// Campaign for leadership in background, the cron only runs on the leader instance
 	weatherScheduler.InitWeatherScheduleTasks(context.Background())
 
 	// Init Weather Processor and Worker
@@ -147,6 +285,8 @@ func main() {
 			WaitTimeSeconds:     resource.GetInt64("weather.worker.wait-time-seconds"),
 			PoolSize:            resource.GetInt64("weather.worker.pool-size"),
 			LogLevel:            sqs.ParseLogLevel(resource.GetString("weather.worker.log-level")),
+			PayloadStore:        payloadStore,
+			StallThreshold:      resource.GetInt64("weather.worker.stall-threshold"),
 		},
 	)
 
@@ -24,9 +24,391 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/redis/caches": {
+            "get": {
+                "description": "List the configured cache names with their key count and estimated memory",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Redis caches",
+                "responses": {
+                    "200": {
+                        "description": "Cache key counts and memory estimates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/redis.CacheKeyspaceStats"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/redis/caches/{name}": {
+            "delete": {
+                "description": "Delete every key of a cache name, in Redis and in the local cache of every instance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Flush a Redis cache",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cache name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of deleted keys",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown cache name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/redis/keys": {
+            "get": {
+                "description": "Return the type, remaining TTL and memory usage of a key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Inspect a Redis key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Full key, e.g. short-url::abc123",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key type, TTL and memory usage",
+                        "schema": {
+                            "$ref": "#/definitions/redis.KeyInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/redis/locks": {
+            "get": {
+                "description": "List the registered locks with their full key and whether this instance holds them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Redis locks",
+                "responses": {
+                    "200": {
+                        "description": "Registered locks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/redis.LockInfo"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a lock whoever holds it, so a lock left by a stuck holder can be taken again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Force-release a Redis lock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Full lock key, e.g. semaphores::brasilapi",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Lock released"
+                    },
+                    "400": {
+                        "description": "Missing key or not a lock key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Lock not held",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/redis/rate-limiters": {
+            "get": {
+                "description": "List the registered rate limiters with their current usage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Redis rate limiters",
+                "responses": {
+                    "200": {
+                        "description": "Rate limiter metrics by name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "object",
+                                "additionalProperties": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/redis/rate-limiters/{name}": {
+            "delete": {
+                "description": "Delete the counters of a rate limiter for every client, so all requests are allowed again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset a Redis rate limiter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rate limiter name, e.g. rate-limit-short-url-create",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of deleted keys",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown rate limiter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
         "/health": {
             "get": {
-                "description": "Check the health status of the application and its dependencies",
+                "description": "Check the health status of the application and its components: database, queue, Redis, locks, rate limiters, scheduler leader election and HTTP circuit breakers.\nThe status is DOWN when a critical component is down and DEGRADED when only a non-critical component is not UP",
                 "consumes": [
                     "application/json"
                 ],
@@ -47,6 +429,72 @@ const docTemplate = `{
                 }
             }
         },
+        "/health/liveness": {
+            "get": {
+                "description": "Check that the application is running, without checking its dependencies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Application is running",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/readiness": {
+            "get": {
+                "description": "Check the critical dependencies required to serve requests, Postgres and Redis. A DEGRADED status is still ready",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Application is ready",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "A critical dependency is down",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Expose queue worker metrics in the Prometheus text format",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Prometheus metrics endpoint",
+                "responses": {
+                    "200": {
+                        "description": "Prometheus metrics",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/short-url": {
             "get": {
                 "description": "Retrieve all short URLs with pagination and optional URL filtering",
@@ -124,6 +572,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.CreateShortUrlDTO"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Executes the request once, repeated requests replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -142,6 +596,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "A request with the same Idempotency-Key is in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key already used with a different payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, see the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -374,6 +855,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.CreateCityMonitoringDTO"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Executes the request once, repeated requests replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -395,6 +882,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "A request with the same Idempotency-Key is in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key already used with a different payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, see the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -712,11 +1226,11 @@ const docTemplate = `{
         "model.HealthResponse": {
             "type": "object",
             "properties": {
-                "database": {
-                    "$ref": "#/definitions/model.ComponentHealthStatus"
-                },
-                "queue": {
-                    "$ref": "#/definitions/model.ComponentHealthStatus"
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.ComponentHealthStatus"
+                    }
                 },
                 "status": {
                     "$ref": "#/definitions/model.HealthStatus"
@@ -728,12 +1242,14 @@ const docTemplate = `{
             "enum": [
                 "UP",
                 "DOWN",
-                "UNKNOWN"
+                "UNKNOWN",
+                "DEGRADED"
             ],
             "x-enum-varnames": [
                 "StatusUp",
                 "StatusDown",
-                "StatusUnknown"
+                "StatusUnknown",
+                "StatusDegraded"
             ]
         },
         "model.UpdateShortUrlDTO": {
@@ -749,6 +1265,68 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "redis.CacheKeyspaceStats": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "description": "MemoryBytes is estimated from the memory used by a sample of the keys",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sampled_keys": {
+                    "type": "integer"
+                },
+                "ttl": {
+                    "description": "TTL is the TTL configured for the cache name",
+                    "type": "string"
+                }
+            }
+        },
+        "redis.KeyInfo": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "ttl": {
+                    "description": "TTL is the remaining time to live, empty when the key never expires",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "redis.LockInfo": {
+            "type": "object",
+            "properties": {
+                "acquired": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Admin routes require \"Bearer \u003capp.server.admin.token\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
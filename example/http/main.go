@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"go-api/pkg/http"
 	"go-api/pkg/log"
@@ -106,6 +107,11 @@ func (l *StandardHTTPLogger) LogRequestRetry(method, url string, headers map[str
 	log.Infow("HTTP RETRY", "method", method, "url", url, "attempt", retryCount, "maxRetries", maxRetries, "previousStatus", httpStatus, "error", err)
 }
 
+// LogCircuitStateChange logs circuit breaker state changes
+func (l *StandardHTTPLogger) LogCircuitStateChange(host string, from, to http.CircuitState, requests, failures int) {
+	log.Warnw("HTTP CIRCUIT", "host", host, "from", from, "to", to, "requests", requests, "failures", failures)
+}
+
 // maskSensitiveHeaders creates a copy of headers with sensitive values masked
 func (l *StandardHTTPLogger) maskSensitiveHeaders(headers map[string]string) map[string]string {
 	if len(headers) == 0 {
@@ -345,4 +351,33 @@ func main() {
 	successResp, errorResp, statusCode, err = clientWithDefaultBackoff.Post("/post", nil, customHeaders, postBody, nil, nil)
 
 	fmt.Println("=== Backoff and Logging Examples Completed ===")
+
+	// ===========================================
+	// CIRCUIT BREAKER EXAMPLES
+	// ===========================================
+
+	fmt.Println("=== Starting Circuit Breaker Examples ===")
+
+	// The circuit of a host opens when half of its last 4 requests failed, then rejects
+	// requests for 5 seconds without sending them
+	clientWithCircuitBreaker := http.NewHttpClient("https://httpbin.org", http.ClientOptions{
+		CircuitBreaker: &http.CircuitBreakerConfig{
+			FailureRateThreshold: 0.5,
+			MinimumRequests:      4,
+			Window:               30 * time.Second,
+			CoolDown:             5 * time.Second,
+			HalfOpenRequests:     1,
+		},
+		Logger: httpLogger,
+	})
+
+	for i := 0; i < 6; i++ {
+		_, _, statusCode, err = clientWithCircuitBreaker.Get("/status/503", nil, nil, nil, nil)
+		fmt.Println("Circuit Breaker Response - status:", statusCode, "open:", errors.Is(err, http.ErrCircuitOpen), "err:", err)
+	}
+	for host, status := range clientWithCircuitBreaker.CircuitBreakerStatus() {
+		fmt.Println("Circuit Breaker Status - host:", host, "state:", status.State, "failure rate:", status.FailureRate)
+	}
+
+	fmt.Println("=== Circuit Breaker Examples Completed ===")
 }
@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go-api/pkg/redis"
@@ -181,6 +183,99 @@ func main() {
 	fmt.Printf("✓ Got cached product data: ID=%d, Name=%s, Price=$%.2f\n",
 		cachedProduct.ID, cachedProduct.Name, cachedProduct.Price)
 
+	// Stampede protection: concurrent misses share one setter call, expired values are
+	// served for a grace period while refreshed in background, and recomputes are
+	// serialized across instances with a distributed lock
+	protectedCache := redis.NewCache(client, redis.NewCacheOptions().
+		WithCacheName("products").
+		WithTTL(2*time.Second).
+		WithStaleWhileRevalidate(30*time.Second).
+		WithEarlyExpiration(1).
+		WithDistributedLoad(redis.DefaultLockOptions()))
+
+	var setterCalls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var product Product
+			if err := protectedCache.GetOrSet(ctx, "product_789", &product, func() (interface{}, error) {
+				setterCalls.Add(1)
+				time.Sleep(100 * time.Millisecond)
+				return Product{ID: 789, Name: "Stampede Widget", Price: 19.99, InStock: true}, nil
+			}); err != nil {
+				fmt.Printf("Failed to get or set protected product: %v\n", err)
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Printf("✓ 10 concurrent GetOrSet calls ran the setter %d time(s)\n", setterCalls.Load())
+
+	// =============================================================================
+	// TWO-TIER CACHE (IN-PROCESS LRU IN FRONT OF REDIS)
+	// =============================================================================
+	fmt.Println("\nTwo-tier cache:")
+	localCache := redis.NewLocalCache(client, redis.NewLocalCacheOptions().
+		WithMaxEntries(1000).
+		WithCacheTTL("hot_products", 10*time.Second).
+		WithEvictionPolicy(redis.EvictionLRU))
+	if err := localCache.Start(ctx); err != nil {
+		fmt.Printf("Failed to start local cache invalidation: %v\n", err)
+		return
+	}
+	defer localCache.Close()
+
+	hotCache := redis.NewCache(client, redis.NewCacheOptions().
+		WithCacheName("hot_products").
+		WithTTL(5*time.Minute).
+		WithLocalCache(localCache))
+
+	if err := hotCache.Set(ctx, "product_1", Product{ID: 1, Name: "Hot Widget", Price: 9.99}); err != nil {
+		fmt.Printf("Failed to set hot product: %v\n", err)
+		return
+	}
+	for i := 0; i < 5; i++ {
+		var hotProduct Product
+		if err := hotCache.Get(ctx, "product_1", &hotProduct); err != nil {
+			fmt.Printf("Failed to get hot product: %v\n", err)
+			return
+		}
+	}
+	stats := hotCache.Stats()
+	fmt.Printf("✓ Local hits=%d misses=%d, Redis hits=%d misses=%d\n",
+		stats.LocalHits, stats.LocalMisses, stats.RemoteHits, stats.RemoteMisses)
+
+	// Deleting evicts the entry locally and in every other instance sharing the invalidation channel
+	if err := hotCache.Delete(ctx, "product_1"); err != nil {
+		fmt.Printf("Failed to delete hot product: %v\n", err)
+		return
+	}
+	fmt.Printf("✓ Local cache entries after delete: %d\n", localCache.Stats().Entries)
+
+	// =============================================================================
+	// TYPED CACHE WITH CODECS
+	// =============================================================================
+	fmt.Println("\nTyped cache with codecs:")
+	jsonProducts := redis.NewTypedCache[int, Product](client,
+		redis.NewCacheOptions().WithCacheName("typed_products"),
+		redis.IntKeyEncoder[int](), redis.JSONCodec)
+	if err := jsonProducts.Set(ctx, 1001, Product{ID: 1001, Name: "Typed Widget", Price: 5.5}); err != nil {
+		fmt.Printf("Failed to set typed product: %v\n", err)
+		return
+	}
+
+	// A cache switched to another codec still reads entries written with the previous one
+	gzipProducts := redis.NewTypedCache[int, Product](client,
+		redis.NewCacheOptions().WithCacheName("typed_products"),
+		redis.IntKeyEncoder[int](), redis.GzipJSONCodec)
+	typedProduct, found, err := gzipProducts.Get(ctx, 1001)
+	if err != nil {
+		fmt.Printf("Failed to get typed product: %v\n", err)
+		return
+	}
+	fmt.Printf("✓ Read JSON entry through the gzip cache: found=%t, Name=%s\n", found, typedProduct.Name)
+
 	// =============================================================================
 	// CACHE WITH ARRAYS AND SLICES
 	// =============================================================================
@@ -696,6 +791,12 @@ func main() {
 		// Different data types
 		"int_data", "float_data", "bool_data", "array_data",
 
+		// Typed cache
+		"typed_products::1001",
+
+		// Stampede-protected cache
+		"products::product_789", "products::product_789::__meta",
+
 		// Custom serial cache
 		"custom_serial_cache::custom_data",
 
@@ -77,6 +77,10 @@ func main() {
 	fmt.Println("\nExample Scenario: Cron job with distributed lock...")
 	exampleScenarioCronJobWithDistributedLock(ctx, client)
 
+	// Example Scenario: Fencing tokens and Redlock quorum
+	fmt.Println("\nExample Scenario: Fencing tokens and Redlock quorum...")
+	exampleScenarioFencingTokens(ctx, client)
+
 	// Show final health check
 	fmt.Println("\nFinal health check after all scenarios:")
 	testHealthCheck(client)
@@ -126,7 +130,7 @@ func exampleScenarioSimpleAttemptLockWithNoRetries(ctx context.Context, client *
 			// Add small delay to simulate different startup times
 			time.Sleep(time.Duration(instanceID-1) * 100 * time.Millisecond)
 
-			err := lock.Lock(ctx)
+			_, err := lock.Lock(ctx)
 			if err != nil {
 				fmt.Printf("Instance %d:  Failed to acquire lock as expected: %v\n", instanceID, err)
 				return
@@ -165,7 +169,7 @@ func exampleScenarioManualRefreshAndRetriesToAcquire(ctx context.Context, client
 			// Add small delay to simulate different startup times
 			time.Sleep(time.Duration(instanceID-1) * 500 * time.Millisecond)
 
-			err := lock.Lock(ctx)
+			_, err := lock.Lock(ctx)
 			if err != nil {
 				fmt.Printf("Instance %d:  Failed to acquire lock after retries as expected: %v\n", instanceID, err)
 				return
@@ -216,7 +220,7 @@ func exampleScenarioAutoRefreshLongTaskProcessing(ctx context.Context, client *r
 			time.Sleep(time.Duration(instanceID-1) * 1 * time.Second)
 
 			fmt.Printf("Instance %d: Attempting to acquire persistent lock...\n", instanceID)
-			err := lock.Lock(ctx)
+			_, err := lock.Lock(ctx)
 			if err != nil {
 				fmt.Printf("Instance %d: Failed to acquire persistent lock: %v\n", instanceID, err)
 				return
@@ -305,7 +309,7 @@ func testFailureScenario(ctx context.Context, client *redis.Client) {
 			time.Sleep(time.Duration(instanceID-1) * 1 * time.Second)
 
 			fmt.Printf("Instance %d: Attempting to acquire critical task lock...\n", instanceID)
-			err := lock.Lock(ctx)
+			_, err := lock.Lock(ctx)
 			if err != nil {
 				fmt.Printf("Instance %d: Failed to acquire lock: %v\n", instanceID, err)
 				return
@@ -373,7 +377,7 @@ func testLockAcquisitionAfterFailure(ctx context.Context, client *redis.Client)
 	lock := redis.NewPersistentLock(client, "critical_task", 5*time.Second, 2*time.Second, "critical")
 
 	fmt.Println("New Instance: Attempting to acquire critical task lock after system failure...")
-	err := lock.Lock(ctx)
+	_, err := lock.Lock(ctx)
 	if err != nil {
 		fmt.Printf("New Instance: Failed to acquire lock: %v\n", err)
 		return
@@ -440,7 +444,7 @@ func testPanicFailureScenario(ctx context.Context, client *redis.Client) {
 			time.Sleep(time.Duration(instanceID-1) * 1 * time.Second)
 
 			fmt.Printf("Instance %d: Attempting to acquire panic task lock...\n", instanceID)
-			err := lock.Lock(instanceCtx)
+			_, err := lock.Lock(instanceCtx)
 			if err != nil {
 				fmt.Printf("Instance %d: Failed to acquire lock: %v\n", instanceID, err)
 				return
@@ -485,7 +489,7 @@ func testLockAcquisitionAfterPanic(ctx context.Context, client *redis.Client) {
 	lock := redis.NewPersistentLock(client, "panic_task", 5*time.Second, 2*time.Second, "panic")
 
 	fmt.Println("New Instance: Attempting to acquire panic task lock after panic failure...")
-	err := lock.Lock(ctx)
+	_, err := lock.Lock(ctx)
 	if err != nil {
 		fmt.Printf("New Instance: Failed to acquire lock: %v\n", err)
 		return
@@ -549,7 +553,7 @@ func exampleScenarioCronJobWithDistributedLock(ctx context.Context, client *redi
 			lock := redis.NewScheduledTaskLock(client, "cron_job_lock", 10*time.Second, 2*time.Second, "cron_scheduler")
 
 			fmt.Printf("Instance %d: Attempting to acquire cron job lock...\n", instanceID)
-			err := lock.Lock(instanceCtx)
+			_, err := lock.Lock(instanceCtx)
 			if err != nil {
 				fmt.Printf("Instance %d: Failed to acquire cron job lock: %v\n", instanceID, err)
 				return
@@ -732,7 +736,7 @@ func (c *CronJobScheduler) simulateJobExecution(jobName string, duration time.Du
 	defer cancel()
 
 	// Try to acquire lock for this job
-	err := lock.Lock(ctx)
+	_, err := lock.Lock(ctx)
 	if err != nil {
 		fmt.Printf("Cron Scheduler %d: Job '%s' skipped - another instance is running it: %v\n", c.instanceID, jobName, err)
 		return
@@ -749,6 +753,66 @@ func (c *CronJobScheduler) simulateJobExecution(jobName string, duration time.Du
 	}
 }
 
+// exampleScenarioFencingTokens shows how fencing tokens reject the writes of an instance
+// that was paused while holding the lock, and how a Redlock is held on a quorum of nodes
+func exampleScenarioFencingTokens(ctx context.Context, client *redis.Client) {
+	opts := redis.NewLockOptions().
+		WithTTL(1 * time.Second).
+		WithMaxRetries(0).
+		WithLockNamespace("example-locks")
+
+	paused := redis.NewLock(client, "fenced-report", opts)
+	pausedToken, err := paused.Lock(ctx)
+	if err != nil {
+		fmt.Printf("Paused Instance: Failed to acquire lock: %v\n", err)
+		return
+	}
+	fmt.Printf("Paused Instance: Acquired lock with fencing token %d, pausing longer than the TTL...\n", pausedToken)
+	time.Sleep(1500 * time.Millisecond)
+	fmt.Printf("Paused Instance: Lock validity after the pause: %v\n", paused.Validity())
+
+	active := redis.NewLock(client, "fenced-report", opts)
+	activeToken, err := active.Lock(ctx)
+	if err != nil {
+		fmt.Printf("Active Instance: Failed to acquire lock: %v\n", err)
+		return
+	}
+	defer active.Unlock(ctx)
+	fmt.Printf("Active Instance: Acquired expired lock with fencing token %d\n", activeToken)
+
+	if err := redis.ValidateFencingToken(ctx, client, "reports", activeToken); err == nil {
+		fmt.Println("Active Instance: Write accepted")
+	}
+	if err := redis.ValidateFencingToken(ctx, client, "reports", pausedToken); err != nil {
+		fmt.Printf("Paused Instance: Write rejected as expected: %v\n", err)
+	}
+
+	// A Redlock needs a majority of independent Redis nodes, databases of the same
+	// server are used here only to demonstrate the API
+	var nodes []*redis.Client
+	for database := 1; database <= 3; database++ {
+		node := redis.NewClient(redis.NewRedisConfig().
+			WithHost(client.GetConfig().Host).
+			WithPort(client.GetConfig().Port).
+			WithPassword(client.GetConfig().Password).
+			WithDatabase(database))
+		defer node.Close()
+		nodes = append(nodes, node)
+	}
+
+	redlock := redis.NewRedlock(nodes, "fenced-report", opts)
+	token, err := redlock.Lock(ctx)
+	if err != nil {
+		fmt.Printf("Redlock: Failed to acquire lock on a quorum: %v\n", err)
+		return
+	}
+	fmt.Printf("Redlock: Acquired lock on a quorum of %d nodes with fencing token %d, valid for %v\n",
+		len(nodes), token, redlock.Validity().Round(time.Millisecond))
+	if err := redlock.Unlock(ctx); err != nil {
+		fmt.Printf("Redlock: Failed to release lock: %v\n", err)
+	}
+}
+
 // testHealthCheck demonstrates health check with lock status
 func testHealthCheck(client *redis.Client) {
 	healthChecker := redis.NewHealthChecker(client.GetClient(), client.GetConfig())
@@ -93,6 +93,11 @@ func main() {
 	exampleScenarioPatternSubscription(ctx, client)
 	time.Sleep(200 * time.Millisecond) // Wait between scenarios
 
+	// Example Scenario: Dynamic subscriptions
+	fmt.Println("=== Scenario: Dynamic Channel and Pattern Subscriptions ===")
+	exampleScenarioDynamicSubscriptions(ctx, client)
+	time.Sleep(200 * time.Millisecond) // Wait between scenarios
+
 	// Example Scenario: Error handling
 	fmt.Println("=== Scenario: Error Handling ===")
 	exampleScenarioErrorHandling(ctx, client)
@@ -101,6 +106,11 @@ func main() {
 	// Example Scenario: Health check
 	fmt.Println("=== Scenario: Health Check and Monitoring ===")
 	exampleScenarioHealthCheck(ctx, client)
+	time.Sleep(200 * time.Millisecond) // Wait between scenarios
+
+	// Example Scenario: Streams with a consumer group
+	fmt.Println("=== Scenario: Streams with Consumer Group and Dead-Letter ===")
+	exampleScenarioStreams(ctx, client)
 
 	fmt.Println("All pub/sub scenarios completed successfully!")
 }
@@ -293,6 +303,71 @@ func exampleScenarioPatternSubscription(ctx context.Context, client *redis.Clien
 	fmt.Printf("Results: %d messages received (expected: 4, non-matching: 1)", counter.GetCount())
 }
 
+// exampleScenarioDynamicSubscriptions demonstrates mixing channels and patterns and changing them while running
+func exampleScenarioDynamicSubscriptions(ctx context.Context, client *redis.Client) {
+	fmt.Println("Testing runtime subscribe and unsubscribe with channels and patterns...")
+
+	counter := &MessageCounter{}
+	handler := &CustomHandler{counter: counter, name: "DynamicHandler"}
+
+	config := redis.NewPubSubConfig().
+		WithPoolSize(2).
+		WithQueueSize(50).
+		WithLogLevel(redis.InfoLevel).
+		WithChannelNamespace("dynamic")
+
+	subscriber, err := redis.NewSubscriber(client.GetClient(), handler, config)
+	if err != nil {
+		fmt.Printf("Failed to create subscriber: %v", err)
+		return
+	}
+	defer subscriber.Close()
+
+	publisher := redis.NewPublisher(client.GetClient(), config)
+
+	// Channels and patterns can be mixed on the same subscriber
+	if err := subscriber.Subscribe(ctx, "orders"); err != nil {
+		fmt.Printf("Failed to subscribe: %v", err)
+		return
+	}
+	if err := subscriber.PSubscribe(ctx, "user:*"); err != nil {
+		fmt.Printf("Failed to subscribe to patterns: %v", err)
+		return
+	}
+
+	go subscriber.Start(ctx)
+	time.Sleep(100 * time.Millisecond)
+
+	// Add a channel while running
+	if err := subscriber.Subscribe(ctx, "payments"); err != nil {
+		fmt.Printf("Failed to subscribe: %v", err)
+		return
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	for _, channel := range []string{"orders", "payments", "user:123"} {
+		if err := publisher.Publish(ctx, channel, "Message to "+channel); err != nil {
+			fmt.Printf("Failed to publish to %s: %v", channel, err)
+		}
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// Remove a channel while running, its messages are no longer received
+	if err := subscriber.Unsubscribe(ctx, "orders"); err != nil {
+		fmt.Printf("Failed to unsubscribe: %v", err)
+		return
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if err := publisher.Publish(ctx, "orders", "This should not be received"); err != nil {
+		fmt.Printf("Failed to publish to orders: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	fmt.Printf("Results: %d messages received (expected: 3)", counter.GetCount())
+	showHealthCheck(subscriber)
+}
+
 // exampleScenarioErrorHandling demonstrates error handling in message handlers
 func exampleScenarioErrorHandling(ctx context.Context, client *redis.Client) {
 	fmt.Println("Testing error handling in message handlers...")
@@ -452,6 +527,75 @@ func exampleScenarioHealthCheck(ctx context.Context, client *redis.Client) {
 	showHealthCheck(subscriber)
 }
 
+// exampleScenarioStreams demonstrates the stream consumer, which uses the same handler and
+// conventions as the subscriber but keeps messages until they are acknowledged
+func exampleScenarioStreams(ctx context.Context, client *redis.Client) {
+	fmt.Println("Testing stream producer and consumer group with dead-lettering...")
+
+	counter := &MessageCounter{}
+	handler := redis.HandlerFunc(func(ctx context.Context, stream string, message string) error {
+		if message == "error_trigger" {
+			return fmt.Errorf("intentional error for message: %s", message)
+		}
+		counter.Increment()
+		fmt.Printf("[StreamHandler] Received on stream '%s': %s", stream, message)
+		return nil
+	})
+
+	config := redis.NewStreamConfig().
+		WithPoolSize(2).
+		WithLogLevel(redis.InfoLevel).
+		WithStreamNamespace("order_service").
+		WithGroup("order_workers").
+		WithMaxLen(1000).
+		WithBlockTimeout(100 * time.Millisecond).
+		WithClaimMinIdle(100 * time.Millisecond).
+		WithClaimInterval(200 * time.Millisecond).
+		WithMaxDeliveries(3)
+
+	consumer, err := redis.NewStreamConsumer(client.GetClient(), handler, config)
+	if err != nil {
+		fmt.Printf("Failed to create stream consumer: %v", err)
+		return
+	}
+	defer consumer.Close()
+
+	producer := redis.NewStreamProducer(client.GetClient(), config)
+
+	// Join the consumer group, creating the stream if needed
+	err = consumer.Subscribe(ctx, "orders")
+	if err != nil {
+		fmt.Printf("Failed to subscribe: %v", err)
+		return
+	}
+	fmt.Println("Joined group order_workers on stream order_service::orders")
+
+	// Messages published before the consumer starts are kept in the stream
+	for _, message := range []string{"order_1", "error_trigger", "order_2"} {
+		if err := producer.Publish(ctx, "orders", message); err != nil {
+			fmt.Printf("Failed to publish: %v", err)
+			return
+		}
+	}
+
+	go consumer.Start(ctx)
+
+	// Wait for the failing message to be retried and dead-lettered
+	time.Sleep(1500 * time.Millisecond)
+
+	deadLetters, err := client.GetClient().XLen(ctx, config.DeadLetterStream("orders")).Result()
+	if err != nil {
+		fmt.Printf("Failed to read dead-letter stream: %v", err)
+	}
+
+	healthCheck := consumer.HealthCheck()
+	fmt.Printf("=== RESULTS ===")
+	fmt.Printf("Status: %s", healthCheck.Status)
+	fmt.Printf("Successful: %d", counter.GetCount())
+	fmt.Printf("Dead-lettered: %d (stream %s)", deadLetters, config.DeadLetterStream("orders"))
+	fmt.Printf("Pending: %s", healthCheck.Details["pending_messages"])
+}
+
 // showHealthCheck displays the health status of a subscriber
 func showHealthCheck(subscriber *redis.Subscriber) {
 	healthCheck := subscriber.HealthCheck()
@@ -115,6 +115,10 @@ func main() {
 	fmt.Println("\n=== Scenario: Combined TPH and TPD Limits ===")
 	exampleScenarioTPHandTPD(ctx, client)
 
+	// Example Scenario: Token bucket, GCRA and fixed window algorithms
+	fmt.Println("\n=== Scenario: Rate Limit Algorithms ===")
+	exampleScenarioAlgorithms(ctx, client)
+
 	fmt.Println("\n All distributed rate limiter scenarios completed successfully!")
 }
 
@@ -1321,3 +1325,40 @@ func exampleScenarioTPHandTPD(ctx context.Context, client *redis.Client) {
 	fmt.Printf("Rejected by TPD limit: %d\n", stats.tpd)
 	showMetrics(ctx, limiter, "Final State")
 }
+
+// exampleScenarioAlgorithms demonstrates the token bucket, GCRA and fixed window algorithms
+func exampleScenarioAlgorithms(ctx context.Context, client *redis.Client) {
+	fmt.Println("Testing 10 TPS with a burst of 3, sending 6 requests at once...")
+
+	for _, algorithm := range []redis.RateLimitAlgorithm{
+		redis.AlgorithmSlidingWindow,
+		redis.AlgorithmTokenBucket,
+		redis.AlgorithmGCRA,
+		redis.AlgorithmFixedWindow,
+	} {
+		opts := redis.NewRateLimiterOptions().
+			WithAlgorithm(algorithm).
+			WithMaxTransactionsPerSecond(10).
+			WithBurst(3).
+			WithNamespace("algorithms").
+			WithCacheName("algorithm_test_" + string(algorithm))
+
+		limiter, err := redis.NewRateLimiter(client, "algorithm_endpoint", opts)
+		if err != nil {
+			fmt.Printf("Failed to create rate limiter: %v\n", err)
+			return
+		}
+
+		fmt.Printf("\n[%s]\n", algorithm)
+		for i := range 6 {
+			if _, err := limiter.Acquire(ctx); err != nil {
+				retryAfter, _ := redis.RetryAfter(err)
+				fmt.Printf("Request %d:  Rejected (%v, retry after %v)\n", i+1, err, retryAfter)
+				continue
+			}
+			fmt.Printf("Request %d:  Accepted\n", i+1)
+		}
+		showMetrics(ctx, limiter, string(algorithm))
+		limiter.Cleanup(ctx)
+	}
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"go-api/internal/domain/gateway/cache"
+	"go-api/pkg/log"
+	"go-api/pkg/msg"
+	"go-api/pkg/redis"
+)
+
+type AdminController struct {
+	api             *echo.Group
+	keyspaceGateway cache.KeyspaceGateway
+}
+
+// NewAdminController creates the Redis admin controller, api must be a group requiring authentication
+func NewAdminController(api *echo.Group, keyspaceGateway cache.KeyspaceGateway) *AdminController {
+	return &AdminController{api: api, keyspaceGateway: keyspaceGateway}
+}
+
+// InitAdminRoutes initializes Redis admin routes
+func (controller *AdminController) InitAdminRoutes() {
+	controller.api.GET("/redis/caches", controller.ListCaches)
+	controller.api.DELETE("/redis/caches/:name", controller.FlushCache)
+	controller.api.GET("/redis/keys", controller.InspectKey)
+	controller.api.GET("/redis/locks", controller.ListLocks)
+	controller.api.DELETE("/redis/locks", controller.ReleaseLock)
+	controller.api.GET("/redis/rate-limiters", controller.ListRateLimiters)
+	controller.api.DELETE("/redis/rate-limiters/:name", controller.ResetRateLimiter)
+}
+
+// ListCaches godoc
+// @Summary List Redis caches
+// @Description List the configured cache names with their key count and estimated memory
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {array} redis.CacheKeyspaceStats "Cache key counts and memory estimates"
+// @Failure 401 {object} map[string]string "Missing or invalid admin token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/redis/caches [get]
+func (controller *AdminController) ListCaches(c echo.Context) error {
+	caches := make([]*redis.CacheKeyspaceStats, 0)
+	for _, name := range controller.keyspaceGateway.CacheNames() {
+		stats, err := controller.keyspaceGateway.CacheStats(c.Request().Context(), name)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		caches = append(caches, stats)
+	}
+	return c.JSON(http.StatusOK, caches)
+}
+
+// FlushCache godoc
+// @Summary Flush a Redis cache
+// @Description Delete every key of a cache name, in Redis and in the local cache of every instance
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param name path string true "Cache name"
+// @Success 200 {object} map[string]interface{} "Number of deleted keys"
+// @Failure 401 {object} map[string]string "Missing or invalid admin token"
+// @Failure 404 {object} map[string]string "Unknown cache name"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/redis/caches/{name} [delete]
+func (controller *AdminController) FlushCache(c echo.Context) error {
+	name := c.Param("name")
+	deleted, err := controller.keyspaceGateway.FlushCache(c.Request().Context(), name)
+	auditLog(c, "flush-cache", name, err, zap.Int64("deleted_keys", deleted))
+
+	if errors.Is(err, redis.ErrUnknownCacheName) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"cache": name, "deleted_keys": deleted})
+}
+
+// InspectKey godoc
+// @Summary Inspect a Redis key
+// @Description Return the type, remaining TTL and memory usage of a key
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param key query string true "Full key, e.g. short-url::abc123"
+// @Success 200 {object} redis.KeyInfo "Key type, TTL and memory usage"
+// @Failure 400 {object} map[string]string "Missing key"
+// @Failure 401 {object} map[string]string "Missing or invalid admin token"
+// @Failure 404 {object} map[string]string "Key not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/redis/keys [get]
+func (controller *AdminController) InspectKey(c echo.Context) error {
+	key := c.QueryParam("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "key is required"})
+	}
+
+	info, err := controller.keyspaceGateway.InspectKey(c.Request().Context(), key)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if info == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "key not found"})
+	}
+	return c.JSON(http.StatusOK, info)
+}
+
+// ListLocks godoc
+// @Summary List Redis locks
+// @Description List the registered locks with their full key and whether this instance holds them
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {array} redis.LockInfo "Registered locks"
+// @Failure 401 {object} map[string]string "Missing or invalid admin token"
+// @Router /admin/redis/locks [get]
+func (controller *AdminController) ListLocks(c echo.Context) error {
+	return c.JSON(http.StatusOK, controller.keyspaceGateway.Locks())
+}
+
+// ReleaseLock godoc
+// @Summary Force-release a Redis lock
+// @Description Delete a lock whoever holds it, so a lock left by a stuck holder can be taken again
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param key query string true "Full lock key, e.g. semaphores::brasilapi"
+// @Success 204 "Lock released"
+// @Failure 400 {object} map[string]string "Missing key or not a lock key"
+// @Failure 401 {object} map[string]string "Missing or invalid admin token"
+// @Failure 404 {object} map[string]string "Lock not held"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/redis/locks [delete]
+func (controller *AdminController) ReleaseLock(c echo.Context) error {
+	key := c.QueryParam("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "key is required"})
+	}
+
+	err := controller.keyspaceGateway.ForceReleaseLock(c.Request().Context(), key)
+	auditLog(c, "release-lock", key, err)
+
+	if errors.Is(err, redis.ErrNotALock) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if errors.Is(err, redis.ErrLockNotHeld) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListRateLimiters godoc
+// @Summary List Redis rate limiters
+// @Description List the registered rate limiters with their current usage
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} map[string]map[string]string "Rate limiter metrics by name"
+// @Failure 401 {object} map[string]string "Missing or invalid admin token"
+// @Router /admin/redis/rate-limiters [get]
+func (controller *AdminController) ListRateLimiters(c echo.Context) error {
+	return c.JSON(http.StatusOK, controller.keyspaceGateway.RateLimiters(c.Request().Context()))
+}
+
+// ResetRateLimiter godoc
+// @Summary Reset a Redis rate limiter
+// @Description Delete the counters of a rate limiter for every client, so all requests are allowed again
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param name path string true "Rate limiter name, e.g. rate-limit-short-url-create"
+// @Success 200 {object} map[string]interface{} "Number of deleted keys"
+// @Failure 401 {object} map[string]string "Missing or invalid admin token"
+// @Failure 404 {object} map[string]string "Unknown rate limiter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/redis/rate-limiters/{name} [delete]
+func (controller *AdminController) ResetRateLimiter(c echo.Context) error {
+	name := c.Param("name")
+	deleted, err := controller.keyspaceGateway.ResetRateLimiter(c.Request().Context(), name)
+	auditLog(c, "reset-rate-limiter", name, err, zap.Int64("deleted_keys", deleted))
+
+	if errors.Is(err, redis.ErrUnknownRateLimiter) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"rate_limiter": name, "deleted_keys": deleted})
+}
+
+// auditLog records a destructive admin action with its target, client and outcome
+func auditLog(c echo.Context, action, target string, err error, fields ...zap.Field) {
+	result := "success"
+	if err != nil {
+		result = err.Error()
+	}
+
+	fields = append(fields,
+		zap.String("audit_action", action),
+		zap.String("audit_target", target),
+		zap.String("remote_ip", c.RealIP()),
+		zap.String("user_agent", c.Request().UserAgent()),
+	)
+	if err != nil {
+		log.Warn(msg.GetMessage("app.admin.audit", action, target, c.RealIP(), result), append(fields, zap.Error(err))...)
+		return
+	}
+	log.Info(msg.GetMessage("app.admin.audit", action, target, c.RealIP(), result), fields...)
+}
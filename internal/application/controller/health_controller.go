@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/labstack/echo/v4"
+	"go-api/internal/domain/model"
 	"go-api/internal/domain/usecase/health"
 	"net/http"
 )
@@ -18,11 +19,14 @@ func NewHealthController(api *echo.Group, useCase health.UseCase) *HealthControl
 // InitHealthRoutes initializes health check routes
 func (controller *HealthController) InitHealthRoutes() {
 	controller.api.GET("/health", controller.CheckHealth())
+	controller.api.GET("/health/liveness", controller.CheckLiveness())
+	controller.api.GET("/health/readiness", controller.CheckReadiness())
 }
 
 // CheckHealth godoc
 // @Summary Health check endpoint
-// @Description Check the health status of the application and its dependencies
+// @Description Check the health status of the application and its components: database, queue, Redis, locks, rate limiters, scheduler leader election and HTTP circuit breakers.
+// @Description The status is DOWN when a critical component is down and DEGRADED when only a non-critical component is not UP
 // @Tags health
 // @Accept json
 // @Produce json
@@ -30,8 +34,39 @@ func (controller *HealthController) InitHealthRoutes() {
 // @Router /health [get]
 func (controller *HealthController) CheckHealth() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		healthResponse := controller.useCase.CheckHealth()
+		healthResponse := controller.useCase.CheckHealth(c.Request().Context())
 
 		return c.JSON(http.StatusOK, healthResponse)
 	}
 }
+
+// CheckLiveness godoc
+// @Summary Liveness probe
+// @Description Check that the application is running, without checking its dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.HealthResponse "Application is running"
+// @Router /health/liveness [get]
+func (controller *HealthController) CheckLiveness() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, controller.useCase.CheckLiveness(c.Request().Context()))
+	}
+}
+
+// CheckReadiness godoc
+// @Summary Readiness probe
+// @Description Check the critical dependencies required to serve requests, Postgres and Redis. A DEGRADED status is still ready
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.HealthResponse "Application is ready"
+// @Failure 503 {object} model.HealthResponse "A critical dependency is down"
+// @Router /health/readiness [get]
+func (controller *HealthController) CheckReadiness() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		healthResponse := controller.useCase.CheckReadiness(c.Request().Context())
+		if healthResponse.Status == model.StatusDown {
+			return c.JSON(http.StatusServiceUnavailable, healthResponse)
+		}
+		return c.JSON(http.StatusOK, healthResponse)
+	}
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"go-api/internal/domain/gateway/queue"
+	"go-api/pkg/sqs"
+	"net/http"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type MetricsController struct {
+	api            *echo.Group
+	metricsGateway queue.MetricsGateway
+}
+
+func NewMetricsController(api *echo.Group, metricsGateway queue.MetricsGateway) *MetricsController {
+	return &MetricsController{api: api, metricsGateway: metricsGateway}
+}
+
+// InitMetricsRoutes initializes metrics routes
+func (controller *MetricsController) InitMetricsRoutes() {
+	controller.api.GET("/metrics", controller.GetMetrics())
+}
+
+// GetMetrics godoc
+// @Summary Prometheus metrics endpoint
+// @Description Expose queue worker metrics in the Prometheus text format
+// @Tags metrics
+// @Produce plain
+// @Success 200 {string} string "Prometheus metrics"
+// @Router /metrics [get]
+func (controller *MetricsController) GetMetrics() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, prometheusContentType)
+		c.Response().WriteHeader(http.StatusOK)
+		return sqs.WritePrometheus(c.Response(), controller.metricsGateway.WorkerMetrics()...)
+	}
+}
@@ -86,8 +86,12 @@ func (controller *ShortUrlController) FindByHash(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param shortUrl body model.CreateShortUrlDTO true "Short URL creation data"
+// @Param Idempotency-Key header string false "Executes the request once, repeated requests replay the first response"
 // @Success 201 {object} entity.ShortUrl "Created short URL"
 // @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 409 {object} map[string]string "A request with the same Idempotency-Key is in progress"
+// @Failure 422 {object} map[string]string "Idempotency-Key already used with a different payload"
+// @Failure 429 {object} map[string]string "Too many requests, see the Retry-After header"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /short-url [post]
 func (controller *ShortUrlController) Create(c echo.Context) error {
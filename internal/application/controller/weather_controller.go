@@ -86,8 +86,12 @@ func (controller *WeatherController) FindCityByNameAndState(c echo.Context) erro
 // @Accept json
 // @Produce json
 // @Param city body model.CreateCityMonitoringDTO true "City monitoring data"
+// @Param Idempotency-Key header string false "Executes the request once, repeated requests replay the first response"
 // @Success 201 {object} map[string]string "City monitoring created successfully"
 // @Failure 400 {object} map[string]string "Invalid request body or missing required fields"
+// @Failure 409 {object} map[string]string "A request with the same Idempotency-Key is in progress"
+// @Failure 422 {object} map[string]string "Idempotency-Key already used with a different payload"
+// @Failure 429 {object} map[string]string "Too many requests, see the Retry-After header"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /weather [post]
 func (controller *WeatherController) CreateCityMonitoring(c echo.Context) error {
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+	"go.uber.org/zap"
+
+	"go-api/pkg/log"
+	"go-api/pkg/msg"
+)
+
+// AdminAuth returns a middleware accepting only the requests with an "Authorization: Bearer <token>" header.
+// Rejected requests are logged with the client IP.
+func AdminAuth(token string) echo.MiddlewareFunc {
+	if token == "" {
+		panic("admin token is required")
+	}
+
+	return echomw.KeyAuthWithConfig(echomw.KeyAuthConfig{
+		KeyLookup:  "header:" + echo.HeaderAuthorization,
+		AuthScheme: "Bearer",
+		Validator: func(key string, c echo.Context) (bool, error) {
+			return subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1, nil
+		},
+		ErrorHandler: func(err error, c echo.Context) error {
+			log.Warn(msg.GetMessage("app.admin.unauthorized", c.Request().Method, c.Request().URL.Path, c.RealIP()),
+				zap.String("method", c.Request().Method),
+				zap.String("uri", c.Request().URL.Path),
+				zap.String("remote_ip", c.RealIP()),
+			)
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": msg.GetMessage("app.admin.unauthorized-response")})
+		},
+	})
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"go-api/pkg/log"
+	"go-api/pkg/msg"
+	"go-api/pkg/redis"
+	"go-api/pkg/resource"
+)
+
+const (
+	// idempotencyProperties is the configuration prefix of the idempotency middleware
+	idempotencyProperties = "app.server.idempotency"
+	// maxIdempotencyKeyLength is the maximum length of an Idempotency-Key header
+	maxIdempotencyKeyLength = 255
+
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// IdempotentRoute is a route whose requests carrying an Idempotency-Key header are executed once
+type IdempotentRoute struct {
+	// Name identifies the route, it scopes the idempotency keys so routes never share a key
+	Name string
+	// Method is the HTTP method of the route
+	Method string
+	// Path is the route path as registered in Echo, including the context path
+	Path string
+}
+
+// SetupIdempotency registers the idempotency middleware with the routes configured in app.server.idempotency
+func SetupIdempotency(e *echo.Echo, client *redis.Client) {
+	if !resource.GetBool(idempotencyProperties + ".enabled") {
+		return
+	}
+
+	routes, err := LoadIdempotentRoutes(resource.GetString("app.server.context-path"))
+	if err != nil {
+		log.Fatalf("Failed to load idempotent routes: %v", err)
+	}
+
+	opts := redis.NewIdempotencyOptions().
+		WithNamespace(resource.GetString(idempotencyProperties + ".namespace")).
+		WithTTL(resource.GetDuration(idempotencyProperties + ".ttl")).
+		WithLockTTL(resource.GetDuration(idempotencyProperties + ".lock-ttl")).
+		WithLockWait(resource.GetDuration(idempotencyProperties + ".lock-wait"))
+	e.Use(Idempotency(redis.NewIdempotencyStore(client, opts), routes...))
+}
+
+// LoadIdempotentRoutes builds the routes configured in app.server.idempotency.routes
+func LoadIdempotentRoutes(contextPath string) ([]IdempotentRoute, error) {
+	names := make([]string, 0)
+	for name := range resource.GetStringMap(idempotencyProperties + ".routes") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	routes := make([]IdempotentRoute, 0, len(names))
+	for _, name := range names {
+		properties := idempotencyProperties + ".routes." + name
+		method := strings.ToUpper(resource.GetString(properties + ".method"))
+		path := resource.GetString(properties + ".path")
+		if method == "" || path == "" {
+			return nil, fmt.Errorf("idempotent route %s: method and path are required", name)
+		}
+
+		routes = append(routes, IdempotentRoute{
+			Name:   name,
+			Method: method,
+			Path:   contextPath + path,
+		})
+		log.Info(msg.GetMessage("app.idempotency.route-loaded", name, method, contextPath+path))
+	}
+	return routes, nil
+}
+
+// Idempotency returns a middleware executing the requests of the routes once per Idempotency-Key header.
+// Repeated requests replay the stored response, concurrent duplicates get 409 and a key reused with
+// a different payload gets 422. Server errors are not stored, so they can be retried with the same key.
+// Requests are executed normally when Redis is unavailable.
+func Idempotency(store *redis.IdempotencyStore, routes ...IdempotentRoute) echo.MiddlewareFunc {
+	byRoute := make(map[string]IdempotentRoute, len(routes))
+	for _, route := range routes {
+		byRoute[route.Method+" "+route.Path] = route
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route, ok := byRoute[c.Request().Method+" "+c.Path()]
+			idempotencyKey := c.Request().Header.Get(headerIdempotencyKey)
+			if !ok || idempotencyKey == "" {
+				return next(c)
+			}
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": msg.GetMessage("app.idempotency.invalid-key", maxIdempotencyKeyLength)})
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return err
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			record, claim, err := store.Begin(ctx, route.Name+":"+idempotencyKey, requestFingerprint(c.Request(), body))
+			switch {
+			case errors.Is(err, redis.ErrIdempotencyKeyMismatch):
+				return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": msg.GetMessage("app.idempotency.mismatch")})
+			case errors.Is(err, redis.ErrIdempotencyKeyInProgress):
+				return c.JSON(http.StatusConflict, map[string]string{"error": msg.GetMessage("app.idempotency.in-progress")})
+			case err != nil:
+				log.Warn(msg.GetMessage("app.idempotency.failed", route.Name, err.Error()),
+					zap.String("route", route.Name),
+					zap.Error(err),
+				)
+				return next(c)
+			case record != nil:
+				return replayResponse(c, record)
+			}
+
+			defer func() {
+				// Free the key when the handler panics, the request can be retried once recovered
+				if r := recover(); r != nil {
+					_ = claim.Release(context.WithoutCancel(ctx))
+					panic(r)
+				}
+			}()
+
+			// Only the headers set by the handler are stored, the middlewares set theirs again on replay
+			before := c.Response().Header().Clone()
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			if err := next(c); err != nil {
+				// Write the error response now, so it is stored like any other response
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError {
+				_ = claim.Release(context.WithoutCancel(ctx))
+				return nil
+			}
+			if err := claim.Complete(context.WithoutCancel(ctx), status, handlerHeader(before, c.Response().Header()), recorder.body.Bytes()); err != nil {
+				log.Warn(msg.GetMessage("app.idempotency.failed", route.Name, err.Error()),
+					zap.String("route", route.Name),
+					zap.Error(err),
+				)
+			}
+			return nil
+		}
+	}
+}
+
+// requestFingerprint identifies a request by its method, URI and body
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replayResponse writes a stored response
+func replayResponse(c echo.Context, record *redis.IdempotencyRecord) error {
+	header := c.Response().Header()
+	for name, values := range record.Header {
+		header[name] = values
+	}
+	header.Set(headerIdempotentReplayed, "true")
+
+	c.Response().WriteHeader(record.Status)
+	_, err := c.Response().Write(record.Body)
+	return err
+}
+
+// handlerHeader returns the headers added or changed since before
+func handlerHeader(before, after http.Header) map[string][]string {
+	header := make(map[string][]string)
+	for name, values := range after {
+		if previous, ok := before[name]; !ok || strings.Join(previous, ",") != strings.Join(values, ",") {
+			header[name] = values
+		}
+	}
+	return header
+}
+
+// responseRecorder copies the body written to a response
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes to the response and to the copy
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the recorded response writer, for http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
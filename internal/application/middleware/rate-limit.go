@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"go-api/pkg/log"
+	"go-api/pkg/msg"
+	"go-api/pkg/redis"
+	"go-api/pkg/resource"
+)
+
+const (
+	// rateLimitProperties is the configuration prefix of the rate limiting middleware
+	rateLimitProperties = "app.server.rate-limit"
+	// apiKeyHeader is the header read by APIKeyExtractor
+	apiKeyHeader = "X-API-Key"
+
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+	headerRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// KeyExtractor returns the key a request is rate limited by
+type KeyExtractor func(c echo.Context) string
+
+// IPKeyExtractor rate limits requests by client IP
+func IPKeyExtractor() KeyExtractor {
+	return func(c echo.Context) string {
+		return "ip:" + c.RealIP()
+	}
+}
+
+// APIKeyExtractor rate limits requests by the X-API-Key header, falling back to the client IP.
+// The API key is hashed so it is not stored in Redis.
+func APIKeyExtractor() KeyExtractor {
+	return func(c echo.Context) string {
+		apiKey := c.Request().Header.Get(apiKeyHeader)
+		if apiKey == "" {
+			return "ip:" + c.RealIP()
+		}
+		sum := sha256.Sum256([]byte(apiKey))
+		return "api-key:" + hex.EncodeToString(sum[:16])
+	}
+}
+
+// HeaderKeyExtractor rate limits requests by a header, falling back to the client IP
+func HeaderKeyExtractor(name string) KeyExtractor {
+	return func(c echo.Context) string {
+		if value := c.Request().Header.Get(name); value != "" {
+			return "header:" + value
+		}
+		return "ip:" + c.RealIP()
+	}
+}
+
+// ParamKeyExtractor rate limits requests by a path param, falling back to the client IP
+func ParamKeyExtractor(name string) KeyExtractor {
+	return func(c echo.Context) string {
+		if value := c.Param(name); value != "" {
+			return "param:" + value
+		}
+		return "ip:" + c.RealIP()
+	}
+}
+
+// ParseKeyExtractor converts a configuration value (ip, api-key, header:<name> or param:<name>) into a KeyExtractor
+func ParseKeyExtractor(value string) (KeyExtractor, error) {
+	kind, name, _ := strings.Cut(strings.TrimSpace(value), ":")
+	switch strings.ToLower(kind) {
+	case "", "ip":
+		return IPKeyExtractor(), nil
+	case "api-key":
+		return APIKeyExtractor(), nil
+	case "header":
+		if name == "" {
+			return nil, fmt.Errorf("header key extractor requires a header name")
+		}
+		return HeaderKeyExtractor(name), nil
+	case "param":
+		if name == "" {
+			return nil, fmt.Errorf("param key extractor requires a param name")
+		}
+		return ParamKeyExtractor(name), nil
+	default:
+		return nil, fmt.Errorf("invalid key extractor: %s", value)
+	}
+}
+
+// RateLimitPolicy rate limits the requests of a route
+type RateLimitPolicy struct {
+	// Name identifies the policy, it is the key of its rate limiter
+	Name string
+	// Method is the HTTP method of the route
+	Method string
+	// Path is the route path as registered in Echo, including the context path
+	Path string
+	// Key returns the key each request is limited by
+	Key KeyExtractor
+	// Limiter enforces the limits of the policy
+	Limiter *redis.RateLimiter
+}
+
+// SetupRateLimiter registers the rate limiting middleware with the policies configured in app.server.rate-limit
+func SetupRateLimiter(e *echo.Echo, client *redis.Client) {
+	if !resource.GetBool(rateLimitProperties + ".enabled") {
+		return
+	}
+
+	policies, err := LoadRateLimitPolicies(client, resource.GetString("app.server.context-path"))
+	if err != nil {
+		log.Fatalf("Failed to load rate limit policies: %v", err)
+	}
+	e.Use(RateLimit(policies...))
+}
+
+// LoadRateLimitPolicies builds the policies configured in app.server.rate-limit.policies
+func LoadRateLimitPolicies(client *redis.Client, contextPath string) ([]RateLimitPolicy, error) {
+	names := make([]string, 0)
+	for name := range resource.GetStringMap(rateLimitProperties + ".policies") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	namespace := resource.GetString(rateLimitProperties + ".namespace")
+	policies := make([]RateLimitPolicy, 0, len(names))
+	for _, name := range names {
+		policy, err := loadRateLimitPolicy(client, namespace, contextPath, name)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit policy %s: %w", name, err)
+		}
+		policies = append(policies, policy)
+		log.Info(msg.GetMessage("app.rate-limit.policy-loaded", name, policy.Method, policy.Path))
+	}
+	return policies, nil
+}
+
+// loadRateLimitPolicy builds a policy from its configuration
+func loadRateLimitPolicy(client *redis.Client, namespace, contextPath, name string) (RateLimitPolicy, error) {
+	properties := rateLimitProperties + ".policies." + name
+
+	method := strings.ToUpper(resource.GetString(properties + ".method"))
+	path := resource.GetString(properties + ".path")
+	if method == "" || path == "" {
+		return RateLimitPolicy{}, fmt.Errorf("method and path are required")
+	}
+
+	extractor, err := ParseKeyExtractor(resource.GetString(properties + ".key"))
+	if err != nil {
+		return RateLimitPolicy{}, err
+	}
+
+	algorithm, err := redis.ParseRateLimitAlgorithm(resource.GetString(properties + ".algorithm"))
+	if err != nil {
+		return RateLimitPolicy{}, err
+	}
+
+	limiter, err := redis.NewRateLimiter(client, name, redis.NewRateLimiterOptions().
+		WithAlgorithm(algorithm).
+		WithMaxActiveTransactions(resource.GetInt(properties+".max-active")).
+		WithMaxTransactionsPerSecond(resource.GetInt(properties+".per-second")).
+		WithMaxTransactionsPerMinute(resource.GetInt(properties+".per-minute")).
+		WithMaxTransactionsPerHour(resource.GetInt(properties+".per-hour")).
+		WithMaxTransactionsPerDay(resource.GetInt(properties+".per-day")).
+		WithBurst(resource.GetInt(properties+".burst")).
+		WithNamespace(namespace).
+		WithCacheName("rate-limit-"+name))
+	if err != nil {
+		return RateLimitPolicy{}, err
+	}
+
+	return RateLimitPolicy{
+		Name:    name,
+		Method:  method,
+		Path:    contextPath + path,
+		Key:     extractor,
+		Limiter: limiter,
+	}, nil
+}
+
+// RateLimit returns a middleware applying the policy of the matched route. Requests are allowed
+// when Redis is unavailable, so the rate limiter never takes the API down.
+func RateLimit(policies ...RateLimitPolicy) echo.MiddlewareFunc {
+	routes := make(map[string]RateLimitPolicy, len(policies))
+	for _, policy := range policies {
+		routes[policy.Method+" "+policy.Path] = policy
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			policy, ok := routes[c.Request().Method+" "+c.Path()]
+			if !ok {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := policy.Key(c)
+			result, err := policy.Limiter.AcquireWithResult(ctx, key)
+			if result != nil {
+				setRateLimitHeaders(c.Response().Header(), result)
+			}
+
+			if errors.Is(err, redis.ErrRateLimitExceeded) {
+				retryAfter := ceilSeconds(result.RetryAfter)
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(retryAfter))
+				return c.JSON(http.StatusTooManyRequests, map[string]string{"error": msg.GetMessage("app.rate-limit.exceeded", retryAfter)})
+			}
+			if err != nil {
+				log.Warn(msg.GetMessage("app.rate-limit.failed", policy.Name, err.Error()),
+					zap.String("policy", policy.Name),
+					zap.Error(err),
+				)
+				return next(c)
+			}
+
+			// Release the active transaction once the request completes
+			defer policy.Limiter.ReleaseWithKey(context.WithoutCancel(ctx), result.TransactionID, key)
+			return next(c)
+		}
+	}
+}
+
+// setRateLimitHeaders sets the RateLimit headers describing the most restrictive limit
+func setRateLimitHeaders(header http.Header, result *redis.RateLimitResult) {
+	if result.Burst <= 0 {
+		return
+	}
+	header.Set(headerRateLimitLimit, strconv.Itoa(result.Burst))
+	header.Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+	header.Set(headerRateLimitReset, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	if result.Window > 0 {
+		policy := fmt.Sprintf("%d;w=%d", result.Quota, ceilSeconds(result.Window))
+		if result.Burst != result.Quota {
+			policy += fmt.Sprintf(";burst=%d", result.Burst)
+		}
+		header.Set(headerRateLimitPolicy, policy)
+	}
+}
+
+// ceilSeconds rounds a duration up to whole seconds, at least 1 second
+func ceilSeconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 1)
+}
@@ -1,37 +1,44 @@
 package processor
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"go-api/internal/domain/entity"
 	"go-api/internal/domain/usecase/weather"
 	"go-api/pkg/log"
+	"go-api/pkg/sqs"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
 type WeatherProcessor struct {
 	weatherUseCase weather.UseCase
+	handler        *sqs.TypedHandler[entity.City]
 }
 
+var _ sqs.ContextHandler = (*WeatherProcessor)(nil)
+
 func NewWeatherProcessor(weatherUseCase weather.UseCase) *WeatherProcessor {
-	return &WeatherProcessor{
+	processor := &WeatherProcessor{
 		weatherUseCase: weatherUseCase,
 	}
+	processor.handler = sqs.NewTypedHandler(processor.processCity).
+		WithValidator(validateCity)
+	return processor
 }
 
-// HandleMessage implements the sqs.Handler interface
-func (p *WeatherProcessor) HandleMessage(msg *types.Message) error {
-	if msg == nil || msg.Body == nil {
-		return fmt.Errorf("received nil message or message body")
-	}
+// HandleMessageContext implements the sqs.ContextHandler interface
+func (p *WeatherProcessor) HandleMessageContext(ctx context.Context, msg *types.Message) error {
+	return p.handler.HandleMessageContext(ctx, msg)
+}
 
-	log.Infof("Processing weather message: %s", *msg.MessageId)
+// processCity updates the monitoring data of a decoded City entity
+func (p *WeatherProcessor) processCity(ctx context.Context, city entity.City, msg *types.Message) error {
+	log.Infof("Processing weather message: %s", sqs.MessageIDFromContext(ctx))
 
-	// Parse the message body as a City entity
-	var city entity.City
-	if err := json.Unmarshal([]byte(*msg.Body), &city); err != nil {
-		return fmt.Errorf("failed to unmarshal message body: %w", err)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("weather processing cancelled for %s: %w", city.Name, err)
 	}
 
 	// Update city monitoring using the weather use case
@@ -42,3 +49,14 @@ func (p *WeatherProcessor) HandleMessage(msg *types.Message) error {
 	log.Infof("Successfully processed weather update for city: %s", city.Name)
 	return nil
 }
+
+// validateCity checks the fields required to update a city monitoring
+func validateCity(city entity.City) error {
+	if city.ID == "" {
+		return errors.New("city id is required")
+	}
+	if city.Code == "" {
+		return errors.New("city code is required")
+	}
+	return nil
+}
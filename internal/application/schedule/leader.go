@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"context"
+	"go-api/pkg/log"
+	"go-api/pkg/redis"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// newSchedulerElector creates the leader elector of a scheduler, only the leader runs its cron
+func newSchedulerElector(redisClient *redis.Client, name string, namespace string, lockTTL time.Duration, refreshInterval time.Duration) *redis.LeaderElector {
+	return redis.NewLeaderElector(redisClient, name, redis.NewLeaderElectorOptions().
+		WithLockOptions(redis.NewLockOptions().
+			WithTTL(lockTTL).
+			WithRefreshInterval(refreshInterval).
+			WithRetryDelay(refreshInterval).
+			WithLockNamespace(namespace)))
+}
+
+// runCronWhileLeader starts the cron when the instance is elected and stops it when leadership is revoked
+func runCronWhileLeader(elector *redis.LeaderElector, scheduler *cron.Cron) {
+	elector.
+		OnElected(func(ctx context.Context) {
+			scheduler.Start()
+			log.Info("Elected scheduler leader, cron started",
+				zap.String("election", elector.Name()),
+				zap.String("identity", elector.Identity()),
+				zap.Int64("fencing_token", elector.Token()))
+		}).
+		OnRevoked(func() {
+			// Running jobs are not awaited, they check leadership before writing
+			scheduler.Stop()
+			log.Warn("Scheduler leadership revoked, cron stopped, campaigning again",
+				zap.String("election", elector.Name()),
+				zap.String("identity", elector.Identity()))
+		})
+}
+
+// isLeader reports whether the instance is still the leader of the election and no newer leader
+// already ran the task. A paused instance may resume after its leadership expired and another
+// instance took over, the fencing token prevents both from running the same task.
+func isLeader(elector *redis.LeaderElector, redisClient *redis.Client, requestID string) bool {
+	if !elector.IsLeader() {
+		log.Warn("Skipping scheduled task, this instance is no longer the leader",
+			zap.String("election", elector.Name()), zap.String("request_id", requestID))
+		return false
+	}
+
+	token := elector.Token()
+	if err := redis.ValidateFencingToken(context.Background(), redisClient, elector.Name(), token); err != nil {
+		log.Warn("Skipping scheduled task, another instance took over the leadership",
+			zap.String("election", elector.Name()), zap.String("request_id", requestID),
+			zap.Int64("fencing_token", token), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// secondsOrDefault converts a duration configured in seconds, using the default when it is not set
+func secondsOrDefault(seconds int, defaultValue time.Duration) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultValue
+}
@@ -1,35 +1,58 @@
 package schedule
 
 import (
+	"context"
 	"go-api/internal/domain/usecase/shorturl"
 	"go-api/pkg/log"
 	"go-api/pkg/msg"
+	"go-api/pkg/redis"
 	"go-api/pkg/resource"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 )
 
+// shortUrlSchedulerElection is the election of the instance running the short URL cleanup cron
+const shortUrlSchedulerElection = "short_url_clear_scheduler"
+
 type ShortUrlScheduler struct {
-	cron    *cron.Cron
-	useCase shorturl.UseCase
+	cron        *cron.Cron
+	useCase     shorturl.UseCase
+	redisClient *redis.Client
+	elector     *redis.LeaderElector
 }
 
-func NewShortUrlScheduler(useCase shorturl.UseCase) *ShortUrlScheduler {
-	return &ShortUrlScheduler{cron: cron.New(), useCase: useCase}
+func NewShortUrlScheduler(useCase shorturl.UseCase, redisClient *redis.Client) *ShortUrlScheduler {
+	return &ShortUrlScheduler{
+		cron:        cron.New(),
+		useCase:     useCase,
+		redisClient: redisClient,
+		elector: newSchedulerElector(redisClient, shortUrlSchedulerElection, "short_url_schedules",
+			secondsOrDefault(resource.GetInt("short-url.clear.lock-ttl"), 1*time.Minute),
+			secondsOrDefault(resource.GetInt("short-url.clear.refresh-interval"), 20*time.Second)),
+	}
 }
 
-// InitShortUrlScheduleTasks initializes short url schedule tasks
-func (scheduler *ShortUrlScheduler) InitShortUrlScheduleTasks() {
+// InitShortUrlScheduleTasks initializes short url schedule tasks, which run only on the elected leader instance
+func (scheduler *ShortUrlScheduler) InitShortUrlScheduleTasks(ctx context.Context) {
 	_, err := scheduler.cron.AddFunc(resource.GetString("short-url.clear.cron"), scheduler.ClearShortUrlByExpiration)
 
 	if err != nil {
 		panic(err)
 	}
 
-	scheduler.cron.Start()
+	runCronWhileLeader(scheduler.elector, scheduler.cron)
+	if err := scheduler.elector.Campaign(ctx); err != nil {
+		panic(err)
+	}
 }
 
 func (scheduler *ShortUrlScheduler) ClearShortUrlByExpiration() {
+	if !isLeader(scheduler.elector, scheduler.redisClient, uuid.New().String()) {
+		return
+	}
+
 	log.Info(msg.GetMessage("short-url.cron.start"))
 
 	err := scheduler.useCase.ClearAllByExpiration()
@@ -41,3 +64,8 @@ func (scheduler *ShortUrlScheduler) ClearShortUrlByExpiration() {
 
 	log.Info(msg.GetMessage("short-url.cron.end"))
 }
+
+// Elector returns the leader elector of the scheduler
+func (scheduler *ShortUrlScheduler) Elector() *redis.LeaderElector {
+	return scheduler.elector
+}
@@ -19,17 +19,21 @@ type WeatherSchedulerConfig struct {
 	RefreshInterval time.Duration
 }
 
-// WeatherScheduler handles scheduled weather monitoring updates with distributed locking
+// weatherSchedulerElection is the election of the instance running the weather monitoring cron
+const weatherSchedulerElection = "weather_monitoring_scheduler"
+
+// WeatherScheduler handles scheduled weather monitoring updates, only on the elected leader instance
 type WeatherScheduler struct {
 	cron        *cron.Cron
 	useCase     weather.UseCase
 	redisClient *redis.Client
 	config      *WeatherSchedulerConfig
+	elector     *redis.LeaderElector
 }
 
-// NewWeatherScheduler creates a new weather scheduler with distributed locking support
+// NewWeatherScheduler creates a new weather scheduler with leader election
 func NewWeatherScheduler(useCase weather.UseCase, redisClient *redis.Client, cronExpression string, lockTTL int, refreshInterval int) *WeatherScheduler {
-	return &WeatherScheduler{
+	scheduler := &WeatherScheduler{
 		cron:        cron.New(),
 		useCase:     useCase,
 		redisClient: redisClient,
@@ -39,59 +43,29 @@ func NewWeatherScheduler(useCase weather.UseCase, redisClient *redis.Client, cro
 			RefreshInterval: time.Duration(refreshInterval) * time.Second,
 		},
 	}
+	scheduler.elector = newSchedulerElector(redisClient, weatherSchedulerElection, "weather_schedules",
+		scheduler.getLockTTL(), scheduler.getRefreshInterval())
+	return scheduler
 }
 
-// InitWeatherScheduleTasks initializes weather schedule tasks with distributed locking
+// InitWeatherScheduleTasks schedules the weather monitoring update and campaigns for leadership.
+// The cron runs while this instance is leader, after losing leadership it campaigns again.
 func (s *WeatherScheduler) InitWeatherScheduleTasks(ctx context.Context) {
-	go func() {
-		// Create a scheduled task lock with persistent refresh
-		lock := redis.NewScheduledTaskLock(
-			s.redisClient,
-			"weather_monitoring_scheduler",
-			s.getLockTTL(),
-			s.getRefreshInterval(),
-			"weather_schedules",
-		)
-
-		err := lock.Lock(ctx)
-		if err != nil {
-			log.Errorf("Failed to acquire distributed lock, weather scheduler will not be initialized: %v", err)
-			return
-		}
-
-		// Start auto-refresh to maintain the lock indefinitely
-		refreshErrChan := lock.AutoRefresh(ctx)
-
-		// Get cron expression from config
-		cronExpression := s.config.CronExpression
-
-		// Schedule task to run at configured times (default: 02:00, 10:00, and 18:00 daily)
-		_, err = s.cron.AddFunc(cronExpression, s.ExecuteScheduledTask)
-
-		if err != nil {
-			log.Errorf("Failed to initialize weather scheduler, cron will not be started: %v", err)
-			return
-		}
-
-		// Start the scheduler
-		s.cron.Start()
-		log.Infof("Weather monitoring scheduler started successfully with cron expression: %s", cronExpression)
-
-		// Monitor auto-refresh errors and stop scheduler if refresh fails
-		err = <-refreshErrChan
-
-		// Stop the scheduler due to refresh failure or context cancellation
-		if s.cron != nil {
-			cronCtx := s.cron.Stop()
-			<-cronCtx.Done()
-		}
-
-		if err != nil {
-			log.Errorf("Weather monitoring scheduler stopped due to auto-refresh failure: %v", err)
-		} else {
-			log.Info("Weather monitoring scheduler stopped gracefully")
-		}
-	}()
+	// Get cron expression from config
+	cronExpression := s.config.CronExpression
+
+	// Schedule task to run at configured times (default: 02:00, 10:00, and 18:00 daily)
+	if _, err := s.cron.AddFunc(cronExpression, s.ExecuteScheduledTask); err != nil {
+		log.Errorf("Failed to initialize weather scheduler, cron will not be started: %v", err)
+		return
+	}
+
+	runCronWhileLeader(s.elector, s.cron)
+	if err := s.elector.Campaign(ctx); err != nil {
+		log.Errorf("Failed to campaign for weather scheduler leadership: %v", err)
+		return
+	}
+	log.Infof("Weather monitoring scheduler campaigning for leadership with cron expression: %s", cronExpression)
 }
 
 // ExecuteScheduledTask executes the city monitoring update
@@ -101,6 +75,10 @@ func (s *WeatherScheduler) ExecuteScheduledTask() {
 
 	log.Info("Weather monitoring scheduled task triggered", zap.String("request_id", requestID))
 
+	if !isLeader(s.elector, s.redisClient, requestID) {
+		return
+	}
+
 	// Execute the scheduled task
 	log.Info("Executing scheduled weather monitoring update for all cities", zap.String("request_id", requestID))
 	if err := s.useCase.UpdateAllCitiesMonitoringScheduled(requestID); err != nil {
@@ -111,14 +89,22 @@ func (s *WeatherScheduler) ExecuteScheduledTask() {
 	log.Info("Scheduled weather monitoring update completed successfully", zap.String("request_id", requestID))
 }
 
-// Stop gracefully stops the scheduler
+// Stop gracefully stops the scheduler and resigns leadership
 func (s *WeatherScheduler) Stop() {
+	if err := s.elector.Resign(context.Background()); err != nil {
+		log.Errorf("Failed to resign weather scheduler leadership: %v", err)
+	}
 	if s.cron != nil {
 		ctx := s.cron.Stop()
 		<-ctx.Done()
 	}
 }
 
+// Elector returns the leader elector of the scheduler
+func (s *WeatherScheduler) Elector() *redis.LeaderElector {
+	return s.elector
+}
+
 // Helper methods to get duration values from config
 func (s *WeatherScheduler) getLockTTL() time.Duration {
 	if s.config.LockTTL > 0 {
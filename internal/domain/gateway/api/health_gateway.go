@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go-api/internal/domain/model"
+	"go-api/pkg/http"
+)
+
+// HealthGateway reports the health of the external APIs
+type HealthGateway interface {
+	Health(ctx context.Context) model.ComponentHealthStatus
+}
+
+// CircuitBreakerHealthGateway reports the circuit breakers of the HTTP clients by host.
+// The component is DOWN while the circuit of a host is not closed.
+type CircuitBreakerHealthGateway struct{}
+
+var _ HealthGateway = (*CircuitBreakerHealthGateway)(nil)
+
+func NewCircuitBreakerHealthGateway() *CircuitBreakerHealthGateway {
+	return &CircuitBreakerHealthGateway{}
+}
+
+func (gateway *CircuitBreakerHealthGateway) Health(_ context.Context) model.ComponentHealthStatus {
+	breakers := http.GetCircuitBreakerStatus()
+	status := model.StatusUp
+	details := map[string]string{
+		"circuit_breakers_total": strconv.Itoa(len(breakers)),
+	}
+	for host, breaker := range breakers {
+		if breaker.State != http.CircuitClosed {
+			status = model.StatusDown
+		}
+		details[host+"_state"] = string(breaker.State)
+		details[host+"_requests"] = strconv.Itoa(breaker.Requests)
+		details[host+"_failures"] = strconv.Itoa(breaker.Failures)
+		details[host+"_failure_rate"] = strconv.FormatFloat(breaker.FailureRate, 'f', 2, 64)
+		if !breaker.OpenUntil.IsZero() {
+			details[host+"_open_until"] = breaker.OpenUntil.Format(time.RFC3339)
+		}
+		if breaker.LastSyncError != "" {
+			details[host+"_last_sync_error"] = breaker.LastSyncError
+		}
+	}
+
+	return model.ComponentHealthStatus{
+		Status:  status,
+		Details: details,
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"go-api/internal/domain/model/external"
+	"go-api/pkg/log"
+	"go-api/pkg/redis"
+)
+
+// limitedWeatherGateway caps the concurrent BrasilAPI calls of all instances with a Redis semaphore
+type limitedWeatherGateway struct {
+	WeatherGateway
+	semaphore *redis.Semaphore
+}
+
+var _ WeatherGateway = (*limitedWeatherGateway)(nil)
+
+// NewLimitedWeatherGateway wraps a WeatherGateway so that at most the semaphore limit of calls
+// run at the same time across all instances. Calls wait for a permit, and fail when none is
+// released within the semaphore retries. If Redis is unavailable calls are not limited.
+func NewLimitedWeatherGateway(delegate WeatherGateway, semaphore *redis.Semaphore) WeatherGateway {
+	return &limitedWeatherGateway{
+		WeatherGateway: delegate,
+		semaphore:      semaphore,
+	}
+}
+
+// SearchCities searches for cities by name
+func (w *limitedWeatherGateway) SearchCities(cityName string) ([]external.CitySearchResponse, error) {
+	return limitCall(w.semaphore, func() ([]external.CitySearchResponse, error) {
+		return w.WeatherGateway.SearchCities(cityName)
+	})
+}
+
+// GetWeatherForecast gets weather forecast for a city
+func (w *limitedWeatherGateway) GetWeatherForecast(cityCode int, days int) (*external.WeatherForecastResponse, error) {
+	return limitCall(w.semaphore, func() (*external.WeatherForecastResponse, error) {
+		return w.WeatherGateway.GetWeatherForecast(cityCode, days)
+	})
+}
+
+// GetWaveConditions gets wave conditions for a city
+func (w *limitedWeatherGateway) GetWaveConditions(cityCode int, days int) (*external.WaveConditionResponse, error) {
+	return limitCall(w.semaphore, func() (*external.WaveConditionResponse, error) {
+		return w.WeatherGateway.GetWaveConditions(cityCode, days)
+	})
+}
+
+// limitCall runs a call while holding a semaphore permit
+func limitCall[T any](semaphore *redis.Semaphore, call func() (T, error)) (T, error) {
+	ctx := context.Background()
+	permit, err := semaphore.Acquire(ctx)
+	if err != nil {
+		if errors.Is(err, redis.ErrSemaphoreFull) {
+			var zero T
+			return zero, err
+		}
+		log.Warnf("BrasilAPI concurrency limit unavailable, calling without a permit: %v", err)
+		return call()
+	}
+
+	defer func() {
+		if err := permit.Release(ctx); err != nil {
+			log.Warnf("Failed to release BrasilAPI permit: %v", err)
+		}
+	}()
+	return call()
+}
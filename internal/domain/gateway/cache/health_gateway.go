@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+
+	"go-api/internal/domain/model"
+	"go-api/pkg/redis"
+)
+
+// HealthGateway reports the health of a Redis component
+type HealthGateway interface {
+	Health(ctx context.Context) model.ComponentHealthStatus
+}
+
+// RedisHealthGateway reports the connectivity, operations and pool of the Redis client
+type RedisHealthGateway struct {
+	checker *redis.HealthChecker
+}
+
+var _ HealthGateway = (*RedisHealthGateway)(nil)
+
+func NewRedisHealthGateway(client *redis.Client) *RedisHealthGateway {
+	return &RedisHealthGateway{checker: redis.NewHealthChecker(client.GetClient(), client.GetConfig())}
+}
+
+func (gateway *RedisHealthGateway) Health(ctx context.Context) model.ComponentHealthStatus {
+	check := gateway.checker.HealthCheckContext(ctx)
+	return model.ComponentHealthStatus{
+		Status:  model.HealthStatus(check.Status),
+		Details: check.Details,
+	}
+}
+
+// LockHealthGateway reports the locks of the lock registry and whether this instance holds them.
+// Locks are informational, the component is always UP.
+type LockHealthGateway struct{}
+
+var _ HealthGateway = (*LockHealthGateway)(nil)
+
+func NewLockHealthGateway() *LockHealthGateway {
+	return &LockHealthGateway{}
+}
+
+func (gateway *LockHealthGateway) Health(_ context.Context) model.ComponentHealthStatus {
+	lockStatus := redis.GetLockStatus()
+	details := map[string]string{
+		"locks_total": strconv.Itoa(len(lockStatus)),
+	}
+	held := 0
+	for name, acquired := range lockStatus {
+		details[name+"_acquired"] = strconv.FormatBool(acquired)
+		if acquired {
+			held++
+		}
+	}
+	details["locks_held"] = strconv.Itoa(held)
+
+	return model.ComponentHealthStatus{
+		Status:  model.StatusUp,
+		Details: details,
+	}
+}
+
+// RateLimiterHealthGateway reports the usage of the registered rate limiters.
+// A limiter at capacity is working as intended, the component is always UP.
+type RateLimiterHealthGateway struct{}
+
+var _ HealthGateway = (*RateLimiterHealthGateway)(nil)
+
+func NewRateLimiterHealthGateway() *RateLimiterHealthGateway {
+	return &RateLimiterHealthGateway{}
+}
+
+func (gateway *RateLimiterHealthGateway) Health(ctx context.Context) model.ComponentHealthStatus {
+	metrics := redis.GetRateLimiterMetrics(ctx)
+	details := map[string]string{
+		"rate_limiters_total": strconv.Itoa(len(metrics)),
+	}
+	for name, limiterMetrics := range metrics {
+		for key, value := range limiterMetrics {
+			details[name+"_"+key] = value
+		}
+	}
+
+	return model.ComponentHealthStatus{
+		Status:  model.StatusUp,
+		Details: details,
+	}
+}
+
+// LeaderHealthGateway reports the leader election of a scheduler. A follower is healthy,
+// the component is DOWN only when the instance no longer campaigns.
+type LeaderHealthGateway struct {
+	elector *redis.LeaderElector
+}
+
+var _ HealthGateway = (*LeaderHealthGateway)(nil)
+
+func NewLeaderHealthGateway(elector *redis.LeaderElector) *LeaderHealthGateway {
+	return &LeaderHealthGateway{elector: elector}
+}
+
+func (gateway *LeaderHealthGateway) Health(ctx context.Context) model.ComponentHealthStatus {
+	check := gateway.elector.HealthCheck()
+	if leader, err := gateway.elector.Leader(ctx); err == nil {
+		check.Details["current_leader"] = leader
+	}
+
+	return model.ComponentHealthStatus{
+		Status:  model.HealthStatus(check.Status),
+		Details: check.Details,
+	}
+}
@@ -0,0 +1,8 @@
+package cache
+
+import "context"
+
+// InvalidationGateway deletes cached entries by tag, in every cache and every instance
+type InvalidationGateway interface {
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
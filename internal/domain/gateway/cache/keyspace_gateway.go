@@ -0,0 +1,19 @@
+package cache
+
+import (
+	"context"
+
+	"go-api/pkg/redis"
+)
+
+// KeyspaceGateway inspects and maintains the Redis keys of the caches, locks and rate limiters
+type KeyspaceGateway interface {
+	CacheNames() []string
+	CacheStats(ctx context.Context, cacheName string) (*redis.CacheKeyspaceStats, error)
+	InspectKey(ctx context.Context, key string) (*redis.KeyInfo, error)
+	FlushCache(ctx context.Context, cacheName string) (int64, error)
+	Locks() []redis.LockInfo
+	ForceReleaseLock(ctx context.Context, fullKey string) error
+	RateLimiters(ctx context.Context) map[string]map[string]string
+	ResetRateLimiter(ctx context.Context, cacheName string) (int64, error)
+}
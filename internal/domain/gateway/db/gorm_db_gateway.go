@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"go-api/internal/domain/model"
 	"gorm.io/gorm"
 )
@@ -15,7 +16,7 @@ func NewGormHealthDBGateway(db *gorm.DB) *GormHealthDBGateway {
 	return &GormHealthDBGateway{DB: db}
 }
 
-func (gateway *GormHealthDBGateway) Health() model.ComponentHealthStatus {
+func (gateway *GormHealthDBGateway) Health(ctx context.Context) model.ComponentHealthStatus {
 	sqlDB, err := gateway.DB.DB()
 
 	if err != nil {
@@ -27,7 +28,7 @@ func (gateway *GormHealthDBGateway) Health() model.ComponentHealthStatus {
 		}
 	}
 
-	err = sqlDB.Ping()
+	err = sqlDB.PingContext(ctx)
 	if err != nil {
 		return model.ComponentHealthStatus{
 			Status: model.StatusDown,
@@ -1,7 +1,10 @@
 package db
 
-import "go-api/internal/domain/model"
+import (
+	"context"
+	"go-api/internal/domain/model"
+)
 
 type HealthDBGateway interface {
-	Health() model.ComponentHealthStatus
+	Health(ctx context.Context) model.ComponentHealthStatus
 }
@@ -17,8 +17,8 @@ func NewSQLCHealthDBGateway(db *sql.DB) *SQLCHealthDBGateway {
 	return &SQLCHealthDBGateway{DB: db}
 }
 
-func (gateway *SQLCHealthDBGateway) Health() model.ComponentHealthStatus {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func (gateway *SQLCHealthDBGateway) Health(ctx context.Context) model.ComponentHealthStatus {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
 	err := gateway.DB.PingContext(ctx)
@@ -1,12 +1,13 @@
 package queue
 
 import (
+	"context"
 	"go-api/internal/domain/model"
 	"go-api/pkg/sqs"
 )
 
 type HealthGateway interface {
-	Health() model.ComponentHealthStatus
+	Health(ctx context.Context) model.ComponentHealthStatus
 	RegisterWorker(name string, worker *sqs.Worker)
 	UnregisterWorker(name string)
 }
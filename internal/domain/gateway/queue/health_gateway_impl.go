@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"context"
 	"go-api/internal/domain/model"
 	"go-api/pkg/sqs"
 	"strconv"
@@ -13,6 +14,7 @@ type QueueHealthGateway struct {
 }
 
 var _ HealthGateway = (*QueueHealthGateway)(nil)
+var _ MetricsGateway = (*QueueHealthGateway)(nil)
 
 func NewQueueHealthGateway() *QueueHealthGateway {
 	return &QueueHealthGateway{
@@ -33,7 +35,7 @@ func (gateway *QueueHealthGateway) UnregisterWorker(name string) {
 	delete(gateway.workers, name)
 }
 
-func (gateway *QueueHealthGateway) Health() model.ComponentHealthStatus {
+func (gateway *QueueHealthGateway) Health(ctx context.Context) model.ComponentHealthStatus {
 	gateway.mutex.RLock()
 	defer gateway.mutex.RUnlock()
 
@@ -53,7 +55,7 @@ func (gateway *QueueHealthGateway) Health() model.ComponentHealthStatus {
 	workersDown := 0
 
 	for name, worker := range gateway.workers {
-		workerHealth := worker.HealthCheck()
+		workerHealth := worker.HealthCheckContext(ctx)
 
 		if workerHealth.Status == sqs.StatusUp {
 			workersUp++
@@ -79,3 +81,15 @@ func (gateway *QueueHealthGateway) Health() model.ComponentHealthStatus {
 		Details: details,
 	}
 }
+
+// WorkerMetrics returns a metrics snapshot of every registered worker
+func (gateway *QueueHealthGateway) WorkerMetrics() []sqs.WorkerMetrics {
+	gateway.mutex.RLock()
+	defer gateway.mutex.RUnlock()
+
+	metrics := make([]sqs.WorkerMetrics, 0, len(gateway.workers))
+	for _, worker := range gateway.workers {
+		metrics = append(metrics, worker.Metrics())
+	}
+	return metrics
+}
@@ -0,0 +1,8 @@
+package queue
+
+import "go-api/pkg/sqs"
+
+// MetricsGateway exposes the metrics of the registered queue workers
+type MetricsGateway interface {
+	WorkerMetrics() []sqs.WorkerMetrics
+}
@@ -4,6 +4,18 @@ package queue
 type BatchMessage struct {
 	MessageID string `json:"messageId"`
 	Body      any    `json:"body"`
+	// MessageGroupID orders messages of the same group on FIFO queues
+	MessageGroupID string `json:"messageGroupId,omitempty"`
+	// DeduplicationID discards duplicates sent within the FIFO deduplication interval
+	DeduplicationID string `json:"deduplicationId,omitempty"`
+	// CorrelationID links the message to the request that produced it
+	CorrelationID string `json:"correlationId,omitempty"`
+	// SchemaVersion identifies the version of the body schema
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+	// Attributes are additional message attributes
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// DelaySeconds postpones the delivery of the message (standard queues only)
+	DelaySeconds int32 `json:"delaySeconds,omitempty"`
 }
 
 // BatchResult represents the result of a batch send operation
@@ -12,7 +24,24 @@ type BatchResult struct {
 	Failed     []string `json:"failed"`
 }
 
+// SendOptions represents per-message options used when sending a single message
+type SendOptions struct {
+	// MessageGroupID orders messages of the same group on FIFO queues
+	MessageGroupID string
+	// DeduplicationID discards duplicates sent within the FIFO deduplication interval
+	DeduplicationID string
+	// CorrelationID links the message to the request that produced it
+	CorrelationID string
+	// SchemaVersion identifies the version of the body schema
+	SchemaVersion string
+	// Attributes are additional message attributes
+	Attributes map[string]string
+	// DelaySeconds postpones the delivery of the message (standard queues only)
+	DelaySeconds int32
+}
+
 type Sender interface {
 	SendMessage(queueName string, body any) error
+	SendMessageWithOptions(queueName string, body any, opts SendOptions) error
 	SendMessageBatch(queueName string, messages []BatchMessage) (*BatchResult, error)
 }
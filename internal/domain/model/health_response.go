@@ -7,6 +7,8 @@ const (
 	StatusUp      HealthStatus = "UP"
 	StatusDown    HealthStatus = "DOWN"
 	StatusUnknown HealthStatus = "UNKNOWN"
+	// StatusDegraded means the application serves requests while a non-critical component is not UP
+	StatusDegraded HealthStatus = "DEGRADED"
 )
 
 // ComponentHealthStatus represents the health check structure of a application component
@@ -15,9 +17,8 @@ type ComponentHealthStatus struct {
 	Details map[string]string `json:"details"`
 }
 
-// HealthResponse represents the health check response of all application
+// HealthResponse represents the health check response of all application, with the status of each component by name
 type HealthResponse struct {
-	Status   HealthStatus          `json:"status"`
-	Database ComponentHealthStatus `json:"database"`
-	Queue    ComponentHealthStatus `json:"queue"`
+	Status     HealthStatus                     `json:"status"`
+	Components map[string]ComponentHealthStatus `json:"components,omitempty"`
 }
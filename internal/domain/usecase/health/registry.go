@@ -0,0 +1,303 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-api/internal/domain/model"
+)
+
+// Criticality defines how a contributor that is not UP affects the application status
+type Criticality int
+
+const (
+	// Critical contributors take the application DOWN and are checked by the readiness probe
+	Critical Criticality = iota
+	// DegradedOnly contributors only take the application to DEGRADED
+	DegradedOnly
+)
+
+// String returns the name of the criticality
+func (c Criticality) String() string {
+	switch c {
+	case Critical:
+		return "critical"
+	case DegradedOnly:
+		return "degraded-only"
+	default:
+		return fmt.Sprintf("Criticality(%d)", int(c))
+	}
+}
+
+// Contributor reports the health of an application component
+type Contributor interface {
+	Health(ctx context.Context) model.ComponentHealthStatus
+}
+
+// ContributorFunc adapts a function to a Contributor
+type ContributorFunc func(ctx context.Context) model.ComponentHealthStatus
+
+// Health calls the function
+func (f ContributorFunc) Health(ctx context.Context) model.ComponentHealthStatus {
+	return f(ctx)
+}
+
+// ContributorOptions represents options for a registered contributor
+type ContributorOptions struct {
+	// Timeout is how long the contributor has to report, it is reported DOWN after that
+	Timeout time.Duration
+	// Criticality defines how the contributor affects the application status
+	Criticality Criticality
+	// RefreshInterval is how long a result is reused before the contributor is checked again, 0 disables caching
+	RefreshInterval time.Duration
+}
+
+// NewContributorOptions creates new contributor options with default values
+func NewContributorOptions() *ContributorOptions {
+	return &ContributorOptions{
+		Timeout:         2 * time.Second,
+		Criticality:     Critical,
+		RefreshInterval: 0,
+	}
+}
+
+// WithTimeout sets how long the contributor has to report
+func (o *ContributorOptions) WithTimeout(timeout time.Duration) *ContributorOptions {
+	if timeout <= 0 {
+		panic(fmt.Sprintf("invalid timeout: %v, must be positive", timeout))
+	}
+	o.Timeout = timeout
+	return o
+}
+
+// WithCriticality sets how the contributor affects the application status
+func (o *ContributorOptions) WithCriticality(criticality Criticality) *ContributorOptions {
+	if criticality != Critical && criticality != DegradedOnly {
+		panic(fmt.Sprintf("invalid criticality: %v", criticality))
+	}
+	o.Criticality = criticality
+	return o
+}
+
+// WithRefreshInterval sets how long a result is reused before the contributor is checked again
+func (o *ContributorOptions) WithRefreshInterval(interval time.Duration) *ContributorOptions {
+	if interval < 0 {
+		panic(fmt.Sprintf("invalid refresh interval: %v, must be non-negative", interval))
+	}
+	o.RefreshInterval = interval
+	return o
+}
+
+// Registry holds the named health contributors of the application
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*contributorEntry
+}
+
+// NewRegistry creates an empty health contributor registry
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]*contributorEntry),
+	}
+}
+
+// Register adds a contributor under a name, replacing any contributor with the same name
+func (r *Registry) Register(name string, contributor Contributor, opts *ContributorOptions) {
+	if name == "" {
+		panic("contributor name cannot be empty")
+	}
+	if contributor == nil {
+		panic(fmt.Sprintf("contributor %s cannot be nil", name))
+	}
+	if opts == nil {
+		opts = NewContributorOptions()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[name] = &contributorEntry{contributor: contributor, opts: *opts}
+}
+
+// Unregister removes the contributor with the name
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, name)
+}
+
+// Names returns the names of the registered contributors, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check runs the contributors concurrently, only the critical ones when criticalOnly is set.
+// The application is DOWN when a critical contributor is DOWN, and DEGRADED when a critical
+// contributor is DEGRADED or a degraded-only contributor is not UP.
+func (r *Registry) Check(ctx context.Context, criticalOnly bool) model.HealthResponse {
+	r.mu.RLock()
+	entries := make(map[string]*contributorEntry, len(r.entries))
+	for name, entry := range r.entries {
+		if criticalOnly && entry.opts.Criticality != Critical {
+			continue
+		}
+		entries[name] = entry
+	}
+	r.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	components := make(map[string]model.ComponentHealthStatus, len(entries))
+	for name, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			componentHealth := entry.check(ctx)
+
+			mu.Lock()
+			components[name] = componentHealth
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	overallStatus := model.StatusUp
+	for name, componentHealth := range components {
+		status := aggregateStatus(entries[name].opts.Criticality, componentHealth.Status)
+		if status == model.StatusDown || (status == model.StatusDegraded && overallStatus == model.StatusUp) {
+			overallStatus = status
+		}
+	}
+
+	return model.HealthResponse{
+		Status:     overallStatus,
+		Components: components,
+	}
+}
+
+// aggregateStatus returns the application status caused by a contributor status
+func aggregateStatus(criticality Criticality, status model.HealthStatus) model.HealthStatus {
+	switch {
+	case status == model.StatusUp:
+		return model.StatusUp
+	case criticality == Critical && status != model.StatusDegraded:
+		return model.StatusDown
+	default:
+		return model.StatusDegraded
+	}
+}
+
+// contributorEntry caches the last result of a contributor and shares a check in progress
+// between concurrent callers
+type contributorEntry struct {
+	contributor Contributor
+	opts        ContributorOptions
+
+	mu        sync.Mutex
+	result    model.ComponentHealthStatus
+	checkedAt time.Time
+	inFlight  chan struct{}
+	// running is set while a call to the contributor has not returned, even after its timeout
+	running bool
+}
+
+// check returns the cached result if it is fresh, otherwise waits for a new check
+func (e *contributorEntry) check(ctx context.Context) model.ComponentHealthStatus {
+	e.mu.Lock()
+	if e.opts.RefreshInterval > 0 && !e.checkedAt.IsZero() && time.Since(e.checkedAt) < e.opts.RefreshInterval {
+		result := e.result
+		e.mu.Unlock()
+		return result
+	}
+	if e.inFlight == nil {
+		e.inFlight = make(chan struct{})
+		// The check outlives a cancelled request, so its result can still be cached for the next one
+		go e.refresh(context.WithoutCancel(ctx))
+	}
+	done := e.inFlight
+	e.mu.Unlock()
+
+	select {
+	case <-done:
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return e.result
+	case <-ctx.Done():
+		return downStatus(ctx.Err().Error())
+	}
+}
+
+// refresh calls the contributor within its timeout and stores the result
+func (e *contributorEntry) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+	defer cancel()
+
+	e.mu.Lock()
+	// A contributor ignoring its context is not called again until it returns
+	alreadyRunning := e.running
+	e.running = true
+	e.mu.Unlock()
+
+	var result model.ComponentHealthStatus
+	if alreadyRunning {
+		result = downStatus("previous check still running")
+	} else {
+		result = e.call(ctx)
+	}
+
+	// The details are copied, the contributor may keep its own map
+	details := make(map[string]string, len(result.Details)+1)
+	for key, value := range result.Details {
+		details[key] = value
+	}
+	details["criticality"] = e.opts.Criticality.String()
+	result.Details = details
+
+	e.mu.Lock()
+	e.result = result
+	e.checkedAt = time.Now()
+	close(e.inFlight)
+	e.inFlight = nil
+	e.mu.Unlock()
+}
+
+// call runs the contributor, reporting it DOWN if it panics or does not return before the context ends
+func (e *contributorEntry) call(ctx context.Context) model.ComponentHealthStatus {
+	resultCh := make(chan model.ComponentHealthStatus, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resultCh <- downStatus(fmt.Sprintf("panic: %v", r))
+			}
+			e.mu.Lock()
+			e.running = false
+			e.mu.Unlock()
+		}()
+		resultCh <- e.contributor.Health(ctx)
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result
+	case <-ctx.Done():
+		return downStatus(fmt.Sprintf("timed out after %v", e.opts.Timeout))
+	}
+}
+
+// downStatus returns a DOWN status with the reason as last error
+func downStatus(reason string) model.ComponentHealthStatus {
+	return model.ComponentHealthStatus{
+		Status:  model.StatusDown,
+		Details: map[string]string{"last_error": reason},
+	}
+}
@@ -0,0 +1,187 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api/internal/domain/model"
+)
+
+// contributor is a registered contributor of a test
+type contributor struct {
+	name        string
+	criticality Criticality
+	status      model.HealthStatus
+}
+
+// newTestRegistry registers contributors reporting a fixed status
+func newTestRegistry(contributors ...contributor) *Registry {
+	registry := NewRegistry()
+	for _, c := range contributors {
+		status := c.status
+		registry.Register(c.name, ContributorFunc(func(context.Context) model.ComponentHealthStatus {
+			return model.ComponentHealthStatus{Status: status}
+		}), NewContributorOptions().WithCriticality(c.criticality))
+	}
+	return registry
+}
+
+func TestRegistryCheckCombinesByCriticality(t *testing.T) {
+	tests := []struct {
+		name         string
+		contributors []contributor
+		want         model.HealthStatus
+	}{
+		{name: "no contributors", want: model.StatusUp},
+		{
+			name: "all up",
+			contributors: []contributor{
+				{"database", Critical, model.StatusUp},
+				{"cache", DegradedOnly, model.StatusUp},
+			},
+			want: model.StatusUp,
+		},
+		{
+			name: "non-critical down",
+			contributors: []contributor{
+				{"database", Critical, model.StatusUp},
+				{"cache", DegradedOnly, model.StatusDown},
+			},
+			want: model.StatusDegraded,
+		},
+		{
+			name: "non-critical unknown",
+			contributors: []contributor{
+				{"database", Critical, model.StatusUp},
+				{"cache", DegradedOnly, model.StatusUnknown},
+			},
+			want: model.StatusDegraded,
+		},
+		{
+			name: "critical degraded",
+			contributors: []contributor{
+				{"database", Critical, model.StatusDegraded},
+				{"cache", DegradedOnly, model.StatusUp},
+			},
+			want: model.StatusDegraded,
+		},
+		{
+			name: "critical down",
+			contributors: []contributor{
+				{"database", Critical, model.StatusDown},
+				{"cache", DegradedOnly, model.StatusUp},
+			},
+			want: model.StatusDown,
+		},
+		{
+			name: "critical unknown",
+			contributors: []contributor{
+				{"database", Critical, model.StatusUnknown},
+			},
+			want: model.StatusDown,
+		},
+		{
+			name: "critical down with non-critical down",
+			contributors: []contributor{
+				{"database", Critical, model.StatusDown},
+				{"cache", DegradedOnly, model.StatusDown},
+				{"queue", Critical, model.StatusDegraded},
+			},
+			want: model.StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := newTestRegistry(tt.contributors...).Check(context.Background(), false)
+			if response.Status != tt.want {
+				t.Errorf("Check() status = %s, want %s", response.Status, tt.want)
+			}
+			if len(response.Components) != len(tt.contributors) {
+				t.Fatalf("Check() components = %v, want %d", response.Components, len(tt.contributors))
+			}
+			for _, c := range tt.contributors {
+				component := response.Components[c.name]
+				if component.Status != c.status || component.Details["criticality"] != c.criticality.String() {
+					t.Errorf("component %s = %+v, want %s with criticality %s", c.name, component, c.status, c.criticality)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryCheckCriticalOnly(t *testing.T) {
+	registry := newTestRegistry(
+		contributor{"database", Critical, model.StatusUp},
+		contributor{"cache", DegradedOnly, model.StatusDown},
+	)
+
+	response := registry.Check(context.Background(), true)
+	if response.Status != model.StatusUp {
+		t.Errorf("Check() status = %s, want %s", response.Status, model.StatusUp)
+	}
+	if _, ok := response.Components["cache"]; ok || len(response.Components) != 1 {
+		t.Errorf("Check() components = %v, want the critical contributors only", response.Components)
+	}
+}
+
+func TestRegistryCheckFailingContributors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contributor ContributorFunc
+		wantError   string
+	}{
+		{
+			name: "timeout",
+			contributor: func(ctx context.Context) model.ComponentHealthStatus {
+				<-ctx.Done()
+				return model.ComponentHealthStatus{Status: model.StatusUp}
+			},
+			wantError: "timed out after 20ms",
+		},
+		{
+			name: "panic",
+			contributor: func(context.Context) model.ComponentHealthStatus {
+				panic("connection pool closed")
+			},
+			wantError: "panic: connection pool closed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			registry.Register("database", tt.contributor, NewContributorOptions().WithTimeout(20*time.Millisecond))
+
+			response := registry.Check(context.Background(), false)
+			component := response.Components["database"]
+			if response.Status != model.StatusDown || component.Status != model.StatusDown || component.Details["last_error"] != tt.wantError {
+				t.Errorf("Check() = %+v, want DOWN with last error %q", response, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestRegistryCheckReusesFreshResults(t *testing.T) {
+	var calls int32
+	registry := NewRegistry()
+	registry.Register("database", ContributorFunc(func(context.Context) model.ComponentHealthStatus {
+		atomic.AddInt32(&calls, 1)
+		return model.ComponentHealthStatus{Status: model.StatusUp}
+	}), NewContributorOptions().WithRefreshInterval(50*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		registry.Check(context.Background(), false)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("contributor calls within the refresh interval = %d, want 1", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	registry.Check(context.Background(), false)
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("contributor calls after the refresh interval = %d, want 2", got)
+	}
+}
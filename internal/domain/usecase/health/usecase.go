@@ -1,7 +1,17 @@
 package health
 
-import "go-api/internal/domain/model"
+import (
+	"context"
+
+	"go-api/internal/domain/model"
+)
 
 type UseCase interface {
-	CheckHealth() model.HealthResponse
+	// CheckHealth reports every contributor, the application is DOWN when a critical contributor is DOWN
+	// and DEGRADED when only degraded-only contributors are not UP
+	CheckHealth(ctx context.Context) model.HealthResponse
+	// CheckLiveness reports whether the application is running, ignoring its dependencies
+	CheckLiveness(ctx context.Context) model.HealthResponse
+	// CheckReadiness reports the critical contributors, required to serve requests
+	CheckReadiness(ctx context.Context) model.HealthResponse
 }
@@ -1,37 +1,33 @@
 package health
 
 import (
-	"go-api/internal/domain/gateway/db"
-	"go-api/internal/domain/gateway/queue"
+	"context"
+
 	"go-api/internal/domain/model"
 )
 
 type healthUseCase struct {
-	dbGateway    db.HealthDBGateway
-	queueGateway queue.HealthGateway
+	registry *Registry
 }
 
 var _ UseCase = (*healthUseCase)(nil)
 
-func NewHealthUseCase(dbGateway db.HealthDBGateway, queueGateway queue.HealthGateway) UseCase {
+func NewHealthUseCase(registry *Registry) UseCase {
 	return &healthUseCase{
-		dbGateway:    dbGateway,
-		queueGateway: queueGateway,
+		registry: registry,
 	}
 }
 
-func (useCase *healthUseCase) CheckHealth() model.HealthResponse {
-	dbHealth := useCase.dbGateway.Health()
-	queueHealth := useCase.queueGateway.Health()
-
-	overallStatus := model.StatusUp
-	if dbHealth.Status != model.StatusUp || queueHealth.Status != model.StatusUp {
-		overallStatus = model.StatusDown
-	}
+func (useCase *healthUseCase) CheckHealth(ctx context.Context) model.HealthResponse {
+	return useCase.registry.Check(ctx, false)
+}
 
+func (useCase *healthUseCase) CheckLiveness(_ context.Context) model.HealthResponse {
 	return model.HealthResponse{
-		Status:   overallStatus,
-		Database: dbHealth,
-		Queue:    queueHealth,
+		Status: model.StatusUp,
 	}
 }
+
+func (useCase *healthUseCase) CheckReadiness(ctx context.Context) model.HealthResponse {
+	return useCase.registry.Check(ctx, true)
+}
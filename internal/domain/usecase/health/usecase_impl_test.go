@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"testing"
+
+	"go-api/internal/domain/model"
+)
+
+func TestHealthUseCase(t *testing.T) {
+	tests := []struct {
+		name          string
+		contributors  []contributor
+		wantHealth    model.HealthStatus
+		wantReadiness model.HealthStatus
+	}{
+		{
+			name: "all up",
+			contributors: []contributor{
+				{"database", Critical, model.StatusUp},
+				{"cache", DegradedOnly, model.StatusUp},
+			},
+			wantHealth:    model.StatusUp,
+			wantReadiness: model.StatusUp,
+		},
+		{
+			name: "non-critical down",
+			contributors: []contributor{
+				{"database", Critical, model.StatusUp},
+				{"cache", DegradedOnly, model.StatusDown},
+			},
+			wantHealth:    model.StatusDegraded,
+			wantReadiness: model.StatusUp,
+		},
+		{
+			name: "critical down",
+			contributors: []contributor{
+				{"database", Critical, model.StatusDown},
+				{"cache", DegradedOnly, model.StatusUp},
+			},
+			wantHealth:    model.StatusDown,
+			wantReadiness: model.StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			useCase := NewHealthUseCase(newTestRegistry(tt.contributors...))
+
+			if health := useCase.CheckHealth(ctx); health.Status != tt.wantHealth {
+				t.Errorf("CheckHealth() status = %s, want %s", health.Status, tt.wantHealth)
+			}
+			if readiness := useCase.CheckReadiness(ctx); readiness.Status != tt.wantReadiness {
+				t.Errorf("CheckReadiness() status = %s, want %s", readiness.Status, tt.wantReadiness)
+			}
+			// Liveness ignores the dependencies
+			if liveness := useCase.CheckLiveness(ctx); liveness.Status != model.StatusUp || len(liveness.Components) != 0 {
+				t.Errorf("CheckLiveness() = %+v, want UP without components", liveness)
+			}
+		})
+	}
+}
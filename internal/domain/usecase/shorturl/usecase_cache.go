@@ -0,0 +1,127 @@
+package shorturl
+
+import (
+	"context"
+	"go-api/internal/domain/entity"
+	"go-api/internal/domain/model"
+	"go-api/pkg/log"
+	"go-api/pkg/redis"
+)
+
+// CacheName is the cache holding short URLs by hash, its TTL comes from app.cache.redis.ttl.short-url
+const CacheName = "short-url"
+
+// allShortUrlsCacheTag is carried by every cached short URL, so expiration cleanups can evict them all
+const allShortUrlsCacheTag = "short-url:all"
+
+// ShortUrlCacheTag returns the cache tag carried by the cached entries of a short URL
+func ShortUrlCacheTag(id string) string {
+	return "short-url:" + id
+}
+
+// cachedShortUrlUseCase is a read-through caching decorator for UseCase.
+// FindByHash is cached by hash and every write evicts the affected entries.
+type cachedShortUrlUseCase struct {
+	UseCase
+	cache *redis.TypedCache[string, entity.ShortUrl]
+}
+
+var _ UseCase = (*cachedShortUrlUseCase)(nil)
+
+// NewCachedShortUrlUseCase wraps a UseCase with a Redis cache. localCache may be nil.
+func NewCachedShortUrlUseCase(delegate UseCase, client *redis.Client, localCache *redis.LocalCache) UseCase {
+	return &cachedShortUrlUseCase{
+		UseCase: delegate,
+		cache: redis.NewTypedCache[string, entity.ShortUrl](client,
+			redis.NewCacheOptions().
+				WithCacheName(CacheName).
+				WithLocalCache(localCache),
+			redis.StringKeyEncoder[string](), redis.JSONCodec),
+	}
+}
+
+func (uc *cachedShortUrlUseCase) FindByHash(hash string) (*entity.ShortUrl, error) {
+	shortUrl, err := uc.cache.GetOrSetWithTags(context.Background(), hash, func() (entity.ShortUrl, []string, error) {
+		shortUrl, err := uc.UseCase.FindByHash(hash)
+		if err != nil {
+			return entity.ShortUrl{}, nil, err
+		}
+		return *shortUrl, []string{ShortUrlCacheTag(shortUrl.ID), allShortUrlsCacheTag}, nil
+	})
+	return redis.ReadThrough(CacheName, &shortUrl, err, func() (*entity.ShortUrl, error) {
+		return uc.UseCase.FindByHash(hash)
+	})
+}
+
+func (uc *cachedShortUrlUseCase) Create(dto model.CreateShortUrlDTO) (*entity.ShortUrl, error) {
+	createdShortUrl, err := uc.UseCase.Create(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.evictHash(createdShortUrl.Hash)
+	return createdShortUrl, nil
+}
+
+func (uc *cachedShortUrlUseCase) UpdateByHash(hash string, dto model.UpdateShortUrlDTO) (*entity.ShortUrl, error) {
+	updatedShortUrl, err := uc.UseCase.UpdateByHash(hash, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.evictHash(hash)
+	uc.evictTags(ShortUrlCacheTag(updatedShortUrl.ID))
+	return updatedShortUrl, nil
+}
+
+func (uc *cachedShortUrlUseCase) UpdateByID(id string, dto model.UpdateShortUrlDTO) (*entity.ShortUrl, error) {
+	updatedShortUrl, err := uc.UseCase.UpdateByID(id, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.evictTags(ShortUrlCacheTag(id))
+	return updatedShortUrl, nil
+}
+
+func (uc *cachedShortUrlUseCase) ClearAllByExpiration() error {
+	if err := uc.UseCase.ClearAllByExpiration(); err != nil {
+		return err
+	}
+
+	uc.evictTags(allShortUrlsCacheTag)
+	return nil
+}
+
+func (uc *cachedShortUrlUseCase) DeleteByID(id string) error {
+	if err := uc.UseCase.DeleteByID(id); err != nil {
+		return err
+	}
+
+	uc.evictTags(ShortUrlCacheTag(id))
+	return nil
+}
+
+func (uc *cachedShortUrlUseCase) DeleteByHash(hash string) error {
+	if err := uc.UseCase.DeleteByHash(hash); err != nil {
+		return err
+	}
+
+	uc.evictHash(hash)
+	return nil
+}
+
+// evictHash removes the cached short URL of a hash.
+// Failures are logged, the write already succeeded and the entry expires with its TTL.
+func (uc *cachedShortUrlUseCase) evictHash(hash string) {
+	if err := uc.cache.Delete(context.Background(), hash); err != nil {
+		log.Errorf("Failed to evict cached short URL %s: %v", hash, err)
+	}
+}
+
+// evictTags removes the cached short URLs carrying the tags
+func (uc *cachedShortUrlUseCase) evictTags(tags ...string) {
+	if err := uc.cache.InvalidateTags(context.Background(), tags...); err != nil {
+		log.Errorf("Failed to invalidate cached short URLs %v: %v", tags, err)
+	}
+}
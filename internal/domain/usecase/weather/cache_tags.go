@@ -0,0 +1,13 @@
+package weather
+
+import "strings"
+
+// CityCacheTag returns the cache tag carried by every cached lookup or page that includes the city
+func CityCacheTag(cityID string) string {
+	return "city:" + cityID
+}
+
+// StateCacheTag returns the cache tag carried by every cached lookup or page filtered by the state
+func StateCacheTag(state string) string {
+	return "state:" + strings.ToUpper(state)
+}
@@ -0,0 +1,136 @@
+package weather
+
+import (
+	"context"
+	"go-api/internal/domain/entity"
+	"go-api/internal/domain/model"
+	"go-api/pkg/log"
+	"go-api/pkg/redis"
+	"strings"
+)
+
+// CacheName is the cache holding city lookups and pages, its TTL comes from app.cache.redis.ttl.weather
+const CacheName = "weather"
+
+// cityPagesCacheTag is carried by every cached page of cities, so creating or removing
+// a city evicts all pages whose content or totals change
+const cityPagesCacheTag = "weather:pages"
+
+// cityLookupKey identifies a cached FindCityByNameAndState result
+type cityLookupKey struct {
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	FromDate string `json:"fromDate"`
+}
+
+// cityPageKey identifies a cached FindAllCities page
+type cityPageKey struct {
+	Page       int    `json:"page"`
+	Size       int    `json:"size"`
+	NamePrefix string `json:"namePrefix"`
+	State      string `json:"state"`
+	FromDate   string `json:"fromDate"`
+}
+
+// cachedWeatherUseCase is a read-through caching decorator for UseCase.
+// City lookups and pages are cached with tags for every city they include, so
+// UpdateCityMonitoring, which invalidates CityCacheTag, evicts every entry with that city.
+type cachedWeatherUseCase struct {
+	UseCase
+	cities *redis.TypedCache[cityLookupKey, entity.City]
+	pages  *redis.TypedCache[cityPageKey, model.Page[entity.City]]
+}
+
+var _ UseCase = (*cachedWeatherUseCase)(nil)
+
+// NewCachedWeatherUseCase wraps a UseCase with a Redis cache. localCache may be nil.
+// Cities carry all their forecasts, so values are stored as gzip-compressed JSON.
+func NewCachedWeatherUseCase(delegate UseCase, client *redis.Client, localCache *redis.LocalCache) UseCase {
+	opts := redis.NewCacheOptions().
+		WithCacheName(CacheName).
+		WithLocalCache(localCache)
+
+	return &cachedWeatherUseCase{
+		UseCase: delegate,
+		cities: redis.NewTypedCache[cityLookupKey, entity.City](client, opts,
+			prefixedKeyEncoder[cityLookupKey]("city:"), redis.GzipJSONCodec),
+		pages: redis.NewTypedCache[cityPageKey, model.Page[entity.City]](client, opts,
+			prefixedKeyEncoder[cityPageKey]("page:"), redis.GzipJSONCodec),
+	}
+}
+
+// prefixedKeyEncoder encodes keys as JSON behind a prefix, keeping lookups and pages apart in the same cache
+func prefixedKeyEncoder[K any](prefix string) redis.KeyEncoder[K] {
+	encode := redis.JSONKeyEncoder[K]()
+	return func(key K) string {
+		return prefix + encode(key)
+	}
+}
+
+// FindAllCities returns a paginated list of cities with filters
+func (uc *cachedWeatherUseCase) FindAllCities(page int, size int, namePrefix string, state string, fromDate string) (*model.Page[entity.City], error) {
+	key := cityPageKey{Page: page, Size: size, NamePrefix: namePrefix, State: strings.ToUpper(state), FromDate: fromDate}
+
+	cached, err := uc.pages.GetOrSetWithTags(context.Background(), key, func() (model.Page[entity.City], []string, error) {
+		citiesPage, err := uc.UseCase.FindAllCities(page, size, namePrefix, state, fromDate)
+		if err != nil {
+			return model.Page[entity.City]{}, nil, err
+		}
+
+		tags := []string{cityPagesCacheTag}
+		for _, city := range citiesPage.Content {
+			tags = append(tags, CityCacheTag(city.ID))
+		}
+		return *citiesPage, tags, nil
+	})
+
+	return redis.ReadThrough(CacheName, &cached, err, func() (*model.Page[entity.City], error) {
+		return uc.UseCase.FindAllCities(page, size, namePrefix, state, fromDate)
+	})
+}
+
+// FindCityByNameAndState searches for a single city by name, state and optional date
+func (uc *cachedWeatherUseCase) FindCityByNameAndState(name string, state string, fromDate string) (*entity.City, error) {
+	key := cityLookupKey{Name: strings.ToLower(name), State: strings.ToUpper(state), FromDate: fromDate}
+
+	cached, err := uc.cities.GetOrSetWithTags(context.Background(), key, func() (entity.City, []string, error) {
+		city, err := uc.UseCase.FindCityByNameAndState(name, state, fromDate)
+		if err != nil {
+			return entity.City{}, nil, err
+		}
+		return *city, []string{CityCacheTag(city.ID), StateCacheTag(city.State)}, nil
+	})
+
+	return redis.ReadThrough(CacheName, &cached, err, func() (*entity.City, error) {
+		return uc.UseCase.FindCityByNameAndState(name, state, fromDate)
+	})
+}
+
+// CreateCityMonitoring searches for a city in the API, saves it and enqueues it
+func (uc *cachedWeatherUseCase) CreateCityMonitoring(cityName string, state string) error {
+	if err := uc.UseCase.CreateCityMonitoring(cityName, state); err != nil {
+		return err
+	}
+
+	uc.evictTags(cityPagesCacheTag)
+	return nil
+}
+
+// RemoveCityMonitoring deletes a city and all its related weather and wave conditions
+func (uc *cachedWeatherUseCase) RemoveCityMonitoring(name string, state string) error {
+	if err := uc.UseCase.RemoveCityMonitoring(name, state); err != nil {
+		return err
+	}
+
+	// The removed city ID is unknown here, lookups of its state are evicted instead
+	uc.evictTags(cityPagesCacheTag, StateCacheTag(state))
+	return nil
+}
+
+// evictTags removes the cached entries carrying the tags.
+// Failures are logged, the write already succeeded and the entries expire with their TTL.
+func (uc *cachedWeatherUseCase) evictTags(tags ...string) {
+	if err := uc.pages.InvalidateTags(context.Background(), tags...); err != nil {
+		log.Errorf("Failed to invalidate cached cities %v: %v", tags, err)
+	}
+}
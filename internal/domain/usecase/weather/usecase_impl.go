@@ -1,10 +1,12 @@
 package weather
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"go-api/internal/domain/entity"
 	"go-api/internal/domain/gateway/api"
+	"go-api/internal/domain/gateway/cache"
 	"go-api/internal/domain/gateway/db"
 	"go-api/internal/domain/gateway/queue"
 	"go-api/internal/domain/model"
@@ -17,22 +19,25 @@ import (
 )
 
 type weatherUseCase struct {
-	queueName   string
-	batchSize   int
-	apiGateway  api.WeatherGateway
-	dbGateway   db.CityGateway
-	queueSender queue.Sender
+	queueName         string
+	batchSize         int
+	apiGateway        api.WeatherGateway
+	dbGateway         db.CityGateway
+	queueSender       queue.Sender
+	cacheInvalidation cache.InvalidationGateway
 }
 
 var _ UseCase = (*weatherUseCase)(nil)
 
-func NewWeatherUseCase(queueName string, batchSize int, queueSender queue.Sender, apiGateway api.WeatherGateway, dbGateway db.CityGateway) UseCase {
+// NewWeatherUseCase creates the weather use case. cacheInvalidation may be nil when no cache is used.
+func NewWeatherUseCase(queueName string, batchSize int, queueSender queue.Sender, apiGateway api.WeatherGateway, dbGateway db.CityGateway, cacheInvalidation cache.InvalidationGateway) UseCase {
 	return &weatherUseCase{
-		queueName:   queueName,
-		batchSize:   batchSize,
-		queueSender: queueSender,
-		apiGateway:  apiGateway,
-		dbGateway:   dbGateway,
+		queueName:         queueName,
+		batchSize:         batchSize,
+		queueSender:       queueSender,
+		apiGateway:        apiGateway,
+		dbGateway:         dbGateway,
+		cacheInvalidation: cacheInvalidation,
 	}
 }
 
@@ -135,7 +140,10 @@ func (uc *weatherUseCase) CreateCityMonitoring(cityName string, state string) er
 	}
 
 	// Enqueue the saved city
-	err = uc.queueSender.SendMessage(uc.queueName, savedCity)
+	err = uc.queueSender.SendMessageWithOptions(uc.queueName, savedCity, queue.SendOptions{
+		MessageGroupID: cityMessageGroupID(*savedCity),
+		SchemaVersion:  citySchemaVersion,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to enqueue saved city: %w", err)
 	}
@@ -165,8 +173,10 @@ func (uc *weatherUseCase) UpdateAllCitiesMonitoring() {
 		messages := make([]queue.BatchMessage, len(cities))
 		for i, city := range cities {
 			messages[i] = queue.BatchMessage{
-				MessageID: fmt.Sprintf("city-%s-%d", city.ID, page),
-				Body:      city,
+				MessageID:      fmt.Sprintf("city-%s-%d", city.ID, page),
+				Body:           city,
+				MessageGroupID: cityMessageGroupID(city),
+				SchemaVersion:  citySchemaVersion,
 			}
 		}
 
@@ -233,9 +243,14 @@ func (uc *weatherUseCase) UpdateAllCitiesMonitoringScheduled(requestID string) e
 		// Prepare batch messages
 		messages := make([]queue.BatchMessage, len(cities))
 		for i, city := range cities {
+			messageID := fmt.Sprintf("scheduled-%s-city-%s", requestID, city.ID)
 			messages[i] = queue.BatchMessage{
-				MessageID: fmt.Sprintf("scheduled-%s-city-%s", requestID, city.ID),
-				Body:      city,
+				MessageID:       messageID,
+				Body:            city,
+				MessageGroupID:  cityMessageGroupID(city),
+				DeduplicationID: messageID,
+				CorrelationID:   requestID,
+				SchemaVersion:   citySchemaVersion,
 			}
 		}
 
@@ -289,6 +304,15 @@ func (uc *weatherUseCase) UpdateAllCitiesMonitoringScheduled(requestID string) e
 	return nil
 }
 
+// citySchemaVersion is the schema version of the city messages sent to the weather queue
+const citySchemaVersion = "1"
+
+// cityMessageGroupID returns the queue message group of a city, so updates of the same city
+// are processed in order when the weather queue is a FIFO queue
+func cityMessageGroupID(city entity.City) string {
+	return "city-" + city.ID
+}
+
 // UpdateCityMonitoring updates weather and wave conditions for a city in parallel
 func (uc *weatherUseCase) UpdateCityMonitoring(city entity.City) error {
 	if city.Code == "" {
@@ -302,6 +326,11 @@ func (uc *weatherUseCase) UpdateCityMonitoring(city entity.City) error {
 
 	weatherErr, waveErr := uc.updateWeatherAndWaveInParallel(city, cityCode)
 
+	// Either update may have changed the stored forecasts, so evict every cached page or lookup with the city
+	if weatherErr == nil || waveErr == nil {
+		uc.invalidateCityCache(city)
+	}
+
 	// Weather is mandatory, wave conditions are optional
 	if weatherErr != nil {
 		return fmt.Errorf("weather update failed: %w", weatherErr)
@@ -318,6 +347,18 @@ func (uc *weatherUseCase) UpdateCityMonitoring(city entity.City) error {
 	return nil
 }
 
+// invalidateCityCache evicts the cached entries that include the city.
+// A failed invalidation is logged, cached entries then expire with their TTL.
+func (uc *weatherUseCase) invalidateCityCache(city entity.City) {
+	if uc.cacheInvalidation == nil {
+		return
+	}
+
+	if err := uc.cacheInvalidation.InvalidateTags(context.Background(), CityCacheTag(city.ID)); err != nil {
+		log.Warnf("Failed to invalidate cache for city %s: %v", city.Name, err)
+	}
+}
+
 // updateWeatherAndWaveInParallel updates weather and wave conditions in parallel
 func (uc *weatherUseCase) updateWeatherAndWaveInParallel(city entity.City, cityCode int) (error, error) {
 	var wg sync.WaitGroup
@@ -1,7 +1,44 @@
 package aws
 
-import "github.com/aws/aws-sdk-go-v2/service/sqs"
+import (
+	"fmt"
+	"go-api/pkg/redis"
+	"go-api/pkg/resource"
+	pkgsqs "go-api/pkg/sqs"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
 
 func NewSqsClient() *sqs.Client {
 	return sqs.NewFromConfig(Config)
 }
+
+// NewQueueClient creates the SQS client selected by app.cloud.sqs.provider.
+// The "memory" provider keeps queues in process for offline development,
+// the "redis" provider keeps them in Redis job queues so they are shared by local instances,
+// any other value uses AWS (or LocalStack through app.cloud.aws-endpoint).
+func NewQueueClient(redisClient *redis.Client) pkgsqs.Client {
+	switch resource.GetString("app.cloud.sqs.provider") {
+	case "memory":
+		return pkgsqs.NewMemoryClient().WithAutoCreateQueues(true)
+	case "redis":
+		return redis.NewJobQueueSQSClient(redisClient.GetClient(), redis.NewJobQueueConfig().
+			WithNamespace(resource.GetString("app.cloud.sqs.redis.namespace")).
+			WithMaxAttempts(resource.GetInt("app.cloud.sqs.redis.max-receive-count")))
+	default:
+		return NewSqsClient()
+	}
+}
+
+// NewPayloadStore creates the blob store used to offload large SQS payloads
+// from the app.cloud.sqs.payload-store configuration. It returns nil when offloading is disabled.
+func NewPayloadStore() (pkgsqs.BlobStore, error) {
+	switch resource.GetString("app.cloud.sqs.payload-store.type") {
+	case "file":
+		return pkgsqs.NewFileBlobStore(resource.GetString("app.cloud.sqs.payload-store.dir"))
+	case "", "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported payload store type %q", resource.GetString("app.cloud.sqs.payload-store.type"))
+	}
+}
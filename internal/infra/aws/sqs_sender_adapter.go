@@ -13,9 +13,9 @@ type SQSSenderAdapter struct {
 var _ queue.Sender = (*SQSSenderAdapter)(nil)
 
 // NewSQSSenderAdapter creates a new SQS sender adapter that implements domain interface
-func NewSQSSenderAdapter(sqsClient sqs.SQSClient) queue.Sender {
+func NewSQSSenderAdapter(sqsClient sqs.SQSClient, config *sqs.SenderConfig) queue.Sender {
 	return &SQSSenderAdapter{
-		sqsSender: sqs.NewSender(sqsClient),
+		sqsSender: sqs.NewSenderWithConfig(sqsClient, config),
 	}
 }
 
@@ -24,14 +24,32 @@ func (adapter *SQSSenderAdapter) SendMessage(queueName string, body any) error {
 	return adapter.sqsSender.SendMessage(queueName, body)
 }
 
+// SendMessageWithOptions implements the domain interface
+func (adapter *SQSSenderAdapter) SendMessageWithOptions(queueName string, body any, opts queue.SendOptions) error {
+	return adapter.sqsSender.SendMessageWithOptions(queueName, body, &sqs.SendOptions{
+		MessageGroupID:  opts.MessageGroupID,
+		DeduplicationID: opts.DeduplicationID,
+		CorrelationID:   opts.CorrelationID,
+		SchemaVersion:   opts.SchemaVersion,
+		Attributes:      opts.Attributes,
+		DelaySeconds:    opts.DelaySeconds,
+	})
+}
+
 // SendMessageBatch implements the domain interface by converting types
 func (adapter *SQSSenderAdapter) SendMessageBatch(queueName string, messages []queue.BatchMessage) (*queue.BatchResult, error) {
 	// Convert domain types to SQS types
 	sqsMessages := make([]sqs.BatchMessage, len(messages))
 	for i, msg := range messages {
 		sqsMessages[i] = sqs.BatchMessage{
-			MessageID: msg.MessageID,
-			Body:      msg.Body,
+			MessageID:       msg.MessageID,
+			Body:            msg.Body,
+			MessageGroupID:  msg.MessageGroupID,
+			DeduplicationID: msg.DeduplicationID,
+			CorrelationID:   msg.CorrelationID,
+			SchemaVersion:   msg.SchemaVersion,
+			Attributes:      msg.Attributes,
+			DelaySeconds:    msg.DelaySeconds,
 		}
 	}
 
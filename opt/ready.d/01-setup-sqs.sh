@@ -14,6 +14,7 @@ echo "Creating SQS queue: $QUEUE_NAME"
 
 # Create the SQS queue (simplified - no custom attributes for now)
 awslocal sqs create-queue --queue-name="$QUEUE_NAME"
+awslocal sqs create-queue --queue-name="$QUEUE_NAME.fifo" --attributes FifoQueue=true
 awslocal sqs create-queue --queue-name="test-queue"
 
 echo "✅ Queue '$QUEUE_NAME' created successfully"
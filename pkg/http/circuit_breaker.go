@@ -0,0 +1,440 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed lets every request through while the failure rate is counted
+	CircuitClosed CircuitState = "CLOSED"
+	// CircuitOpen rejects every request until the cool-down elapses
+	CircuitOpen CircuitState = "OPEN"
+	// CircuitHalfOpen lets a limited number of trial requests through to decide whether to close or open again
+	CircuitHalfOpen CircuitState = "HALF_OPEN"
+)
+
+// ErrCircuitOpen is returned without sending the request when the circuit of the host is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitStateStore shares the open circuits of the hosts between instances, so they all trip together
+type CircuitStateStore interface {
+	// Open opens the circuit of the host for every instance during the cool-down
+	Open(ctx context.Context, host string, coolDown time.Duration) error
+	// OpenFor returns how long the circuit of the host stays open, 0 when it is not open
+	OpenFor(ctx context.Context, host string) (time.Duration, error)
+	// Close closes the circuit of the host for every instance
+	Close(ctx context.Context, host string) error
+}
+
+// CircuitBreakerConfig represents the circuit breaker configuration, applied to each host
+type CircuitBreakerConfig struct {
+	// FailureRateThreshold is the failure rate, between 0 and 1, that opens the circuit
+	FailureRateThreshold float64
+	// MinimumRequests is the number of requests in the window before the failure rate is evaluated
+	MinimumRequests int
+	// Window is the rolling window of the failure rate
+	Window time.Duration
+	// WindowBuckets is the number of buckets the window is split into, the oldest bucket expires at once
+	WindowBuckets int
+	// CoolDown is how long the circuit stays open before trial requests are let through
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of trial requests of a half-open circuit, it closes when all of them succeed
+	HalfOpenRequests int
+	// FailureStatusCodes are the HTTP status codes counted as failures besides connection errors, 5xx by default
+	FailureStatusCodes []int
+	// SharedState shares the open circuits between instances, nil keeps the state local
+	SharedState CircuitStateStore
+	// SyncInterval is how often a closed circuit checks the shared state
+	SyncInterval time.Duration
+	// OnStateChange is called on every state change, after the logger
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// withDefaults returns a copy of the configuration with the zero values replaced by defaults
+func (c CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if c.FailureRateThreshold <= 0 || c.FailureRateThreshold > 1 {
+		c.FailureRateThreshold = 0.5
+	}
+	if c.MinimumRequests <= 0 {
+		c.MinimumRequests = 10
+	}
+	if c.Window <= 0 {
+		c.Window = 60 * time.Second
+	}
+	if c.WindowBuckets <= 0 {
+		c.WindowBuckets = 10
+	}
+	if c.CoolDown <= 0 {
+		c.CoolDown = 30 * time.Second
+	}
+	if c.HalfOpenRequests <= 0 {
+		c.HalfOpenRequests = 1
+	}
+	if c.SyncInterval <= 0 {
+		c.SyncInterval = time.Second
+	}
+	return c
+}
+
+// CircuitBreakerStatus represents the state and rolling window counts of the circuit of a host
+type CircuitBreakerStatus struct {
+	Host        string
+	State       CircuitState
+	Requests    int
+	Failures    int
+	FailureRate float64
+	// OpenUntil is when an open circuit lets trial requests through, zero when it is not open
+	OpenUntil time.Time
+	// LastSyncError is the last error of the shared state, empty when it is reachable or not used
+	LastSyncError string
+}
+
+// CircuitBreakerRegistry tracks the circuit breakers of every client for health check
+type CircuitBreakerRegistry struct {
+	breakers map[string]*circuitBreaker
+	mu       sync.RWMutex
+}
+
+// Global circuit breaker registry
+var circuitBreakerRegistry = &CircuitBreakerRegistry{
+	breakers: make(map[string]*circuitBreaker),
+}
+
+// register registers the circuit breaker of a host, replacing the breaker of another client to the same host
+func (cbr *CircuitBreakerRegistry) register(breaker *circuitBreaker) {
+	cbr.mu.Lock()
+	defer cbr.mu.Unlock()
+	cbr.breakers[breaker.host] = breaker
+}
+
+// GetCircuitBreakerStatus returns the status of all registered circuit breakers by host
+func (cbr *CircuitBreakerRegistry) GetCircuitBreakerStatus() map[string]CircuitBreakerStatus {
+	cbr.mu.RLock()
+	defer cbr.mu.RUnlock()
+
+	status := make(map[string]CircuitBreakerStatus, len(cbr.breakers))
+	for host, breaker := range cbr.breakers {
+		status[host] = breaker.status()
+	}
+	return status
+}
+
+// GetCircuitBreakerStatus returns the status of the circuit breakers of every client by host
+func GetCircuitBreakerStatus() map[string]CircuitBreakerStatus {
+	return circuitBreakerRegistry.GetCircuitBreakerStatus()
+}
+
+// circuitBreakers holds the circuit breakers of a client by host
+type circuitBreakers struct {
+	config   CircuitBreakerConfig
+	logger   HTTPLogger
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newCircuitBreakers(config CircuitBreakerConfig, logger HTTPLogger) *circuitBreakers {
+	return &circuitBreakers{
+		config:   config.withDefaults(),
+		logger:   logger,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// get returns the circuit breaker of the host of a URL, creating it on first use
+func (cbs *circuitBreakers) get(rawURL string) *circuitBreaker {
+	host := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+	breaker, ok := cbs.breakers[host]
+	if !ok {
+		breaker = newCircuitBreaker(host, &cbs.config, cbs.logger)
+		cbs.breakers[host] = breaker
+		circuitBreakerRegistry.register(breaker)
+	}
+	return breaker
+}
+
+// status returns the status of the circuit breakers of the client by host
+func (cbs *circuitBreakers) status() map[string]CircuitBreakerStatus {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	status := make(map[string]CircuitBreakerStatus, len(cbs.breakers))
+	for host, breaker := range cbs.breakers {
+		status[host] = breaker.status()
+	}
+	return status
+}
+
+// circuitBucket counts the requests of a slice of the rolling window
+type circuitBucket struct {
+	start    time.Time
+	requests int
+	failures int
+}
+
+// circuitTransition is a state change, reported once the breaker is unlocked
+type circuitTransition struct {
+	from, to CircuitState
+	requests int
+	failures int
+}
+
+// circuitBreaker tracks the failure rate of a host and rejects its requests while the circuit is open
+type circuitBreaker struct {
+	host   string
+	config *CircuitBreakerConfig
+	logger HTTPLogger
+
+	mu        sync.Mutex
+	state     CircuitState
+	openUntil time.Time
+	buckets   []circuitBucket
+	// generation changes on every state change, so a request started before it is not counted after it
+	generation       uint64
+	halfOpenInFlight int
+	halfOpenSuccess  int
+	lastSync         time.Time
+	lastSyncError    string
+}
+
+func newCircuitBreaker(host string, config *CircuitBreakerConfig, logger HTTPLogger) *circuitBreaker {
+	return &circuitBreaker{
+		host:    host,
+		config:  config,
+		logger:  logger,
+		state:   CircuitClosed,
+		buckets: make([]circuitBucket, config.WindowBuckets),
+	}
+}
+
+// allow returns the generation to record the request with, or ErrCircuitOpen if the request must not be sent
+func (b *circuitBreaker) allow() (uint64, error) {
+	b.syncSharedState()
+
+	b.mu.Lock()
+	now := time.Now()
+	var transition *circuitTransition
+	if b.state == CircuitOpen && !now.Before(b.openUntil) {
+		transition = b.setState(CircuitHalfOpen)
+	}
+
+	var err error
+	switch b.state {
+	case CircuitOpen:
+		err = fmt.Errorf("%w: %s until %s", ErrCircuitOpen, b.host, b.openUntil.Format(time.RFC3339))
+	case CircuitHalfOpen:
+		if b.halfOpenInFlight >= b.config.HalfOpenRequests-b.halfOpenSuccess {
+			err = fmt.Errorf("%w: %s is half-open with trial requests in flight", ErrCircuitOpen, b.host)
+		} else {
+			b.halfOpenInFlight++
+		}
+	}
+	generation := b.generation
+	b.mu.Unlock()
+
+	b.notify(transition)
+	return generation, err
+}
+
+// record counts the outcome of a request allowed in the generation
+func (b *circuitBreaker) record(generation uint64, failure bool) {
+	b.mu.Lock()
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+
+	var transition *circuitTransition
+	switch b.state {
+	case CircuitClosed:
+		bucket := b.currentBucket(time.Now())
+		bucket.requests++
+		if failure {
+			bucket.failures++
+		}
+		requests, failures := b.counts(time.Now())
+		if requests >= b.config.MinimumRequests && float64(failures)/float64(requests) >= b.config.FailureRateThreshold {
+			transition = b.setState(CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		b.halfOpenInFlight--
+		if failure {
+			transition = b.setState(CircuitOpen)
+		} else if b.halfOpenSuccess++; b.halfOpenSuccess >= b.config.HalfOpenRequests {
+			transition = b.setState(CircuitClosed)
+		}
+	}
+	b.mu.Unlock()
+
+	b.publish(transition)
+	b.notify(transition)
+}
+
+// setState changes the state and resets the counts of the previous state, b.mu must be held
+func (b *circuitBreaker) setState(state CircuitState) *circuitTransition {
+	requests, failures := b.counts(time.Now())
+	transition := &circuitTransition{from: b.state, to: state, requests: requests, failures: failures}
+
+	b.state = state
+	b.generation++
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccess = 0
+	b.openUntil = time.Time{}
+	if state == CircuitOpen {
+		b.openUntil = time.Now().Add(b.config.CoolDown)
+	}
+	if state == CircuitClosed {
+		for i := range b.buckets {
+			b.buckets[i] = circuitBucket{}
+		}
+	}
+	return transition
+}
+
+// currentBucket returns the bucket of the time, resetting it if it holds an expired slice, b.mu must be held
+func (b *circuitBreaker) currentBucket(now time.Time) *circuitBucket {
+	bucketSize := b.config.Window / time.Duration(len(b.buckets))
+	start := now.Truncate(bucketSize)
+	bucket := &b.buckets[int(start.UnixNano()/int64(bucketSize))%len(b.buckets)]
+	if !bucket.start.Equal(start) {
+		*bucket = circuitBucket{start: start}
+	}
+	return bucket
+}
+
+// counts returns the requests and failures of the rolling window, b.mu must be held
+func (b *circuitBreaker) counts(now time.Time) (int, int) {
+	requests, failures := 0, 0
+	for _, bucket := range b.buckets {
+		if now.Sub(bucket.start) < b.config.Window {
+			requests += bucket.requests
+			failures += bucket.failures
+		}
+	}
+	return requests, failures
+}
+
+// isFailure reports whether the outcome of a request counts as a failure of the host.
+// Connection errors and timeouts are failures, and so are the failure status codes.
+func (b *circuitBreaker) isFailure(statusCode int, err error) bool {
+	// The caller gave up on the request, the host did not fail
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	if len(b.config.FailureStatusCodes) == 0 {
+		return statusCode >= 500
+	}
+	for _, code := range b.config.FailureStatusCodes {
+		if statusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// syncSharedState opens a closed circuit when another instance opened it in the shared state
+func (b *circuitBreaker) syncSharedState() {
+	if b.config.SharedState == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if b.state != CircuitClosed || time.Since(b.lastSync) < b.config.SyncInterval {
+		b.mu.Unlock()
+		return
+	}
+	b.lastSync = time.Now()
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	openFor, err := b.config.SharedState.OpenFor(ctx, b.host)
+
+	b.mu.Lock()
+	var transition *circuitTransition
+	b.lastSyncError = ""
+	if err != nil {
+		b.lastSyncError = err.Error()
+	} else if openFor > 0 && b.state == CircuitClosed {
+		transition = b.setState(CircuitOpen)
+		b.openUntil = time.Now().Add(openFor)
+	}
+	b.mu.Unlock()
+
+	b.notify(transition)
+}
+
+// publish shares the circuit opened or closed by this instance, a shared state failure keeps the local state
+func (b *circuitBreaker) publish(transition *circuitTransition) {
+	if transition == nil || b.config.SharedState == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var err error
+	switch transition.to {
+	case CircuitOpen:
+		err = b.config.SharedState.Open(ctx, b.host, b.config.CoolDown)
+	case CircuitClosed:
+		err = b.config.SharedState.Close(ctx, b.host)
+	}
+
+	b.mu.Lock()
+	b.lastSyncError = ""
+	if err != nil {
+		b.lastSyncError = err.Error()
+	}
+	b.mu.Unlock()
+}
+
+// notify reports a state change to the logger and the state change hook
+func (b *circuitBreaker) notify(transition *circuitTransition) {
+	if transition == nil {
+		return
+	}
+	if b.logger != nil {
+		b.logger.LogCircuitStateChange(b.host, transition.from, transition.to, transition.requests, transition.failures)
+	}
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.host, transition.from, transition.to)
+	}
+}
+
+// status returns the state and rolling window counts of the breaker
+func (b *circuitBreaker) status() CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	requests, failures := b.counts(time.Now())
+	status := CircuitBreakerStatus{
+		Host:          b.host,
+		State:         b.state,
+		Requests:      requests,
+		Failures:      failures,
+		OpenUntil:     b.openUntil,
+		LastSyncError: b.lastSyncError,
+	}
+	if requests > 0 {
+		status.FailureRate = float64(failures) / float64(requests)
+	}
+	return status
+}
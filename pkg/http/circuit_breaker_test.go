@@ -0,0 +1,284 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryCircuitStateStore is a CircuitStateStore shared by the breakers of a test
+type memoryCircuitStateStore struct {
+	mu        sync.Mutex
+	openUntil map[string]time.Time
+	err       error
+}
+
+func newMemoryCircuitStateStore() *memoryCircuitStateStore {
+	return &memoryCircuitStateStore{openUntil: make(map[string]time.Time)}
+}
+
+func (s *memoryCircuitStateStore) Open(ctx context.Context, host string, coolDown time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.openUntil[host] = time.Now().Add(coolDown)
+	return nil
+}
+
+func (s *memoryCircuitStateStore) OpenFor(ctx context.Context, host string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	return max(time.Until(s.openUntil[host]), 0), nil
+}
+
+func (s *memoryCircuitStateStore) Close(ctx context.Context, host string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.openUntil, host)
+	return nil
+}
+
+// newTestCircuitBreaker creates the breaker of a host opening at a 50% failure rate over 4 requests
+func newTestCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	if config.FailureRateThreshold == 0 {
+		config.FailureRateThreshold = 0.5
+	}
+	if config.MinimumRequests == 0 {
+		config.MinimumRequests = 4
+	}
+	if config.CoolDown == 0 {
+		config.CoolDown = 50 * time.Millisecond
+	}
+	config = config.withDefaults()
+	return newCircuitBreaker("api.example.com", &config, nil)
+}
+
+// recordOutcomes sends requests through the breaker with the given outcomes
+func recordOutcomes(t *testing.T, breaker *circuitBreaker, failures ...bool) {
+	t.Helper()
+	for _, failure := range failures {
+		generation, err := breaker.allow()
+		if err != nil {
+			t.Fatalf("allow() error = %v", err)
+		}
+		breaker.record(generation, failure)
+	}
+}
+
+// openCircuit opens the circuit of a breaker and waits for its cool-down
+func openCircuit(t *testing.T, breaker *circuitBreaker) {
+	t.Helper()
+	recordOutcomes(t, breaker, true, true, true, true)
+	if state := breaker.status().State; state != CircuitOpen {
+		t.Fatalf("state after failures = %s, want %s", state, CircuitOpen)
+	}
+	time.Sleep(breaker.config.CoolDown + 10*time.Millisecond)
+}
+
+func TestCircuitBreakerFailureRateThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []bool
+		want     CircuitState
+	}{
+		{name: "below minimum requests", failures: []bool{true, true, true}, want: CircuitClosed},
+		{name: "below threshold", failures: []bool{true, false, false, false}, want: CircuitClosed},
+		{name: "at threshold", failures: []bool{true, false, true, false}, want: CircuitOpen},
+		{name: "above threshold", failures: []bool{false, true, true, true}, want: CircuitOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := newTestCircuitBreaker(CircuitBreakerConfig{})
+			recordOutcomes(t, breaker, tt.failures...)
+
+			status := breaker.status()
+			if status.State != tt.want {
+				t.Fatalf("state = %s, want %s", status.State, tt.want)
+			}
+			_, err := breaker.allow()
+			if wantOpen := tt.want == CircuitOpen; errors.Is(err, ErrCircuitOpen) != wantOpen {
+				t.Errorf("allow() error = %v, want rejected %v", err, wantOpen)
+			}
+			if tt.want == CircuitOpen && status.OpenUntil.IsZero() {
+				t.Error("OpenUntil of an open circuit is zero")
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	var transitions []CircuitState
+	breaker := newTestCircuitBreaker(CircuitBreakerConfig{
+		HalfOpenRequests: 2,
+		OnStateChange: func(host string, from, to CircuitState) {
+			transitions = append(transitions, to)
+		},
+	})
+	recordOutcomes(t, breaker, true, true, true, true)
+
+	if _, err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() during the cool-down error = %v, want %v", err, ErrCircuitOpen)
+	}
+	time.Sleep(breaker.config.CoolDown + 10*time.Millisecond)
+
+	// Only HalfOpenRequests trial requests are let through
+	first, err := breaker.allow()
+	if err != nil {
+		t.Fatalf("allow() of the first trial error = %v", err)
+	}
+	second, err := breaker.allow()
+	if err != nil {
+		t.Fatalf("allow() of the second trial error = %v", err)
+	}
+	if _, err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() beyond the trial requests error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	breaker.record(first, false)
+	if state := breaker.status().State; state != CircuitHalfOpen {
+		t.Fatalf("state after one successful trial = %s, want %s", state, CircuitHalfOpen)
+	}
+	if _, err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() with a trial in flight error = %v, want %v", err, ErrCircuitOpen)
+	}
+	breaker.record(second, false)
+
+	status := breaker.status()
+	if status.State != CircuitClosed || status.Requests != 0 {
+		t.Errorf("status after the successful trials = %+v, want closed with an empty window", status)
+	}
+	want := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transitions = %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	breaker := newTestCircuitBreaker(CircuitBreakerConfig{})
+	openCircuit(t, breaker)
+
+	generation, err := breaker.allow()
+	if err != nil {
+		t.Fatalf("allow() of the trial error = %v", err)
+	}
+	breaker.record(generation, true)
+
+	if state := breaker.status().State; state != CircuitOpen {
+		t.Errorf("state after a failed trial = %s, want %s", state, CircuitOpen)
+	}
+	if _, err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() after a failed trial error = %v, want %v", err, ErrCircuitOpen)
+	}
+}
+
+func TestCircuitBreakerDropsStaleOutcomes(t *testing.T) {
+	breaker := newTestCircuitBreaker(CircuitBreakerConfig{})
+
+	// A slow request is sent while the circuit is closed
+	slow, err := breaker.allow()
+	if err != nil {
+		t.Fatalf("allow() error = %v", err)
+	}
+	openCircuit(t, breaker)
+	trial, err := breaker.allow()
+	if err != nil {
+		t.Fatalf("allow() of the trial error = %v", err)
+	}
+
+	// Its success says nothing about the host since the circuit opened, it must not close it
+	breaker.record(slow, false)
+	if state := breaker.status().State; state != CircuitHalfOpen {
+		t.Fatalf("state after a stale outcome = %s, want %s", state, CircuitHalfOpen)
+	}
+	breaker.record(trial, false)
+	if state := breaker.status().State; state != CircuitClosed {
+		t.Errorf("state after the trial = %s, want %s", state, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerSharedState(t *testing.T) {
+	store := newMemoryCircuitStateStore()
+	config := CircuitBreakerConfig{SharedState: store, SyncInterval: time.Millisecond}
+	first := newTestCircuitBreaker(config)
+	second := newTestCircuitBreaker(config)
+
+	// The circuit opened by an instance opens on the others at their next sync
+	recordOutcomes(t, first, true, true, true, true)
+	if openFor, err := store.OpenFor(context.Background(), "api.example.com"); err != nil || openFor <= 0 {
+		t.Fatalf("shared OpenFor() = %v, %v, want the circuit open", openFor, err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if _, err := second.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() of the other instance error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if state := second.status().State; state != CircuitOpen {
+		t.Errorf("state of the other instance = %s, want %s", state, CircuitOpen)
+	}
+
+	// Closing after a successful trial closes the shared circuit too
+	time.Sleep(first.config.CoolDown + 10*time.Millisecond)
+	generation, err := first.allow()
+	if err != nil {
+		t.Fatalf("allow() of the trial error = %v", err)
+	}
+	first.record(generation, false)
+	if openFor, err := store.OpenFor(context.Background(), "api.example.com"); err != nil || openFor != 0 {
+		t.Errorf("shared OpenFor() after closing = %v, %v, want closed", openFor, err)
+	}
+
+	// An unreachable shared state keeps the local state and is reported
+	store.err = errors.New("connection refused")
+	time.Sleep(2 * time.Millisecond)
+	if _, err := first.allow(); err != nil {
+		t.Errorf("allow() with the shared state down error = %v, want allowed", err)
+	}
+	if status := first.status(); status.State != CircuitClosed || status.LastSyncError != "connection refused" {
+		t.Errorf("status with the shared state down = %+v, want closed with the sync error", status)
+	}
+}
+
+func TestCircuitBreakerIsFailure(t *testing.T) {
+	connErr := &url.Error{Op: "Get", URL: "http://api.example.com", Err: errors.New("connection refused")}
+	tests := []struct {
+		name       string
+		codes      []int
+		statusCode int
+		err        error
+		want       bool
+	}{
+		{name: "connection error", err: connErr, want: true},
+		{name: "timeout", err: &url.Error{Op: "Get", URL: "http://api.example.com", Err: context.DeadlineExceeded}, want: true},
+		{name: "canceled by the caller", err: &url.Error{Op: "Get", URL: "http://api.example.com", Err: context.Canceled}},
+		{name: "server error", statusCode: 503, want: true},
+		{name: "client error", statusCode: 404},
+		{name: "success", statusCode: 200},
+		{name: "configured status code", codes: []int{429}, statusCode: 429, want: true},
+		{name: "server error not configured", codes: []int{429}, statusCode: 503},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := newTestCircuitBreaker(CircuitBreakerConfig{FailureStatusCodes: tt.codes})
+			if got := breaker.isFailure(tt.statusCode, tt.err); got != tt.want {
+				t.Errorf("isFailure(%d, %v) = %v, want %v", tt.statusCode, tt.err, got, tt.want)
+			}
+		})
+	}
+}
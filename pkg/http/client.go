@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -46,6 +47,7 @@ type Client struct {
 	defaultContentType string
 	defaultBackoff     *BackoffConfig
 	logger             HTTPLogger
+	circuitBreakers    *circuitBreakers
 }
 
 // ClientOptions represents the configuration options for the HTTP client.
@@ -61,6 +63,8 @@ type ClientOptions struct {
 	ReadTimeout         time.Duration
 	DefaultBackoff      *BackoffConfig
 	Logger              HTTPLogger
+	// CircuitBreaker enables a circuit breaker per host, nil disables it
+	CircuitBreaker *CircuitBreakerConfig
 }
 
 // NewHttpClient creates a new HTTP client with the given base URL and configuration options.
@@ -101,6 +105,11 @@ func NewHttpClient(baseURL string, opts ClientOptions) *Client {
 		}
 	}
 
+	var breakers *circuitBreakers
+	if opts.CircuitBreaker != nil {
+		breakers = newCircuitBreakers(*opts.CircuitBreaker, opts.Logger)
+	}
+
 	return &Client{
 		baseURL:            strings.TrimRight(baseURL, "/"),
 		client:             client,
@@ -110,6 +119,7 @@ func NewHttpClient(baseURL string, opts ClientOptions) *Client {
 		defaultContentType: opts.DefaultContentType,
 		defaultBackoff:     opts.DefaultBackoff,
 		logger:             opts.Logger,
+		circuitBreakers:    breakers,
 	}
 }
 
@@ -118,6 +128,14 @@ func (hc *Client) Request() *Request {
 	return NewHttpClientRequest(hc)
 }
 
+// CircuitBreakerStatus returns the status of the circuit breakers of the client by host, empty when it has none
+func (hc *Client) CircuitBreakerStatus() map[string]CircuitBreakerStatus {
+	if hc.circuitBreakers == nil {
+		return map[string]CircuitBreakerStatus{}
+	}
+	return hc.circuitBreakers.status()
+}
+
 // Get sends a GET request to the specified path with optional query parameters, headers, and response types.
 // It returns the success response, error response, status code, and error if any.
 func (hc *Client) Get(path string, queryParams map[string]string, headers map[string]string, successResp any, errorResp any) (any, any, int, error) {
@@ -160,7 +178,7 @@ func (hc *Client) doRequestWithBackoff(method, path string, queryParams map[stri
 
 	// If no backoff configuration is available, execute request without retries
 	if backoffConfig == nil {
-		return hc.doRequest(method, path, queryParams, headers, body, successResp, errorResp)
+		return hc.doRequestWithCircuitBreaker(method, path, queryParams, headers, body, successResp, errorResp)
 	}
 
 	var lastSuccessResp, lastErrorResp any
@@ -168,10 +186,10 @@ func (hc *Client) doRequestWithBackoff(method, path string, queryParams map[stri
 	var lastErr error
 
 	// Execute the initial request
-	lastSuccessResp, lastErrorResp, lastStatusCode, lastErr = hc.doRequest(method, path, queryParams, headers, body, successResp, errorResp)
+	lastSuccessResp, lastErrorResp, lastStatusCode, lastErr = hc.doRequestWithCircuitBreaker(method, path, queryParams, headers, body, successResp, errorResp)
 
-	// Check if the request was successful or if we should not retry
-	if lastErr == nil || !hc.shouldRetry(lastStatusCode, backoffConfig.RetryStatusCodes) {
+	// Check if the request was successful or if we should not retry, an open circuit is not retried
+	if lastErr == nil || errors.Is(lastErr, ErrCircuitOpen) || !hc.shouldRetry(lastStatusCode, backoffConfig.RetryStatusCodes) {
 		return lastSuccessResp, lastErrorResp, lastStatusCode, lastErr
 	}
 
@@ -205,10 +223,10 @@ func (hc *Client) doRequestWithBackoff(method, path string, queryParams map[stri
 		time.Sleep(delay)
 
 		// Execute the retry request
-		lastSuccessResp, lastErrorResp, lastStatusCode, lastErr = hc.doRequest(method, path, queryParams, headers, body, successResp, errorResp)
+		lastSuccessResp, lastErrorResp, lastStatusCode, lastErr = hc.doRequestWithCircuitBreaker(method, path, queryParams, headers, body, successResp, errorResp)
 
 		// Check if the request was successful or if we should not retry
-		if lastErr == nil || !hc.shouldRetry(lastStatusCode, backoffConfig.RetryStatusCodes) {
+		if lastErr == nil || errors.Is(lastErr, ErrCircuitOpen) || !hc.shouldRetry(lastStatusCode, backoffConfig.RetryStatusCodes) {
 			return lastSuccessResp, lastErrorResp, lastStatusCode, lastErr
 		}
 
@@ -223,6 +241,24 @@ func (hc *Client) doRequestWithBackoff(method, path string, queryParams map[stri
 	return lastSuccessResp, lastErrorResp, lastStatusCode, lastErr
 }
 
+// doRequestWithCircuitBreaker sends the request through the circuit breaker of its host, if the client has one.
+// While the circuit is open the request is not sent and ErrCircuitOpen is returned.
+func (hc *Client) doRequestWithCircuitBreaker(method, path string, queryParams map[string]string, headers map[string]string, body any, successResp any, errorResp any) (any, any, int, error) {
+	if hc.circuitBreakers == nil {
+		return hc.doRequest(method, path, queryParams, headers, body, successResp, errorResp)
+	}
+
+	breaker := hc.circuitBreakers.get(hc.buildURL(path))
+	generation, err := breaker.allow()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	successResp, errorResp, statusCode, err := hc.doRequest(method, path, queryParams, headers, body, successResp, errorResp)
+	breaker.record(generation, breaker.isFailure(statusCode, err))
+	return successResp, errorResp, statusCode, err
+}
+
 // shouldRetry determines if a request should be retried based on the status code
 func (hc *Client) shouldRetry(statusCode int, retryStatusCodes []int) bool {
 	for _, code := range retryStatusCodes {
@@ -13,4 +13,7 @@ type HTTPLogger interface {
 	
 	// LogRequestRetry is called when backoff exists and a retry attempt is about to be made
 	LogRequestRetry(method, url string, headers map[string]string, body string, httpStatus int, responseBody string, latency int64, err error, retryCount, maxRetries int)
+	
+	// LogCircuitStateChange is called when the circuit breaker of a host changes state, with the counts of its rolling window
+	LogCircuitStateChange(host string, from, to CircuitState, requests, failures int)
 }
\ No newline at end of file
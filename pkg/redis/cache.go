@@ -3,10 +3,29 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"go-api/pkg/log"
 )
 
+// cacheMetaSuffix is appended to a cache key to build the key holding its freshness metadata
+const cacheMetaSuffix = "::__meta"
+
+// defaultLoadLockNamespace is the lock namespace used by GetOrSet when LoadLock has none
+const defaultLoadLockNamespace = "cache-load"
+
 // CacheOptions represents options for cache operations
 type CacheOptions struct {
 	// TTL is the time to live for the cached value
@@ -19,6 +38,14 @@ type CacheOptions struct {
 	Deserializer func([]byte, interface{}) error
 	// CacheName is the name of the cache for TTL lookup
 	CacheName string
+	// StaleTTL is how long GetOrSet keeps serving an expired value while one goroutine refreshes it
+	StaleTTL time.Duration
+	// EarlyExpirationBeta enables probabilistic early expiration in GetOrSet (0 disables, 1 is the usual value)
+	EarlyExpirationBeta float64
+	// LoadLock enables a distributed lock around GetOrSet recomputes, so only one instance runs the setter
+	LoadLock *LockOptions
+	// LocalCache is an optional in-process tier checked before Redis
+	LocalCache *LocalCache
 	// ttlSet indicates whether TTL was explicitly set
 	ttlSet bool
 }
@@ -63,12 +90,64 @@ func (co *CacheOptions) WithDeserializer(deserializer func([]byte, interface{})
 	return co
 }
 
+// WithCodec stores values with a Codec behind a payload header naming the codec,
+// so values written with a previous codec, or as plain JSON, keep being readable
+func (co *CacheOptions) WithCodec(codec Codec) *CacheOptions {
+	if codec == nil {
+		panic("codec must not be nil")
+	}
+	co.Serializer = func(value interface{}) ([]byte, error) {
+		return EncodeWithHeader(codec, value)
+	}
+	co.Deserializer = DecodeWithHeader
+	return co
+}
+
 // WithCacheName sets the cache name for TTL lookup
 func (co *CacheOptions) WithCacheName(cacheName string) *CacheOptions {
 	co.CacheName = cacheName
 	return co
 }
 
+// WithStaleWhileRevalidate makes GetOrSet serve expired values for up to staleTTL
+// while a single goroutine refreshes them in background
+func (co *CacheOptions) WithStaleWhileRevalidate(staleTTL time.Duration) *CacheOptions {
+	if staleTTL < 0 {
+		panic(fmt.Sprintf("invalid stale TTL: %v, must be non-negative", staleTTL))
+	}
+	co.StaleTTL = staleTTL
+	return co
+}
+
+// WithEarlyExpiration enables probabilistic early expiration in GetOrSet.
+// Values are recomputed before their TTL with a probability that grows as the
+// expiration approaches and with the time the setter took, avoiding synchronized
+// recomputes when many keys expire together. Higher beta values expire earlier.
+func (co *CacheOptions) WithEarlyExpiration(beta float64) *CacheOptions {
+	if beta < 0 {
+		panic(fmt.Sprintf("invalid early expiration beta: %v, must be non-negative", beta))
+	}
+	co.EarlyExpirationBeta = beta
+	return co
+}
+
+// WithDistributedLoad protects GetOrSet recomputes with a distributed Lock, so only
+// one instance calls the setter for a key while the others wait for its value
+func (co *CacheOptions) WithDistributedLoad(lockOpts *LockOptions) *CacheOptions {
+	if lockOpts == nil {
+		lockOpts = DefaultLockOptions()
+	}
+	co.LoadLock = lockOpts
+	return co
+}
+
+// WithLocalCache places an in-process tier in front of Redis. The same LocalCache can be
+// shared by several caches, and writes through any of them are broadcast to the other instances.
+func (co *CacheOptions) WithLocalCache(localCache *LocalCache) *CacheOptions {
+	co.LocalCache = localCache
+	return co
+}
+
 // DefaultCacheOptions returns default cache options
 func DefaultCacheOptions() *CacheOptions {
 	return NewCacheOptions()
@@ -76,8 +155,69 @@ func DefaultCacheOptions() *CacheOptions {
 
 // Cache provides high-level caching operations
 type Cache struct {
-	client *Client
-	opts   *CacheOptions
+	client     *Client
+	opts       *CacheOptions
+	loads      singleflight.Group
+	refreshing sync.Map
+
+	localHits       uint64
+	localMisses     uint64
+	remoteHits      uint64
+	remoteMisses    uint64
+	refreshFailures uint64
+}
+
+// CacheStats is a point-in-time snapshot of the hit and miss counts of both cache tiers.
+// Local counts stay at zero when the cache has no LocalCache.
+type CacheStats struct {
+	LocalHits    uint64
+	LocalMisses  uint64
+	RemoteHits   uint64
+	RemoteMisses uint64
+	// RefreshFailures counts the background refreshes of stale values that failed
+	RefreshFailures uint64
+}
+
+// SetterError wraps an error returned by a GetOrSet setter, so callers can tell it apart
+// from cache failures and return the original error unchanged
+type SetterError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *SetterError) Error() string {
+	return "setter function failed: " + e.Err.Error()
+}
+
+// Unwrap returns the setter error
+func (e *SetterError) Unwrap() error {
+	return e.Err
+}
+
+// ReadThrough returns a value read through a cache with GetOrSet. Errors of the setter are
+// returned unchanged, and when the cache itself fails the value is read with fallback.
+// cacheName only identifies the cache in the log message.
+func ReadThrough[T any](cacheName string, cached T, err error, fallback func() (T, error)) (T, error) {
+	if err == nil {
+		return cached, nil
+	}
+
+	var setterErr *SetterError
+	if errors.As(err, &setterErr) {
+		var zero T
+		return zero, setterErr.Err
+	}
+
+	log.Warnf("Cache %s unavailable, reading without it: %v", cacheName, err)
+	return fallback()
+}
+
+// cacheEntry is a cached value read by GetOrSet along with its freshness metadata
+type cacheEntry struct {
+	data      []byte
+	expiresAt time.Time
+	delta     time.Duration
+	hasMeta   bool
 }
 
 // NewCache creates a new cache instance
@@ -125,20 +265,34 @@ func (c *Cache) buildCacheKey(key string) string {
 	return key
 }
 
-// Get retrieves a value from cache and deserializes it
+// Get retrieves a value from cache and deserializes it.
+// A missing key is not an error and leaves dest untouched, use Lookup to tell hits from misses.
 func (c *Cache) Get(ctx context.Context, key string, dest interface{}) error {
+	_, err := c.Lookup(ctx, key, dest)
+	return err
+}
+
+// Lookup retrieves a value from cache and deserializes it, reporting whether the key was found.
+// Errors are only returned for real failures (connection, deserialization), never for misses.
+func (c *Cache) Lookup(ctx context.Context, key string, dest interface{}) (bool, error) {
 	fullKey := c.buildCacheKey(key)
+	if data, ok := c.localGet(fullKey); ok {
+		return true, c.deserialize(data, dest)
+	}
+
 	data, err := c.client.GetBytes(ctx, fullKey)
 	if err != nil {
 		// Real error (connection, etc.) - return it
-		return err
+		return false, err
 	}
 
 	// Check if data is empty (key doesn't exist)
+	c.countRemote(len(data) > 0)
 	if len(data) == 0 {
-		// Key doesn't exist - this is not an error, just return nil
-		return nil
+		// Key doesn't exist - this is not an error
+		return false, nil
 	}
+	c.localSet(fullKey, data, c.getTTL())
 
 	if c.opts.RefreshTTL {
 		// Refresh TTL on access
@@ -148,29 +302,45 @@ func (c *Cache) Get(ctx context.Context, key string, dest interface{}) error {
 		}
 	}
 
-	return c.opts.Deserializer(data, dest)
+	if err := c.opts.Deserializer(data, dest); err != nil {
+		return true, fmt.Errorf("failed to deserialize value: %w", err)
+	}
+	return true, nil
 }
 
 // Set stores a value in cache with serialization
 func (c *Cache) Set(ctx context.Context, key string, value interface{}) error {
-	fullKey := c.buildCacheKey(key)
-	data, err := c.opts.Serializer(value)
-	if err != nil {
-		return fmt.Errorf("failed to serialize value: %w", err)
-	}
-
-	return c.client.Set(ctx, fullKey, data, c.getTTL())
+	return c.set(ctx, key, value, c.getTTL(), nil)
 }
 
 // SetWithTTL stores a value in cache with custom TTL (highest priority)
 func (c *Cache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.set(ctx, key, value, ttl, nil)
+}
+
+// SetWithTags stores a value in cache and attaches tags to it, so InvalidateTags
+// can later delete it along with every other key carrying one of the tags
+func (c *Cache) SetWithTags(ctx context.Context, key string, value interface{}, tags ...string) error {
+	return c.set(ctx, key, value, c.getTTL(), tags)
+}
+
+// SetWithTTLAndTags stores a value in cache with custom TTL and attaches tags to it
+func (c *Cache) SetWithTTLAndTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	return c.set(ctx, key, value, ttl, tags)
+}
+
+// set serializes and stores a value, then propagates the write to the local tiers
+func (c *Cache) set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
 	fullKey := c.buildCacheKey(key)
 	data, err := c.opts.Serializer(value)
 	if err != nil {
 		return fmt.Errorf("failed to serialize value: %w", err)
 	}
 
-	return c.client.Set(ctx, fullKey, data, ttl)
+	if err := c.writeValue(ctx, fullKey, data, ttl, tags); err != nil {
+		return err
+	}
+	return c.propagateWrite(ctx, fullKey, data, ttl)
 }
 
 // SetWithTTLAndRefresh stores a value in cache with custom TTL and refresh TTL on access
@@ -182,19 +352,29 @@ func (c *Cache) SetWithTTLAndRefresh(ctx context.Context, key string, value inte
 	}
 
 	// Store with custom TTL
-	err = c.client.Set(ctx, fullKey, data, ttl)
+	err = c.writeValue(ctx, fullKey, data, ttl, nil)
 	if err != nil {
 		return err
 	}
+	if err := c.propagateWrite(ctx, fullKey, data, ttl); err != nil {
+		return err
+	}
 
 	// If refresh TTL is enabled, we'll handle it in the Get method
 	return nil
 }
 
-// Delete removes a value from cache
+// Delete removes a value from cache, along with its GetOrSet freshness metadata.
+// With a LocalCache the entry is also evicted from every instance.
 func (c *Cache) Delete(ctx context.Context, key string) error {
 	fullKey := c.buildCacheKey(key)
-	return c.client.Delete(ctx, fullKey)
+	if err := c.client.Delete(ctx, fullKey, fullKey+cacheMetaSuffix); err != nil {
+		return err
+	}
+	if c.opts.LocalCache != nil {
+		return c.opts.LocalCache.invalidate(ctx, fullKey)
+	}
+	return nil
 }
 
 // Exists checks if a key exists in cache
@@ -204,31 +384,344 @@ func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
 	return count > 0, err
 }
 
-// GetOrSet retrieves a value from cache, or sets it if it doesn't exist
+// GetOrSet retrieves a value from cache, or sets it with the setter result if it doesn't exist.
+//
+// Concurrent misses for the same key in this process share a single setter call.
+// With WithDistributedLoad the recompute is also serialized across instances through
+// a Lock, and instances waiting for it reuse the value stored by the lock holder.
+// With WithStaleWhileRevalidate expired values keep being served for StaleTTL while
+// one goroutine refreshes them, and with WithEarlyExpiration values are recomputed
+// shortly before their TTL with a probability that avoids synchronized expirations.
+//
+// Cache read errors are returned instead of being treated as misses.
 func (c *Cache) GetOrSet(ctx context.Context, key string, dest interface{}, setter func() (interface{}, error)) error {
-	// Try to get from cache first
-	err := c.Get(ctx, key, dest)
-	if err == nil {
+	return c.getOrLoad(ctx, key, dest, func() (interface{}, []string, error) {
+		value, err := setter()
+		return value, nil, err
+	})
+}
+
+// GetOrSetWithTags works like GetOrSet, attaching the tags returned by the setter to the
+// stored value. Tags can depend on the value, for example one tag per city in a page.
+func (c *Cache) GetOrSetWithTags(ctx context.Context, key string, dest interface{}, setter func() (interface{}, []string, error)) error {
+	return c.getOrLoad(ctx, key, dest, setter)
+}
+
+// cacheLoader computes a value and the tags to attach to it
+type cacheLoader func() (interface{}, []string, error)
+
+// getOrLoad implements GetOrSet and GetOrSetWithTags
+func (c *Cache) getOrLoad(ctx context.Context, key string, dest interface{}, setter cacheLoader) error {
+	fullKey := c.buildCacheKey(key)
+	if data, ok := c.localGet(fullKey); ok {
+		return c.deserialize(data, dest)
+	}
+
+	entry, err := c.readEntry(ctx, fullKey)
+	if err != nil {
+		return fmt.Errorf("failed to read cache key %s: %w", fullKey, err)
+	}
+	c.countRemote(entry != nil)
+
+	if entry != nil {
+		now := time.Now()
+		switch {
+		case !c.isExpired(entry, now) && !c.shouldExpireEarly(entry, now):
+			c.localSet(fullKey, entry.data, c.remainingTTL(entry, now))
+			return c.deserialize(entry.data, dest)
+		case c.opts.StaleTTL > 0:
+			// Serve the current value and refresh it in background
+			c.refreshInBackground(ctx, fullKey, setter)
+			return c.deserialize(entry.data, dest)
+		}
+	}
+
+	data, err := c.load(ctx, fullKey, setter)
+	if err != nil {
+		return err
+	}
+	return c.deserialize(data, dest)
+}
+
+// usesMetadata reports whether GetOrSet stores freshness metadata next to the values
+func (c *Cache) usesMetadata() bool {
+	return c.opts.StaleTTL > 0 || c.opts.EarlyExpirationBeta > 0
+}
+
+// readEntry reads a value and, when needed, its freshness metadata in a single round trip.
+// It returns nil when the key does not exist.
+func (c *Cache) readEntry(ctx context.Context, fullKey string) (*cacheEntry, error) {
+	if !c.usesMetadata() {
+		data, err := c.client.GetBytes(ctx, fullKey)
+		if err != nil || len(data) == 0 {
+			return nil, err
+		}
+		return &cacheEntry{data: data}, nil
+	}
+
+	// A pipeline rather than MGET, as the value and its metadata may be in different cluster slots
+	var dataCmd, metaCmd *redis.StringCmd
+	_, err := c.client.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		dataCmd = pipe.Get(ctx, fullKey)
+		metaCmd = pipe.Get(ctx, fullKey+cacheMetaSuffix)
 		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
 	}
 
-	// If not found or error, call setter function
-	value, err := setter()
+	data, err := dataCmd.Bytes()
 	if err != nil {
-		return fmt.Errorf("setter function failed: %w", err)
+		return nil, nil
+	}
+	entry := &cacheEntry{data: data}
+
+	if meta, err := metaCmd.Result(); err == nil {
+		expiresAt, delta, err := parseCacheMeta(meta)
+		if err == nil {
+			entry.expiresAt = expiresAt
+			entry.delta = delta
+			entry.hasMeta = true
+		}
 	}
 
-	// Set in cache
-	if err := c.Set(ctx, key, value); err != nil {
-		return fmt.Errorf("failed to set value in cache: %w", err)
+	return entry, nil
+}
+
+// isExpired reports whether the logical TTL of the entry has passed
+func (c *Cache) isExpired(entry *cacheEntry, now time.Time) bool {
+	return entry.hasMeta && !now.Before(entry.expiresAt)
+}
+
+// shouldExpireEarly implements probabilistic early expiration (XFetch): the entry is
+// treated as expired when now - delta * beta * ln(rand) reaches its expiration time
+func (c *Cache) shouldExpireEarly(entry *cacheEntry, now time.Time) bool {
+	if !entry.hasMeta || c.opts.EarlyExpirationBeta <= 0 {
+		return false
 	}
+	gap := -float64(entry.delta) * c.opts.EarlyExpirationBeta * math.Log(1-rand.Float64())
+	return !now.Add(time.Duration(gap)).Before(entry.expiresAt)
+}
+
+// load runs the setter once per key in this process and stores the result
+func (c *Cache) load(ctx context.Context, fullKey string, setter cacheLoader) ([]byte, error) {
+	result, err, _ := c.loads.Do(fullKey, func() (interface{}, error) {
+		return c.loadOnce(ctx, fullKey, setter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// loadOnce calls the setter, holding the distributed load lock when configured, and stores the result
+func (c *Cache) loadOnce(ctx context.Context, fullKey string, setter cacheLoader) ([]byte, error) {
+	if c.opts.LoadLock != nil {
+		lock := c.newLoadLock(fullKey)
+		if _, err := lock.Lock(ctx); err != nil {
+			// The lock holder may have stored the value in the meantime
+			if entry, readErr := c.readEntry(ctx, fullKey); readErr == nil && entry != nil {
+				return entry.data, nil
+			}
+			return nil, fmt.Errorf("failed to acquire load lock for %s: %w", fullKey, err)
+		}
+		defer func() {
+			if err := lock.Unlock(ctx); err != nil {
+				// The lock expired while loading, the value is stored anyway
+				log.Warn("Failed to release cache load lock",
+					zap.String("cache", c.opts.CacheName),
+					zap.String("key", fullKey),
+					zap.Error(err),
+				)
+			}
+		}()
+
+		// Another instance may have loaded the value while we were waiting for the lock
+		entry, err := c.readEntry(ctx, fullKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache key %s: %w", fullKey, err)
+		}
+		if entry != nil && (!entry.hasMeta || entry.expiresAt.After(time.Now().Add(c.getTTL()/2))) {
+			return entry.data, nil
+		}
+	}
+
+	start := time.Now()
+	value, tags, err := setter()
+	if err != nil {
+		return nil, &SetterError{Err: err}
+	}
+	delta := time.Since(start)
 
-	// Serialize the value and deserialize it into dest
 	data, err := c.opts.Serializer(value)
 	if err != nil {
-		return fmt.Errorf("failed to serialize value: %w", err)
+		return nil, fmt.Errorf("failed to serialize value: %w", err)
+	}
+
+	if err := c.storeEntry(ctx, fullKey, data, delta, tags); err != nil {
+		return nil, fmt.Errorf("failed to set value in cache: %w", err)
+	}
+	c.localSet(fullKey, data, c.getTTL())
+	return data, nil
+}
+
+// newLoadLock creates the lock serializing recomputes of a key across instances.
+// Load locks are short-lived and per key, so they are not added to the lock registry, and
+// their fencing token counter expires instead of being kept for every key ever loaded.
+func (c *Cache) newLoadLock(fullKey string) *Lock {
+	lockOpts := *c.opts.LoadLock
+	lockOpts.CacheName = ""
+	if lockOpts.LockNamespace == "" {
+		lockOpts.LockNamespace = defaultLoadLockNamespace
+	}
+	if lockOpts.FencingTokenRetention == 0 {
+		lockOpts.FencingTokenRetention = max(lockOpts.TTL, time.Minute)
+	}
+	return NewLock(c.client, fullKey, &lockOpts)
+}
+
+// refreshInBackground recomputes a stale key in a goroutine, at most once at a time per key
+func (c *Cache) refreshInBackground(ctx context.Context, fullKey string, setter cacheLoader) {
+	if _, running := c.refreshing.LoadOrStore(fullKey, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer c.refreshing.Delete(fullKey)
+		if _, err := c.load(context.WithoutCancel(ctx), fullKey, setter); err != nil {
+			// The stale value keeps being served until it is evicted or a refresh succeeds
+			atomic.AddUint64(&c.refreshFailures, 1)
+			log.Warn("Failed to refresh stale cache value",
+				zap.String("cache", c.opts.CacheName),
+				zap.String("key", fullKey),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// storeEntry stores a value computed by GetOrSet. With stale-while-revalidate or early
+// expiration the key lives for TTL + StaleTTL and its logical expiration is kept in metadata.
+func (c *Cache) storeEntry(ctx context.Context, fullKey string, data []byte, delta time.Duration, tags []string) error {
+	ttl := c.getTTL()
+	if !c.usesMetadata() || ttl == 0 {
+		return c.writeValue(ctx, fullKey, data, ttl, tags)
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	pipe := c.client.Pipeline()
+	pipe.Set(ctx, fullKey, data, ttl+c.opts.StaleTTL)
+	pipe.Set(ctx, fullKey+cacheMetaSuffix, formatCacheMeta(expiresAt, delta), ttl+c.opts.StaleTTL)
+	calls := attachTags(ctx, pipe, fullKey, ttl+c.opts.StaleTTL, tags)
+	return execTagged(ctx, c.client.GetClient(), pipe, calls)
+}
+
+// writeValue stores a value set directly, dropping GetOrSet metadata that no longer applies
+func (c *Cache) writeValue(ctx context.Context, fullKey string, data []byte, ttl time.Duration, tags []string) error {
+	if !c.usesMetadata() && len(tags) == 0 {
+		return c.client.Set(ctx, fullKey, data, ttl)
+	}
+
+	pipe := c.client.Pipeline()
+	pipe.Set(ctx, fullKey, data, ttl)
+	if c.usesMetadata() {
+		pipe.Del(ctx, fullKey+cacheMetaSuffix)
 	}
-	return c.opts.Deserializer(data, dest)
+	calls := attachTags(ctx, pipe, fullKey, ttl, tags)
+	return execTagged(ctx, c.client.GetClient(), pipe, calls)
+}
+
+// remainingTTL returns how long a value read by GetOrSet stays fresh
+func (c *Cache) remainingTTL(entry *cacheEntry, now time.Time) time.Duration {
+	if !entry.hasMeta {
+		return c.getTTL()
+	}
+	return entry.expiresAt.Sub(now)
+}
+
+// localGet reads a full key from the LocalCache, counting the local hit or miss
+func (c *Cache) localGet(fullKey string) ([]byte, bool) {
+	if c.opts.LocalCache == nil {
+		return nil, false
+	}
+
+	data, ok := c.opts.LocalCache.get(fullKey)
+	if ok {
+		atomic.AddUint64(&c.localHits, 1)
+	} else {
+		atomic.AddUint64(&c.localMisses, 1)
+	}
+	return data, ok
+}
+
+// localSet stores a value read from or written to Redis in the LocalCache
+func (c *Cache) localSet(fullKey string, data []byte, ttl time.Duration) {
+	if c.opts.LocalCache != nil {
+		c.opts.LocalCache.set(c.opts.CacheName, fullKey, data, ttl)
+	}
+}
+
+// propagateWrite evicts a written key from every instance and keeps the new value locally
+func (c *Cache) propagateWrite(ctx context.Context, fullKey string, data []byte, ttl time.Duration) error {
+	if c.opts.LocalCache == nil {
+		return nil
+	}
+
+	if err := c.opts.LocalCache.invalidate(ctx, fullKey); err != nil {
+		return err
+	}
+	c.localSet(fullKey, data, ttl)
+	return nil
+}
+
+// countRemote counts a Redis hit or miss
+func (c *Cache) countRemote(hit bool) {
+	if hit {
+		atomic.AddUint64(&c.remoteHits, 1)
+	} else {
+		atomic.AddUint64(&c.remoteMisses, 1)
+	}
+}
+
+// Stats returns the hit and miss counts of the local and Redis tiers and the failed background refreshes
+func (c *Cache) Stats() CacheStats {
+	return CacheStats{
+		LocalHits:       atomic.LoadUint64(&c.localHits),
+		LocalMisses:     atomic.LoadUint64(&c.localMisses),
+		RemoteHits:      atomic.LoadUint64(&c.remoteHits),
+		RemoteMisses:    atomic.LoadUint64(&c.remoteMisses),
+		RefreshFailures: atomic.LoadUint64(&c.refreshFailures),
+	}
+}
+
+// deserialize decodes cached data into dest
+func (c *Cache) deserialize(data []byte, dest interface{}) error {
+	if err := c.opts.Deserializer(data, dest); err != nil {
+		return fmt.Errorf("failed to deserialize value: %w", err)
+	}
+	return nil
+}
+
+// formatCacheMeta encodes the logical expiration and recompute time as "expiresAtMillis:deltaMillis"
+func formatCacheMeta(expiresAt time.Time, delta time.Duration) string {
+	return strconv.FormatInt(expiresAt.UnixMilli(), 10) + ":" + strconv.FormatInt(delta.Milliseconds(), 10)
+}
+
+// parseCacheMeta decodes metadata written by formatCacheMeta
+func parseCacheMeta(meta string) (time.Time, time.Duration, error) {
+	expiresAtValue, deltaValue, found := strings.Cut(meta, ":")
+	if !found {
+		return time.Time{}, 0, fmt.Errorf("invalid cache metadata: %s", meta)
+	}
+	expiresAt, err := strconv.ParseInt(expiresAtValue, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid cache metadata expiration: %w", err)
+	}
+	delta, err := strconv.ParseInt(deltaValue, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid cache metadata delta: %w", err)
+	}
+	return time.UnixMilli(expiresAt), time.Duration(delta) * time.Millisecond, nil
 }
 
 // MGet retrieves multiple values from cache
@@ -237,6 +730,11 @@ func (c *Cache) MGet(ctx context.Context, keys []string) (map[string][]byte, err
 
 	for _, key := range keys {
 		fullKey := c.buildCacheKey(key)
+		if data, ok := c.localGet(fullKey); ok {
+			result[key] = data
+			continue
+		}
+
 		data, err := c.client.GetBytes(ctx, fullKey)
 		if err != nil {
 			// Real error (connection, etc.) - return it
@@ -244,8 +742,10 @@ func (c *Cache) MGet(ctx context.Context, keys []string) (map[string][]byte, err
 		}
 
 		// Only add non-empty data to result
+		c.countRemote(len(data) > 0)
 		if len(data) > 0 {
 			result[key] = data
+			c.localSet(fullKey, data, c.getTTL())
 		}
 	}
 
@@ -275,9 +775,14 @@ func (c *Cache) Clear(ctx context.Context, pattern string) error {
 	}
 
 	if len(keys) > 0 {
-		return c.client.Delete(ctx, keys...)
+		if err := c.client.Delete(ctx, keys...); err != nil {
+			return err
+		}
 	}
 
+	if c.opts.LocalCache != nil {
+		return c.opts.LocalCache.invalidatePattern(ctx, pattern)
+	}
 	return nil
 }
 
@@ -313,5 +818,8 @@ func (c *Cache) ClearCacheName(ctx context.Context) error {
 		}
 	}
 
+	if c.opts.LocalCache != nil {
+		return c.opts.LocalCache.invalidatePattern(ctx, pattern)
+	}
 	return nil
 }
@@ -0,0 +1,158 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// cacheTagNamespace prefixes the Redis sets holding the keys of each tag.
+// Tags are shared by every cache name, so one tag can invalidate entries of several caches.
+const cacheTagNamespace = "cache-tag"
+
+// attachTagsScript adds a key to the set of a tag (KEYS) and keeps the set alive at least as
+// long as the key. ARGV[1] is the key, ARGV[2] its TTL in milliseconds (0 for none).
+var attachTagsScript = RegisterScript("cache.attach-tags", `
+	local ttl = tonumber(ARGV[2])
+	for _, tagKey in ipairs(KEYS) do
+		local created = redis.call("SADD", tagKey, ARGV[1]) == 1 and redis.call("SCARD", tagKey) == 1
+		if ttl == 0 then
+			redis.call("PERSIST", tagKey)
+		else
+			local current = redis.call("PTTL", tagKey)
+			if created or (current >= 0 and current < ttl) then
+				redis.call("PEXPIRE", tagKey, ttl)
+			end
+		end
+	end
+	return 1
+`)
+
+// invalidateTagsScript deletes every key in the sets of the tags (KEYS), their GetOrSet
+// metadata and the sets themselves, and returns the deleted keys.
+// It touches keys it is not given, so it only runs outside a Redis Cluster.
+var invalidateTagsScript = RegisterScript("cache.invalidate-tags", `
+	local deleted = {}
+	for _, tagKey in ipairs(KEYS) do
+		local members = redis.call("SMEMBERS", tagKey)
+		for _, member in ipairs(members) do
+			if redis.call("DEL", member, member .. ARGV[1]) > 0 then
+				table.insert(deleted, member)
+			end
+		end
+		redis.call("DEL", tagKey)
+	end
+	return deleted
+`)
+
+// popTagScript deletes the set of a tag (KEYS[1]) and returns its keys
+var popTagScript = RegisterScript("cache.pop-tag", `
+	local members = redis.call("SMEMBERS", KEYS[1])
+	redis.call("DEL", KEYS[1])
+	return members
+`)
+
+// buildTagKey constructs the key of the set holding the keys of a tag
+func buildTagKey(tag string) string {
+	return cacheTagNamespace + "::" + tag
+}
+
+// buildTagKeys constructs the set keys of several tags
+func buildTagKeys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = buildTagKey(tag)
+	}
+	return keys
+}
+
+// attachTags queues the tagging of a key in a pipeline, with one script call per tag as the sets
+// of the tags may be in different cluster slots. The pipeline is executed with execTagged.
+func attachTags(ctx context.Context, pipe redis.Pipeliner, fullKey string, ttl time.Duration, tags []string) []*ScriptCall {
+	calls := make([]*ScriptCall, 0, len(tags))
+	for _, tagKey := range buildTagKeys(tags) {
+		calls = append(calls, attachTagsScript.Queue(ctx, pipe, []string{tagKey}, fullKey, ttl.Milliseconds()))
+	}
+	return calls
+}
+
+// execTagged executes a pipeline ending with the calls queued by attachTags, retrying the
+// calls Redis answered with NOSCRIPT once the script is loaded
+func execTagged(ctx context.Context, client redis.Scripter, pipe redis.Pipeliner, calls []*ScriptCall) error {
+	// The first error of the pipeline is NOSCRIPT only when the commands before the calls succeeded
+	if _, err := pipe.Exec(ctx); err != nil && !isNoScript(err) {
+		return err
+	}
+	for _, call := range calls {
+		if err := call.Result(ctx, client).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidateTags atomically deletes every key carrying at least one of the tags, in any
+// cache name, and evicts them from the LocalCache of every instance.
+// It is a single Lua script over the tag sets, so no key pattern is scanned. On a Redis Cluster,
+// where the tagged keys are spread over the slots, each tag set is popped atomically and its
+// keys are then deleted in a pipeline.
+func (c *Cache) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	var result []string
+	var err error
+	if c.client.IsCluster() {
+		result, err = c.invalidateClusterTags(ctx, tags)
+	} else {
+		result, err = invalidateTagsScript.Run(ctx, c.client.GetClient(), buildTagKeys(tags), cacheMetaSuffix).StringSlice()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to invalidate tags %v: %w", tags, err)
+	}
+
+	if c.opts.LocalCache != nil && len(result) > 0 {
+		return c.opts.LocalCache.invalidate(ctx, result...)
+	}
+	return nil
+}
+
+// invalidateClusterTags pops the sets of the tags and deletes their keys and metadata, returning the deleted keys
+func (c *Cache) invalidateClusterTags(ctx context.Context, tags []string) ([]string, error) {
+	members := make([]string, 0)
+	for _, tagKey := range buildTagKeys(tags) {
+		tagMembers, err := popTagScript.Run(ctx, c.client.GetClient(), []string{tagKey}).StringSlice()
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, tagMembers...)
+	}
+
+	cmds := make([]*redis.IntCmd, len(members))
+	_, err := c.client.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, member := range members {
+			cmds[i] = pipe.Del(ctx, member)
+			pipe.Del(ctx, member+cacheMetaSuffix)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]string, 0, len(members))
+	for i, member := range members {
+		if cmds[i].Val() > 0 {
+			deleted = append(deleted, member)
+		}
+	}
+	return deleted, nil
+}
+
+// TaggedKeys returns the keys currently carrying a tag, including keys that already expired
+func (c *Cache) TaggedKeys(ctx context.Context, tag string) ([]string, error) {
+	return c.client.SMembers(ctx, buildTagKey(tag))
+}
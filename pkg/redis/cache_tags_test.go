@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCacheInvalidateTags(t *testing.T) {
+	tests := []struct {
+		name string
+		// invalidate runs the invalidation of the tags on cache
+		invalidate func(ctx context.Context, cache *Cache, tags ...string) error
+	}{
+		{
+			name: "script",
+			invalidate: func(ctx context.Context, cache *Cache, tags ...string) error {
+				return cache.InvalidateTags(ctx, tags...)
+			},
+		},
+		{
+			name: "cluster",
+			invalidate: func(ctx context.Context, cache *Cache, tags ...string) error {
+				deleted, err := cache.invalidateClusterTags(ctx, tags)
+				if err != nil {
+					return err
+				}
+				return cache.opts.LocalCache.invalidate(ctx, deleted...)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t)
+			ctx := context.Background()
+			localCache := NewLocalCache(client, NewLocalCacheOptions())
+			weather := NewCache(client, NewCacheOptions().
+				WithCacheName("weather").
+				WithTTL(time.Minute).
+				WithStaleWhileRevalidate(time.Minute).
+				WithLocalCache(localCache))
+			shortURL := NewCache(client, NewCacheOptions().
+				WithCacheName("shorturl").
+				WithTTL(time.Hour).
+				WithLocalCache(localCache))
+
+			var forecast string
+			if err := weather.GetOrSetWithTags(ctx, "recife", &forecast, func() (interface{}, []string, error) {
+				return "sunny", []string{"city:recife"}, nil
+			}); err != nil {
+				t.Fatalf("GetOrSetWithTags() error = %v", err)
+			}
+			if err := shortURL.SetWithTags(ctx, "abc", "https://recife.example", "city:recife", "user:1"); err != nil {
+				t.Fatalf("SetWithTags() error = %v", err)
+			}
+			if err := shortURL.SetWithTags(ctx, "def", "https://olinda.example", "user:1"); err != nil {
+				t.Fatalf("SetWithTags() error = %v", err)
+			}
+			if err := shortURL.Set(ctx, "untagged", "https://example"); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			// A tag set lives at least as long as its longest-lived key
+			if ttl := server.TTL(buildTagKey("city:recife")); ttl != time.Hour {
+				t.Errorf("TTL of the city:recife set = %v, want %v", ttl, time.Hour)
+			}
+			keys, err := weather.TaggedKeys(ctx, "city:recife")
+			sort.Strings(keys)
+			if err != nil || !reflect.DeepEqual(keys, []string{"shorturl::abc", "weather::recife"}) {
+				t.Errorf("TaggedKeys() = %v, %v, want the keys of both caches", keys, err)
+			}
+
+			if err := tt.invalidate(ctx, weather, "city:recife"); err != nil {
+				t.Fatalf("invalidate() error = %v", err)
+			}
+
+			for _, key := range []string{"weather::recife", "weather::recife" + cacheMetaSuffix, "shorturl::abc", buildTagKey("city:recife")} {
+				if server.Exists(key) {
+					t.Errorf("%s exists after the invalidation", key)
+				}
+			}
+			for _, key := range []string{"shorturl::def", "shorturl::untagged", buildTagKey("user:1")} {
+				if !server.Exists(key) {
+					t.Errorf("%s was deleted, it does not carry the tag", key)
+				}
+			}
+
+			// The local tier must not serve the invalidated values
+			for _, fullKey := range []string{"weather::recife", "shorturl::abc"} {
+				if _, ok := localCache.get(fullKey); ok {
+					t.Errorf("local cache still holds %s", fullKey)
+				}
+			}
+			var url string
+			if found, err := shortURL.Lookup(ctx, "abc", &url); err != nil || found {
+				t.Errorf("Lookup() after the invalidation = %v, %v, want a miss", found, err)
+			}
+			if found, err := shortURL.Lookup(ctx, "def", &url); err != nil || !found || url != "https://olinda.example" {
+				t.Errorf("Lookup() of a key with another tag = %q, %v, %v, want a hit", url, found, err)
+			}
+		})
+	}
+}
+
+func TestCacheAttachTagsKeepsLongestTTL(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	cache := NewCache(client, NewCacheOptions().WithCacheName("test"))
+
+	steps := []struct {
+		key     string
+		ttl     time.Duration
+		wantTTL time.Duration
+	}{
+		{"short", time.Minute, time.Minute},
+		{"long", time.Hour, time.Hour},
+		// A shorter key never shortens the set
+		{"shorter", time.Second, time.Hour},
+		// A key without expiration makes the set permanent
+		{"forever", 0, 0},
+		{"again", time.Minute, 0},
+	}
+	for _, step := range steps {
+		if err := cache.SetWithTTLAndTags(ctx, step.key, "value", step.ttl, "tag"); err != nil {
+			t.Fatalf("SetWithTTLAndTags(%s) error = %v", step.key, err)
+		}
+		if ttl := server.TTL(buildTagKey("tag")); ttl != step.wantTTL {
+			t.Errorf("TTL of the tag set after %s = %v, want %v", step.key, ttl, step.wantTTL)
+		}
+	}
+}
+
+func TestCacheAttachTagsAfterScriptFlush(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	cache := NewCache(client, NewCacheOptions().WithCacheName("test"))
+
+	// Redis forgets the scripts on restart or failover
+	if err := client.GetClient().ScriptFlush(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.SetWithTags(ctx, "key", "value", "first", "second"); err != nil {
+		t.Fatalf("SetWithTags() error = %v", err)
+	}
+	for _, tag := range []string{"first", "second"} {
+		if members, err := server.Members(buildTagKey(tag)); err != nil || !reflect.DeepEqual(members, []string{"test::key"}) {
+			t.Errorf("members of %s = %v, %v, want test::key", tag, members, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheCountsRefreshFailures(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	cache := NewCache(client, NewCacheOptions().
+		WithCacheName("test").
+		WithTTL(10*time.Millisecond).
+		WithStaleWhileRevalidate(time.Minute))
+
+	var value string
+	if err := cache.GetOrSet(ctx, "key", &value, func() (interface{}, error) {
+		return "first", nil
+	}); err != nil {
+		t.Fatalf("GetOrSet() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// The stale value is served while the refresh fails in background
+	if err := cache.GetOrSet(ctx, "key", &value, func() (interface{}, error) {
+		return nil, errors.New("unavailable")
+	}); err != nil || value != "first" {
+		t.Fatalf("GetOrSet() of a stale value = %q, %v, want first", value, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Stats().RefreshFailures == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if stats := cache.Stats(); stats.RefreshFailures != 1 {
+		t.Errorf("Stats().RefreshFailures = %d, want 1", stats.RefreshFailures)
+	}
+}
+
+func TestReadThrough(t *testing.T) {
+	setterErr := errors.New("not found")
+	tests := []struct {
+		name         string
+		err          error
+		want         string
+		wantErr      error
+		wantFallback bool
+	}{
+		{name: "cached value", want: "cached"},
+		{name: "setter error is returned unchanged", err: &SetterError{Err: setterErr}, wantErr: setterErr},
+		{name: "cache failure falls back", err: errors.New("connection refused"), want: "fallback", wantFallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fellBack := false
+			got, err := ReadThrough("test", "cached", tt.err, func() (string, error) {
+				fellBack = true
+				return "fallback", nil
+			})
+			if err != tt.wantErr || (err == nil && got != tt.want) || fellBack != tt.wantFallback {
+				t.Errorf("ReadThrough() = %q, %v, fallback %v, want %q, %v, fallback %v", got, err, fellBack, tt.want, tt.wantErr, tt.wantFallback)
+			}
+		})
+	}
+}
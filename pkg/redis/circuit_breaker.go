@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pkghttp "go-api/pkg/http"
+)
+
+// CircuitBreakerStore shares the open circuits of pkg/http circuit breakers between instances,
+// so a host failing on one instance is rejected by all of them during the cool-down
+type CircuitBreakerStore struct {
+	client    *Client
+	namespace string
+}
+
+var _ pkghttp.CircuitStateStore = (*CircuitBreakerStore)(nil)
+
+// NewCircuitBreakerStore creates a circuit breaker store, keys are namespace::host
+func NewCircuitBreakerStore(client *Client, namespace string) *CircuitBreakerStore {
+	if namespace == "" {
+		namespace = "circuit-breakers"
+	}
+	return &CircuitBreakerStore{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// buildKey constructs the key of a host using Namespace::host format
+func (s *CircuitBreakerStore) buildKey(host string) string {
+	return s.namespace + "::" + host
+}
+
+// Open opens the circuit of the host for the cool-down, keeping the expiration of a circuit already open
+func (s *CircuitBreakerStore) Open(ctx context.Context, host string, coolDown time.Duration) error {
+	if err := s.client.GetClient().SetNX(ctx, s.buildKey(host), time.Now().Unix(), coolDown).Err(); err != nil {
+		return fmt.Errorf("failed to open circuit of %s: %w", host, err)
+	}
+	return nil
+}
+
+// OpenFor returns how long the circuit of the host stays open, 0 when it is not open
+func (s *CircuitBreakerStore) OpenFor(ctx context.Context, host string) (time.Duration, error) {
+	ttl, err := s.client.GetClient().PTTL(ctx, s.buildKey(host)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read circuit of %s: %w", host, err)
+	}
+	// PTTL is negative when the key does not exist or has no expiration
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Close closes the circuit of the host
+func (s *CircuitBreakerStore) Close(ctx context.Context, host string) error {
+	if err := s.client.GetClient().Del(ctx, s.buildKey(host)).Err(); err != nil {
+		return fmt.Errorf("failed to close circuit of %s: %w", host, err)
+	}
+	return nil
+}
@@ -6,14 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// Client wraps the Redis client with additional functionality
+// Client wraps the Redis client with additional functionality.
+// It works against a single server, a Sentinel monitored master or a Redis Cluster.
 type Client struct {
-	rdb    *redis.Client
+	rdb    redis.UniversalClient
 	config *Config
 }
 
@@ -28,19 +30,64 @@ func NewClient(config *Config) *Client {
 		panic(fmt.Sprintf("invalid Redis configuration: %v", err))
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:           fmt.Sprintf("%s:%d", config.Host, config.Port),
-		Password:       config.Password,
-		DB:             config.Database,
-		MinIdleConns:   config.MinIdleConns,
-		MaxIdleConns:   config.MaxIdleConns,
-		MaxActiveConns: config.MaxActive,
-		MaxRetries:     config.MaxRetries,
-		DialTimeout:    config.DialTimeout,
-		ReadTimeout:    config.ReadTimeout,
-		WriteTimeout:   config.WriteTimeout,
-		PoolTimeout:    config.PoolTimeout,
-	})
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		panic(fmt.Sprintf("invalid Redis TLS configuration: %v", err))
+	}
+
+	var rdb redis.UniversalClient
+	switch config.GetMode() {
+	case ModeSentinel:
+		rdb = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       config.MasterName,
+			SentinelAddrs:    config.SentinelAddrs,
+			SentinelUsername: config.SentinelUsername,
+			SentinelPassword: config.SentinelPassword,
+			Username:         config.Username,
+			Password:         config.Password,
+			DB:               config.Database,
+			MinIdleConns:     config.MinIdleConns,
+			MaxIdleConns:     config.MaxIdleConns,
+			MaxActiveConns:   config.MaxActive,
+			MaxRetries:       config.MaxRetries,
+			DialTimeout:      config.DialTimeout,
+			ReadTimeout:      config.ReadTimeout,
+			WriteTimeout:     config.WriteTimeout,
+			PoolTimeout:      config.PoolTimeout,
+			TLSConfig:        tlsConfig,
+		})
+	case ModeCluster:
+		rdb = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:          config.ClusterAddrs,
+			Username:       config.Username,
+			Password:       config.Password,
+			MinIdleConns:   config.MinIdleConns,
+			MaxIdleConns:   config.MaxIdleConns,
+			MaxActiveConns: config.MaxActive,
+			MaxRetries:     config.MaxRetries,
+			DialTimeout:    config.DialTimeout,
+			ReadTimeout:    config.ReadTimeout,
+			WriteTimeout:   config.WriteTimeout,
+			PoolTimeout:    config.PoolTimeout,
+			TLSConfig:      tlsConfig,
+		})
+	default:
+		rdb = redis.NewClient(&redis.Options{
+			Addr:           fmt.Sprintf("%s:%d", config.Host, config.Port),
+			Username:       config.Username,
+			Password:       config.Password,
+			DB:             config.Database,
+			MinIdleConns:   config.MinIdleConns,
+			MaxIdleConns:   config.MaxIdleConns,
+			MaxActiveConns: config.MaxActive,
+			MaxRetries:     config.MaxRetries,
+			DialTimeout:    config.DialTimeout,
+			ReadTimeout:    config.ReadTimeout,
+			WriteTimeout:   config.WriteTimeout,
+			PoolTimeout:    config.PoolTimeout,
+			TLSConfig:      tlsConfig,
+		})
+	}
 
 	return &Client{
 		rdb:    rdb,
@@ -59,10 +106,26 @@ func (c *Client) Close() error {
 }
 
 // GetClient returns the underlying Redis client for advanced operations
-func (c *Client) GetClient() *redis.Client {
+func (c *Client) GetClient() redis.UniversalClient {
 	return c.rdb
 }
 
+// IsCluster reports whether the client is connected to a Redis Cluster
+func (c *Client) IsCluster() bool {
+	_, ok := c.rdb.(*redis.ClusterClient)
+	return ok
+}
+
+// forEachNode runs fn on the server, or on every master of a Redis Cluster
+func (c *Client) forEachNode(ctx context.Context, fn func(ctx context.Context, node redis.Cmdable) error) error {
+	if cluster, ok := c.rdb.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return fn(ctx, node)
+		})
+	}
+	return fn(ctx, c.rdb)
+}
+
 // GetConfig returns the Redis configuration
 func (c *Client) GetConfig() *Config {
 	return c.config
@@ -187,14 +250,43 @@ func (c *Client) GetBool(ctx context.Context, key string) (bool, error) {
 	return strconv.ParseBool(val)
 }
 
-// Delete removes one or more keys
+// Delete removes one or more keys. On a Redis Cluster the keys may belong to different slots.
 func (c *Client) Delete(ctx context.Context, keys ...string) error {
-	return c.rdb.Del(ctx, keys...).Err()
+	if !c.IsCluster() || len(keys) < 2 {
+		return c.rdb.Del(ctx, keys...).Err()
+	}
+
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		return nil
+	})
+	return err
 }
 
-// Exists checks if one or more keys exist
+// Exists checks if one or more keys exist. On a Redis Cluster the keys may belong to different slots.
 func (c *Client) Exists(ctx context.Context, keys ...string) (int64, error) {
-	return c.rdb.Exists(ctx, keys...).Result()
+	if !c.IsCluster() || len(keys) < 2 {
+		return c.rdb.Exists(ctx, keys...).Result()
+	}
+
+	cmds := make([]*redis.IntCmd, len(keys))
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Exists(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, cmd := range cmds {
+		count += cmd.Val()
+	}
+	return count, nil
 }
 
 // Expire sets an expiration on a key
@@ -207,12 +299,29 @@ func (c *Client) TTL(ctx context.Context, key string) (time.Duration, error) {
 	return c.rdb.TTL(ctx, key).Result()
 }
 
-// Keys returns all keys matching a pattern
+// Keys returns all keys matching a pattern, from every master of a Redis Cluster
 func (c *Client) Keys(ctx context.Context, pattern string) ([]string, error) {
-	return c.rdb.Keys(ctx, pattern).Result()
+	if !c.IsCluster() {
+		return c.rdb.Keys(ctx, pattern).Result()
+	}
+
+	var mu sync.Mutex
+	keys := make([]string, 0)
+	err := c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+		nodeKeys, err := node.Keys(ctx, pattern).Result()
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		keys = append(keys, nodeKeys...)
+		mu.Unlock()
+		return nil
+	})
+	return keys, err
 }
 
-// Scan iterates over keys matching a pattern
+// Scan iterates over keys matching a pattern. On a Redis Cluster it only iterates the node
+// serving a random slot, ScanKeys iterates every master.
 func (c *Client) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
 	return c.rdb.Scan(ctx, cursor, match, count).Result()
 }
@@ -362,24 +471,32 @@ func (c *Client) PSubscribe(ctx context.Context, channels ...string) *redis.PubS
 	return c.rdb.PSubscribe(ctx, channels...)
 }
 
-// FlushDB removes all keys from the current database
+// FlushDB removes all keys from the current database, on every master of a Redis Cluster
 func (c *Client) FlushDB(ctx context.Context) error {
-	return c.rdb.FlushDB(ctx).Err()
+	return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+		return node.FlushDB(ctx).Err()
+	})
 }
 
-// FlushAll removes all keys from all databases
+// FlushAll removes all keys from all databases, on every master of a Redis Cluster
 func (c *Client) FlushAll(ctx context.Context) error {
-	return c.rdb.FlushAll(ctx).Err()
+	return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+		return node.FlushAll(ctx).Err()
+	})
 }
 
-// FlushDBAsync removes all keys from the current database asynchronously
+// FlushDBAsync removes all keys from the current database asynchronously, on every master of a Redis Cluster
 func (c *Client) FlushDBAsync(ctx context.Context) error {
-	return c.rdb.FlushDBAsync(ctx).Err()
+	return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+		return node.FlushDBAsync(ctx).Err()
+	})
 }
 
-// FlushAllAsync removes all keys from all databases asynchronously
+// FlushAllAsync removes all keys from all databases asynchronously, on every master of a Redis Cluster
 func (c *Client) FlushAllAsync(ctx context.Context) error {
-	return c.rdb.FlushAllAsync(ctx).Err()
+	return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+		return node.FlushAllAsync(ctx).Err()
+	})
 }
 
 // FlushDBWithFallback removes all keys from the current database, with fallback to manual deletion
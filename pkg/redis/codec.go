@@ -0,0 +1,211 @@
+package redis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// CodecID identifies the codec that encoded a stored payload
+type CodecID byte
+
+const (
+	// CodecIDJSON identifies JSONCodec payloads
+	CodecIDJSON CodecID = 1
+	// CodecIDGob identifies GobCodec payloads
+	CodecIDGob CodecID = 2
+	// CodecIDMsgpack identifies MsgpackCodec payloads
+	CodecIDMsgpack CodecID = 3
+	// CodecIDGzipJSON identifies GzipJSONCodec payloads
+	CodecIDGzipJSON CodecID = 4
+)
+
+// Payload header written in front of every value encoded through EncodeWithHeader:
+// two magic bytes, the header format version and the CodecID.
+const (
+	payloadMagic0        byte = 0xCA
+	payloadMagic1        byte = 0xC3
+	payloadHeaderVersion byte = 1
+	payloadHeaderSize         = 4
+)
+
+// Codec encodes and decodes cached values
+type Codec interface {
+	// ID identifies the codec in the payload header, it must never change once values are stored
+	ID() CodecID
+	// Name is a human-readable codec name
+	Name() string
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, dest interface{}) error
+}
+
+// Built-in codecs
+var (
+	JSONCodec     Codec = jsonCodec{}
+	GobCodec      Codec = gobCodec{}
+	MsgpackCodec  Codec = msgpackCodec{}
+	GzipJSONCodec Codec = gzipJSONCodec{}
+)
+
+// CodecRegistry indexes the known codecs by ID, so payloads are decoded with the codec that wrote them
+type CodecRegistry struct {
+	codecs map[CodecID]Codec
+	mu     sync.RWMutex
+}
+
+// Global codec registry
+var codecRegistry = &CodecRegistry{
+	codecs: map[CodecID]Codec{
+		CodecIDJSON:     JSONCodec,
+		CodecIDGob:      GobCodec,
+		CodecIDMsgpack:  MsgpackCodec,
+		CodecIDGzipJSON: GzipJSONCodec,
+	},
+}
+
+// RegisterCodec registers a codec with the registry, it panics if the ID is already used by another codec
+func (cr *CodecRegistry) RegisterCodec(codec Codec) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if existing, ok := cr.codecs[codec.ID()]; ok && existing.Name() != codec.Name() {
+		panic(fmt.Sprintf("codec ID %d is already registered by %s", codec.ID(), existing.Name()))
+	}
+	cr.codecs[codec.ID()] = codec
+}
+
+// GetCodec returns the registered codec with the ID
+func (cr *CodecRegistry) GetCodec(id CodecID) (Codec, bool) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	codec, ok := cr.codecs[id]
+	return codec, ok
+}
+
+// RegisterCodec makes a custom codec available for decoding stored payloads.
+// It panics if the ID is already used by another codec.
+func RegisterCodec(codec Codec) {
+	codecRegistry.RegisterCodec(codec)
+}
+
+// ParseCodec converts a string codec name to a built-in Codec, defaulting to JSON
+func ParseCodec(name string) Codec {
+	switch strings.ToLower(name) {
+	case "gob":
+		return GobCodec
+	case "msgpack":
+		return MsgpackCodec
+	case "gzip-json", "gzip":
+		return GzipJSONCodec
+	default:
+		return JSONCodec
+	}
+}
+
+// EncodeWithHeader encodes a value with the codec and prefixes it with the payload header
+func EncodeWithHeader(codec Codec, value interface{}) ([]byte, error) {
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, 0, payloadHeaderSize+len(data))
+	payload = append(payload, payloadMagic0, payloadMagic1, payloadHeaderVersion, byte(codec.ID()))
+	return append(payload, data...), nil
+}
+
+// DecodeWithHeader decodes a payload with the codec named by its header, so entries written
+// before a codec change keep being readable. Payloads without a header are decoded as plain
+// JSON, the format written by a Cache without codec.
+func DecodeWithHeader(data []byte, dest interface{}) error {
+	if len(data) < payloadHeaderSize || data[0] != payloadMagic0 || data[1] != payloadMagic1 {
+		return json.Unmarshal(data, dest)
+	}
+	if data[2] != payloadHeaderVersion {
+		return fmt.Errorf("unsupported payload header version: %d", data[2])
+	}
+
+	codec, ok := codecRegistry.GetCodec(CodecID(data[3]))
+	if !ok {
+		return fmt.Errorf("unknown payload codec: %d", data[3])
+	}
+	return codec.Unmarshal(data[payloadHeaderSize:], dest)
+}
+
+// jsonCodec encodes values with encoding/json
+type jsonCodec struct{}
+
+func (jsonCodec) ID() CodecID  { return CodecIDJSON }
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Unmarshal(data []byte, dest interface{}) error {
+	return json.Unmarshal(data, dest)
+}
+
+// gobCodec encodes values with encoding/gob, faster than JSON for Go-only consumers
+type gobCodec struct{}
+
+func (gobCodec) ID() CodecID  { return CodecIDGob }
+func (gobCodec) Name() string { return "gob" }
+
+func (gobCodec) Marshal(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to encode gob: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, dest interface{}) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(dest); err != nil {
+		return fmt.Errorf("failed to decode gob: %w", err)
+	}
+	return nil
+}
+
+// gzipJSONCodec encodes values as gzip-compressed JSON, for large values such as cities with all their forecasts
+type gzipJSONCodec struct{}
+
+func (gzipJSONCodec) ID() CodecID  { return CodecIDGzipJSON }
+func (gzipJSONCodec) Name() string { return "gzip-json" }
+
+func (gzipJSONCodec) Marshal(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress value: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress value: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func (gzipJSONCodec) Unmarshal(data []byte, dest interface{}) error {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decompress value: %w", err)
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to decompress value: %w", err)
+	}
+	return json.Unmarshal(decompressed, dest)
+}
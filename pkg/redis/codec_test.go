@@ -0,0 +1,129 @@
+package redis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type codecTestValue struct {
+	Name     string                 `json:"name"`
+	Count    int64                  `json:"count"`
+	Big      uint64                 `json:"big"`
+	Ratio    float64                `json:"ratio"`
+	Enabled  bool                   `json:"enabled"`
+	Tags     []string               `json:"tags"`
+	Nested   map[string][]int       `json:"nested"`
+	Children []codecTestChild       `json:"children"`
+	Extra    map[string]interface{} `json:"extra,omitempty"`
+	Created  time.Time              `json:"created"`
+	Optional *string                `json:"optional"`
+}
+
+type codecTestChild struct {
+	ID    int            `json:"id"`
+	Attrs map[string]int `json:"attrs"`
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	value := codecTestValue{
+		Name:     "recife",
+		Count:    math.MinInt64,
+		Big:      math.MaxUint64,
+		Ratio:    -12.5,
+		Enabled:  true,
+		Tags:     []string{"city:42", ""},
+		Nested:   map[string][]int{"a": {1, 2, 3}, "b": {-1}},
+		Children: []codecTestChild{{ID: 1, Attrs: map[string]int{"x": math.MaxInt32}}, {ID: 2, Attrs: map[string]int{"y": -1}}},
+		Created:  time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+	}
+
+	for _, codec := range []Codec{JSONCodec, GobCodec, MsgpackCodec, GzipJSONCodec} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			payload, err := EncodeWithHeader(codec, value)
+			if err != nil {
+				t.Fatalf("EncodeWithHeader() error = %v", err)
+			}
+
+			var decoded codecTestValue
+			if err := DecodeWithHeader(payload, &decoded); err != nil {
+				t.Fatalf("DecodeWithHeader() error = %v", err)
+			}
+			if !decoded.Created.Equal(value.Created) {
+				t.Errorf("Created = %v, want %v", decoded.Created, value.Created)
+			}
+			decoded.Created = value.Created
+			if !reflect.DeepEqual(decoded, value) {
+				t.Errorf("DecodeWithHeader() = %+v, want %+v", decoded, value)
+			}
+		})
+	}
+}
+
+func TestMsgpackCodecGenericValues(t *testing.T) {
+	value := map[string]interface{}{
+		"string": "value",
+		"int":    int64(math.MaxInt64),
+		"float":  1.5,
+		"bool":   false,
+		"null":   nil,
+		"list":   []interface{}{"a", int64(1), []interface{}{true}},
+		"map":    map[string]interface{}{"inner": map[string]interface{}{"deep": "yes"}},
+	}
+
+	data, err := MsgpackCodec.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := MsgpackCodec.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, value) {
+		t.Errorf("Unmarshal() = %#v, want %#v", decoded, value)
+	}
+}
+
+func TestMsgpackCodecUsesJSONTags(t *testing.T) {
+	data, err := MsgpackCodec.Marshal(codecTestChild{ID: 7})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var fields map[string]interface{}
+	if err := MsgpackCodec.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if _, ok := fields["id"]; !ok {
+		t.Errorf("fields = %v, want the json tag name id", fields)
+	}
+}
+
+func TestMsgpackCodecTruncatedInput(t *testing.T) {
+	data, err := MsgpackCodec.Marshal(codecTestValue{Name: "recife", Tags: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	for _, size := range []int{0, 1, len(data) / 2, len(data) - 1} {
+		var decoded codecTestValue
+		if err := MsgpackCodec.Unmarshal(data[:size], &decoded); err == nil {
+			t.Errorf("Unmarshal() of %d/%d bytes succeeded, want an error", size, len(data))
+		}
+	}
+}
+
+func TestDecodeWithHeader(t *testing.T) {
+	var plain map[string]int
+	if err := DecodeWithHeader([]byte(`{"a":1}`), &plain); err != nil || plain["a"] != 1 {
+		t.Errorf("DecodeWithHeader() of plain JSON = %v, %v", plain, err)
+	}
+
+	var dest map[string]int
+	if err := DecodeWithHeader([]byte{payloadMagic0, payloadMagic1, payloadHeaderVersion, 99, '{', '}'}, &dest); err == nil {
+		t.Error("DecodeWithHeader() with an unknown codec succeeded")
+	}
+	if err := DecodeWithHeader([]byte{payloadMagic0, payloadMagic1, 9, byte(CodecIDJSON), '{', '}'}, &dest); err == nil {
+		t.Error("DecodeWithHeader() with an unknown header version succeeded")
+	}
+}
@@ -1,18 +1,83 @@
 package redis
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 )
 
+// Mode is the topology of the Redis deployment
+type Mode string
+
+const (
+	// ModeStandalone connects to a single Redis server
+	ModeStandalone Mode = "standalone"
+	// ModeSentinel connects to the master of a Sentinel monitored deployment and follows its failovers
+	ModeSentinel Mode = "sentinel"
+	// ModeCluster connects to a Redis Cluster, routing each key to the node serving its slot
+	ModeCluster Mode = "cluster"
+)
+
+// ParseMode converts a configuration value into a Mode, empty values are standalone
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case ModeStandalone, ModeSentinel, ModeCluster:
+		return mode, nil
+	case "":
+		return ModeStandalone, nil
+	default:
+		return "", fmt.Errorf("invalid Redis mode: %s", value)
+	}
+}
+
+// ParseAddrs converts a comma separated list of host:port addresses into a slice
+func ParseAddrs(value string) []string {
+	addrs := make([]string, 0)
+	for _, addr := range strings.Split(value, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 // Config represents Redis configuration options
 type Config struct {
-	// Host is the Redis server host
+	// Mode is the topology of the deployment: standalone, sentinel or cluster
+	Mode Mode
+	// Host is the Redis server host, used in standalone mode
 	Host string
-	// Port is the Redis server port
+	// Port is the Redis server port, used in standalone mode
 	Port int
+	// MasterName is the name of the master monitored by the sentinels, used in sentinel mode
+	MasterName string
+	// SentinelAddrs are the host:port addresses of the sentinels, used in sentinel mode
+	SentinelAddrs []string
+	// SentinelUsername is the ACL username of the sentinels, if they require authentication
+	SentinelUsername string
+	// SentinelPassword is the password of the sentinels, if they require authentication
+	SentinelPassword string
+	// ClusterAddrs are the host:port addresses of the seed nodes, used in cluster mode
+	ClusterAddrs []string
+	// Username is the ACL username, empty for the default user
+	Username string
 	// Password is the Redis server password
 	Password string
+	// TLSEnabled enables TLS connections
+	TLSEnabled bool
+	// TLSServerName is the server name verified in the certificates, the host when empty
+	TLSServerName string
+	// TLSCAFile is the PEM file of the certificate authorities trusted in addition to the system ones
+	TLSCAFile string
+	// TLSCertFile is the PEM client certificate file, for mutual TLS
+	TLSCertFile string
+	// TLSKeyFile is the PEM client key file, for mutual TLS
+	TLSKeyFile string
+	// TLSInsecureSkipVerify disables the verification of the server certificates, for development only
+	TLSInsecureSkipVerify bool
 	// Database is the Redis database number
 	Database int
 	// MinIdleConns is the minimum number of idle connections, idle (unused but open) connections
@@ -40,6 +105,7 @@ type Config struct {
 // NewRedisConfig creates a new Redis configuration with default values
 func NewRedisConfig() *Config {
 	return &Config{
+		Mode:            ModeStandalone,
 		Host:            "localhost",
 		Port:            6379,
 		Password:        "",
@@ -57,6 +123,88 @@ func NewRedisConfig() *Config {
 	}
 }
 
+// WithMode sets the topology of the deployment
+func (c *Config) WithMode(mode Mode) *Config {
+	if _, err := ParseMode(string(mode)); err != nil {
+		panic(err.Error())
+	}
+	c.Mode = mode
+	return c
+}
+
+// WithSentinel connects to the master monitored by the sentinels and follows its failovers
+func (c *Config) WithSentinel(masterName string, sentinelAddrs ...string) *Config {
+	if masterName == "" {
+		panic("sentinel master name must not be empty")
+	}
+	if len(sentinelAddrs) == 0 {
+		panic("at least one sentinel address is required")
+	}
+	c.Mode = ModeSentinel
+	c.MasterName = masterName
+	c.SentinelAddrs = sentinelAddrs
+	return c
+}
+
+// WithSentinelAuth sets the credentials of the sentinels
+func (c *Config) WithSentinelAuth(username, password string) *Config {
+	c.SentinelUsername = username
+	c.SentinelPassword = password
+	return c
+}
+
+// WithCluster connects to a Redis Cluster through its seed nodes
+func (c *Config) WithCluster(clusterAddrs ...string) *Config {
+	if len(clusterAddrs) == 0 {
+		panic("at least one cluster address is required")
+	}
+	c.Mode = ModeCluster
+	c.ClusterAddrs = clusterAddrs
+	return c
+}
+
+// WithUsername sets the ACL username
+func (c *Config) WithUsername(username string) *Config {
+	c.Username = username
+	return c
+}
+
+// WithTLS enables or disables TLS connections
+func (c *Config) WithTLS(enabled bool) *Config {
+	c.TLSEnabled = enabled
+	return c
+}
+
+// WithTLSServerName sets the server name verified in the certificates
+func (c *Config) WithTLSServerName(serverName string) *Config {
+	c.TLSServerName = serverName
+	return c
+}
+
+// WithTLSCAFile sets the PEM file of the trusted certificate authorities and enables TLS
+func (c *Config) WithTLSCAFile(caFile string) *Config {
+	c.TLSEnabled = true
+	c.TLSCAFile = caFile
+	return c
+}
+
+// WithTLSClientCert sets the PEM client certificate and key files for mutual TLS and enables TLS
+func (c *Config) WithTLSClientCert(certFile, keyFile string) *Config {
+	if (certFile == "") != (keyFile == "") {
+		panic("TLS client certificate and key files must be set together")
+	}
+	c.TLSEnabled = true
+	c.TLSCertFile = certFile
+	c.TLSKeyFile = keyFile
+	return c
+}
+
+// WithTLSInsecureSkipVerify disables the verification of the server certificates
+func (c *Config) WithTLSInsecureSkipVerify(skip bool) *Config {
+	c.TLSInsecureSkipVerify = skip
+	return c
+}
+
 // WithHost sets the Redis server host
 func (c *Config) WithHost(host string) *Config {
 	c.Host = host
@@ -185,17 +333,97 @@ func DefaultConfig() *Config {
 	return NewRedisConfig()
 }
 
+// GetMode returns the topology of the deployment, standalone when not set
+func (c *Config) GetMode() Mode {
+	if c.Mode == "" {
+		return ModeStandalone
+	}
+	return c.Mode
+}
+
+// Addrs returns the addresses the client connects to: the server, the sentinels or the cluster seed nodes
+func (c *Config) Addrs() []string {
+	switch c.GetMode() {
+	case ModeSentinel:
+		return c.SentinelAddrs
+	case ModeCluster:
+		return c.ClusterAddrs
+	default:
+		return []string{fmt.Sprintf("%s:%d", c.Host, c.Port)}
+	}
+}
+
+// TLSConfig builds the TLS configuration of the connections, nil when TLS is disabled
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if !c.TLSEnabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.TLSServerName,
+		InsecureSkipVerify: c.TLSInsecureSkipVerify,
+	}
+
+	if c.TLSCAFile != "" {
+		pem, err := os.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in TLS CA file %s", c.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.Host == "" {
-		return fmt.Errorf("host cannot be empty")
-	}
-	if c.Port < 1 || c.Port > 65535 {
-		return fmt.Errorf("invalid port: %d, must be between 1 and 65535", c.Port)
+	switch c.GetMode() {
+	case ModeStandalone:
+		if c.Host == "" {
+			return fmt.Errorf("host cannot be empty")
+		}
+		if c.Port < 1 || c.Port > 65535 {
+			return fmt.Errorf("invalid port: %d, must be between 1 and 65535", c.Port)
+		}
+	case ModeSentinel:
+		if c.MasterName == "" {
+			return fmt.Errorf("sentinel master name cannot be empty")
+		}
+		if len(c.SentinelAddrs) == 0 {
+			return fmt.Errorf("at least one sentinel address is required")
+		}
+	case ModeCluster:
+		if len(c.ClusterAddrs) == 0 {
+			return fmt.Errorf("at least one cluster address is required")
+		}
+		if c.Database != 0 {
+			return fmt.Errorf("invalid database: %d, Redis Cluster only supports database 0", c.Database)
+		}
+	default:
+		return fmt.Errorf("invalid mode: %s", c.Mode)
 	}
 	if c.Database < 0 || c.Database > 15 {
 		return fmt.Errorf("invalid database: %d, must be between 0 and 15", c.Database)
 	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS client certificate and key files must be set together")
+	}
 	if c.MinIdleConns < 0 {
 		return fmt.Errorf("invalid min idle connections: %d, must be non-negative", c.MinIdleConns)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -19,7 +20,7 @@ type RedisHealthCheck struct {
 
 // HealthChecker provides Redis health checking functionality
 type HealthChecker struct {
-	client        *redis.Client
+	client        redis.UniversalClient
 	config        *Config
 	lastCheck     time.Time
 	checkInterval time.Duration
@@ -29,7 +30,7 @@ type HealthChecker struct {
 }
 
 // NewHealthChecker creates a new Redis health checker
-func NewHealthChecker(client *redis.Client, config *Config) *HealthChecker {
+func NewHealthChecker(client redis.UniversalClient, config *Config) *HealthChecker {
 	return &HealthChecker{
 		client:        client,
 		config:        config,
@@ -40,20 +41,34 @@ func NewHealthChecker(client *redis.Client, config *Config) *HealthChecker {
 
 // HealthCheck performs a comprehensive health check on the Redis connection
 func (h *HealthChecker) HealthCheck() RedisHealthCheck {
-	h.mu <- struct{}{}        // acquire lock
+	return h.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext performs a comprehensive health check on the Redis connection,
+// each test giving up after 5 seconds or when ctx is done
+func (h *HealthChecker) HealthCheckContext(ctx context.Context) RedisHealthCheck {
+	// Wait for a check in progress no longer than ctx allows
+	select {
+	case h.mu <- struct{}{}: // acquire lock
+	case <-ctx.Done():
+		return RedisHealthCheck{
+			Status:  StatusDown,
+			Details: map[string]string{"last_error": fmt.Sprintf("health check in progress: %v", ctx.Err())},
+		}
+	}
 	defer func() { <-h.mu }() // release lock
 
 	// Test basic connectivity
-	pingResult := h.testPing()
+	pingResult := h.testPing(ctx)
 
 	// Test basic operations
-	operationResult := h.testBasicOperations()
+	operationResult := h.testBasicOperations(ctx)
 
 	// Test connection pool
 	poolResult := h.testConnectionPool()
 
 	// Test memory usage
-	memoryResult := h.testMemoryUsage()
+	memoryResult := h.testMemoryUsage(ctx)
 
 	// Determine overall status
 	var status HealthStatus
@@ -69,8 +84,9 @@ func (h *HealthChecker) HealthCheck() RedisHealthCheck {
 	h.lastCheck = time.Now()
 
 	details := map[string]string{
-		"host":                  h.config.Host,
-		"port":                  strconv.Itoa(h.config.Port),
+		"mode":                  string(h.config.GetMode()),
+		"addresses":             strings.Join(h.config.Addrs(), ","),
+		"tls":                   strconv.FormatBool(h.config.TLSEnabled),
 		"database":              strconv.Itoa(h.config.Database),
 		"min_idle_conns":        strconv.Itoa(h.config.MinIdleConns),
 		"max_retries":           strconv.Itoa(h.config.MaxRetries),
@@ -98,8 +114,8 @@ func (h *HealthChecker) HealthCheck() RedisHealthCheck {
 }
 
 // testPing tests basic connectivity to Redis
-func (h *HealthChecker) testPing() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (h *HealthChecker) testPing(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	err := h.client.Ping(ctx).Err()
@@ -111,8 +127,8 @@ func (h *HealthChecker) testPing() bool {
 }
 
 // testBasicOperations tests basic Redis operations
-func (h *HealthChecker) testBasicOperations() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (h *HealthChecker) testBasicOperations(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	testKey := "health_check_test"
@@ -161,8 +177,8 @@ func (h *HealthChecker) testConnectionPool() bool {
 }
 
 // testMemoryUsage tests if Redis memory operations are accessible
-func (h *HealthChecker) testMemoryUsage() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (h *HealthChecker) testMemoryUsage(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	_, err := h.client.Info(ctx, "memory").Result()
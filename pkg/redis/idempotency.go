@@ -0,0 +1,241 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyMismatch is returned when an idempotency key is reused by a request with a different fingerprint
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was used by a different request")
+	// ErrIdempotencyKeyInProgress is returned when another request still executes under the idempotency key
+	ErrIdempotencyKeyInProgress = errors.New("idempotency key is in use by a request in progress")
+)
+
+// IdempotencyOptions represents options for the idempotency store
+type IdempotencyOptions struct {
+	// Namespace prefixes the keys of the stored responses (Namespace::key)
+	Namespace string
+	// TTL is how long a response is replayed for its key
+	TTL time.Duration
+	// LockTTL is the expiration of the lock held while a request executes, it is refreshed until the request completes
+	LockTTL time.Duration
+	// LockWait is how long a concurrent duplicate waits for the request in progress before ErrIdempotencyKeyInProgress
+	LockWait time.Duration
+	// RetryDelay is the delay between attempts to take the lock while waiting
+	RetryDelay time.Duration
+}
+
+// NewIdempotencyOptions creates new idempotency options with default values
+func NewIdempotencyOptions() *IdempotencyOptions {
+	return &IdempotencyOptions{
+		Namespace:  "idempotency",
+		TTL:        24 * time.Hour,
+		LockTTL:    30 * time.Second,
+		LockWait:   0,
+		RetryDelay: 100 * time.Millisecond,
+	}
+}
+
+// WithNamespace sets the namespace of the stored responses
+func (o *IdempotencyOptions) WithNamespace(namespace string) *IdempotencyOptions {
+	o.Namespace = namespace
+	return o
+}
+
+// WithTTL sets how long a response is replayed
+func (o *IdempotencyOptions) WithTTL(ttl time.Duration) *IdempotencyOptions {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("invalid TTL: %v, must be positive", ttl))
+	}
+	o.TTL = ttl
+	return o
+}
+
+// WithLockTTL sets the expiration of the lock held while a request executes
+func (o *IdempotencyOptions) WithLockTTL(ttl time.Duration) *IdempotencyOptions {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("invalid lock TTL: %v, must be positive", ttl))
+	}
+	o.LockTTL = ttl
+	return o
+}
+
+// WithLockWait sets how long a concurrent duplicate waits for the request in progress
+func (o *IdempotencyOptions) WithLockWait(wait time.Duration) *IdempotencyOptions {
+	if wait < 0 {
+		panic(fmt.Sprintf("invalid lock wait: %v, must be non-negative", wait))
+	}
+	o.LockWait = wait
+	return o
+}
+
+// WithRetryDelay sets the delay between attempts to take the lock
+func (o *IdempotencyOptions) WithRetryDelay(delay time.Duration) *IdempotencyOptions {
+	if delay <= 0 {
+		panic(fmt.Sprintf("invalid retry delay: %v, must be positive", delay))
+	}
+	o.RetryDelay = delay
+	return o
+}
+
+// DefaultIdempotencyOptions returns default idempotency options
+func DefaultIdempotencyOptions() *IdempotencyOptions {
+	return NewIdempotencyOptions()
+}
+
+// IdempotencyRecord is the response stored for an idempotency key
+type IdempotencyRecord struct {
+	// Fingerprint identifies the request that produced the response, a key reused with another fingerprint is rejected
+	Fingerprint string              `json:"fingerprint"`
+	Status      int                 `json:"status"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// IdempotencyStore stores the responses of requests by idempotency key, so retried requests
+// replay the first response instead of executing again. Concurrent duplicates are serialized with a Lock.
+type IdempotencyStore struct {
+	client *Client
+	opts   *IdempotencyOptions
+}
+
+// NewIdempotencyStore creates a new idempotency store
+func NewIdempotencyStore(client *Client, opts *IdempotencyOptions) *IdempotencyStore {
+	if opts == nil {
+		opts = DefaultIdempotencyOptions()
+	}
+	return &IdempotencyStore{
+		client: client,
+		opts:   opts,
+	}
+}
+
+// buildKey constructs the key of a stored response using Namespace::key format
+func (s *IdempotencyStore) buildKey(key string) string {
+	if s.opts.Namespace != "" {
+		return s.opts.Namespace + "::" + key
+	}
+	return key
+}
+
+// lockNamespace is the namespace of the locks held while requests execute
+func (s *IdempotencyStore) lockNamespace() string {
+	return s.buildKey("lock")
+}
+
+// Get returns the response stored for a key, or nil if there is none
+func (s *IdempotencyStore) Get(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	data, err := s.client.GetBytes(ctx, s.buildKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+	}
+	return &record, nil
+}
+
+// Begin starts a request under a key. If a response is stored for the key, it is returned for
+// replay and no claim is taken. Otherwise the returned claim holds the key until the response
+// is stored with Complete or discarded with Release.
+// It returns ErrIdempotencyKeyMismatch if the key was used by a request with another fingerprint,
+// and ErrIdempotencyKeyInProgress if another request still holds the key after LockWait.
+func (s *IdempotencyStore) Begin(ctx context.Context, key, fingerprint string) (*IdempotencyRecord, *IdempotencyClaim, error) {
+	if record, err := s.lookup(ctx, key, fingerprint); record != nil || err != nil {
+		return record, nil, err
+	}
+
+	lockOpts := NewLockOptions().
+		WithTTL(s.opts.LockTTL).
+		WithRefreshInterval(s.opts.LockTTL / 3).
+		WithRetryDelay(s.opts.RetryDelay).
+		WithMaxRetries(int(s.opts.LockWait / s.opts.RetryDelay)).
+		WithLockNamespace(s.lockNamespace()).
+		// Fencing tokens are not used here, the counter of the lock expires with the response
+		WithFencingTokenRetention(s.opts.TTL)
+	lock := NewLock(s.client, key, lockOpts)
+
+	acquired, err := acquireWithRetry(ctx, lockOpts, lock.tryAcquire)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock idempotency key: %w", err)
+	}
+	if !acquired {
+		// The request in progress may have completed while waiting for the last attempt
+		if record, err := s.lookup(ctx, key, fingerprint); record != nil || err != nil {
+			return record, nil, err
+		}
+		return nil, nil, ErrIdempotencyKeyInProgress
+	}
+
+	// The previous holder may have stored its response between the lookup and the lock
+	record, err := s.lookup(ctx, key, fingerprint)
+	if record != nil || err != nil {
+		_ = lock.Unlock(context.WithoutCancel(ctx))
+		return record, nil, err
+	}
+
+	lock.AutoRefresh(context.WithoutCancel(ctx))
+	return nil, &IdempotencyClaim{
+		store:       s,
+		key:         key,
+		fingerprint: fingerprint,
+		lock:        lock,
+	}, nil
+}
+
+// lookup returns the response stored for a key, checking that it was produced by a request with the fingerprint
+func (s *IdempotencyStore) lookup(ctx context.Context, key, fingerprint string) (*IdempotencyRecord, error) {
+	record, err := s.Get(ctx, key)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	if record.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+	return record, nil
+}
+
+// Delete removes the response stored for a key, so the next request with the key executes again
+func (s *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	return s.client.Delete(ctx, s.buildKey(key))
+}
+
+// IdempotencyClaim is the exclusive right of a request to execute under an idempotency key
+type IdempotencyClaim struct {
+	store       *IdempotencyStore
+	key         string
+	fingerprint string
+	lock        *Lock
+}
+
+// Complete stores the response of the request for replay and releases the key
+func (c *IdempotencyClaim) Complete(ctx context.Context, status int, header map[string][]string, body []byte) error {
+	defer c.Release(ctx)
+
+	record := IdempotencyRecord{
+		Fingerprint: c.fingerprint,
+		Status:      status,
+		Header:      header,
+		Body:        body,
+		CreatedAt:   time.Now(),
+	}
+	if err := c.store.client.SetJSON(ctx, c.store.buildKey(c.key), record, c.store.opts.TTL); err != nil {
+		return fmt.Errorf("failed to store idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release releases the key without storing a response, so the request can be retried
+func (c *IdempotencyClaim) Release(ctx context.Context) error {
+	return c.lock.Unlock(ctx)
+}
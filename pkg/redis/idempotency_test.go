@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestIdempotencyStore(t *testing.T, opts *IdempotencyOptions) *IdempotencyStore {
+	t.Helper()
+	client, _ := newTestClient(t)
+	return NewIdempotencyStore(client, opts.WithNamespace("test-idempotency").WithRetryDelay(10*time.Millisecond))
+}
+
+func TestIdempotencyStoreReplaysCompletedResponse(t *testing.T) {
+	store := newTestIdempotencyStore(t, NewIdempotencyOptions())
+	ctx := context.Background()
+
+	record, claim, err := store.Begin(ctx, "orders:key", "fingerprint")
+	if err != nil || record != nil || claim == nil {
+		t.Fatalf("Begin() = %v, %v, %v, want a claim", record, claim, err)
+	}
+	header := map[string][]string{"Location": {"/orders/1"}}
+	if err := claim.Complete(ctx, http.StatusCreated, header, []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	record, claim, err = store.Begin(ctx, "orders:key", "fingerprint")
+	if err != nil || claim != nil || record == nil {
+		t.Fatalf("Begin() after Complete() = %v, %v, %v, want the stored record", record, claim, err)
+	}
+	if record.Status != http.StatusCreated || string(record.Body) != `{"id":1}` || !reflect.DeepEqual(record.Header, header) {
+		t.Errorf("Begin() record = %+v, want the completed response", record)
+	}
+
+	// Deleting the record executes the next request again
+	if err := store.Delete(ctx, "orders:key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if record, claim, err := store.Begin(ctx, "orders:key", "fingerprint"); err != nil || record != nil || claim == nil {
+		t.Errorf("Begin() after Delete() = %v, %v, %v, want a claim", record, claim, err)
+	}
+}
+
+func TestIdempotencyStoreRejectsFingerprintMismatch(t *testing.T) {
+	store := newTestIdempotencyStore(t, NewIdempotencyOptions())
+	ctx := context.Background()
+
+	_, claim, err := store.Begin(ctx, "orders:key", "fingerprint")
+	if err != nil || claim == nil {
+		t.Fatalf("Begin() = %v, %v, want a claim", claim, err)
+	}
+	if err := claim.Complete(ctx, http.StatusCreated, nil, nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	// The middleware answers 422 Unprocessable Entity
+	record, claim, err := store.Begin(ctx, "orders:key", "other-fingerprint")
+	if !errors.Is(err, ErrIdempotencyKeyMismatch) || record != nil || claim != nil {
+		t.Errorf("Begin() with another fingerprint = %v, %v, %v, want ErrIdempotencyKeyMismatch", record, claim, err)
+	}
+}
+
+func TestIdempotencyStoreRejectsRequestInProgress(t *testing.T) {
+	store := newTestIdempotencyStore(t, NewIdempotencyOptions())
+	ctx := context.Background()
+
+	_, claim, err := store.Begin(ctx, "orders:key", "fingerprint")
+	if err != nil || claim == nil {
+		t.Fatalf("Begin() = %v, %v, want a claim", claim, err)
+	}
+
+	// The middleware answers 409 Conflict
+	record, duplicate, err := store.Begin(ctx, "orders:key", "fingerprint")
+	if !errors.Is(err, ErrIdempotencyKeyInProgress) || record != nil || duplicate != nil {
+		t.Fatalf("Begin() while in progress = %v, %v, %v, want ErrIdempotencyKeyInProgress", record, duplicate, err)
+	}
+
+	// Releasing without a response lets the request be retried
+	if err := claim.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	record, retry, err := store.Begin(ctx, "orders:key", "fingerprint")
+	if err != nil || record != nil || retry == nil {
+		t.Errorf("Begin() after Release() = %v, %v, %v, want a claim", record, retry, err)
+	}
+}
+
+func TestIdempotencyStoreWaitsForRequestInProgress(t *testing.T) {
+	store := newTestIdempotencyStore(t, NewIdempotencyOptions().WithLockWait(2*time.Second))
+	ctx := context.Background()
+
+	_, claim, err := store.Begin(ctx, "orders:key", "fingerprint")
+	if err != nil || claim == nil {
+		t.Fatalf("Begin() = %v, %v, want a claim", claim, err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = claim.Complete(ctx, http.StatusAccepted, nil, []byte("done"))
+	}()
+
+	record, duplicate, err := store.Begin(ctx, "orders:key", "fingerprint")
+	if err != nil || duplicate != nil || record == nil {
+		t.Fatalf("Begin() waiting for the request = %v, %v, %v, want the stored record", record, duplicate, err)
+	}
+	if record.Status != http.StatusAccepted || string(record.Body) != "done" {
+		t.Errorf("Begin() record = %+v, want the completed response", record)
+	}
+}
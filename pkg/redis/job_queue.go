@@ -0,0 +1,920 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// MinJobPriority is the lowest priority of a job
+	MinJobPriority = -100
+	// MaxJobPriority is the highest priority of a job
+	MaxJobPriority = 100
+)
+
+// ErrPermanentJobFailure is wrapped by handler errors that must not be retried, the job is dead-lettered at once
+var ErrPermanentJobFailure = errors.New("permanent job failure")
+
+// luaJobReadyScore defines readyScore(job, at), the score of a job in the ready set: jobs of a
+// higher priority come first, then jobs in the order they became ready
+const luaJobReadyScore = `
+	local function readyScore(data, at)
+		local priority = tonumber(cjson.decode(data).priority) or 0
+		return -priority * 10000000000000 + at
+	end
+`
+
+// enqueueJobScript stores a job (ARGV[2]) under its ID (ARGV[1]) in the jobs hash (KEYS[1]) unless the
+// ID exists, and adds it to the ready set (KEYS[2]) or to the scheduled set (KEYS[3]) until ARGV[3]
+var enqueueJobScript = RegisterScript("job-queue.enqueue", luaNowMs+luaJobReadyScore+`
+	if redis.call("HSETNX", KEYS[1], ARGV[1], ARGV[2]) == 0 then
+		return 0
+	end
+	local runAt = tonumber(ARGV[3])
+	if runAt > now then
+		redis.call("ZADD", KEYS[3], runAt, ARGV[1])
+	else
+		redis.call("ZADD", KEYS[2], readyScore(ARGV[2], now), ARGV[1])
+	end
+	return 1
+`)
+
+// dequeueJobsScript makes the due scheduled jobs (KEYS[3]) and the jobs with an expired lease (KEYS[4])
+// ready (KEYS[2]), then leases up to ARGV[1] ready jobs for ARGV[2] milliseconds under the lease ARGV[3].
+// A job delivered more than its max attempts, because its workers crashed, is dead-lettered (KEYS[5]).
+var dequeueJobsScript = RegisterScript("job-queue.dequeue", luaNowMs+luaJobReadyScore+`
+	for _, source in ipairs({KEYS[3], KEYS[4]}) do
+		local due = redis.call("ZRANGEBYSCORE", source, "-inf", now, "LIMIT", 0, 1000)
+		for _, id in ipairs(due) do
+			redis.call("ZREM", source, id)
+			local data = redis.call("HGET", KEYS[1], id)
+			if data then
+				redis.call("ZADD", KEYS[2], readyScore(data, now), id)
+			end
+		end
+	end
+
+	local jobs = {}
+	while #jobs < tonumber(ARGV[1]) do
+		local popped = redis.call("ZPOPMIN", KEYS[2])
+		if #popped == 0 then
+			break
+		end
+		local id = popped[1]
+		local data = redis.call("HGET", KEYS[1], id)
+		if data then
+			local job = cjson.decode(data)
+			job.attempts = (tonumber(job.attempts) or 0) + 1
+			local maxAttempts = tonumber(job.max_attempts) or 0
+			if maxAttempts > 0 and job.attempts > maxAttempts then
+				job.lease = nil
+				job.last_error = "lease expired too many times"
+				redis.call("HSET", KEYS[1], id, cjson.encode(job))
+				redis.call("ZADD", KEYS[5], now, id)
+			else
+				job.lease = ARGV[3] .. ":" .. id
+				data = cjson.encode(job)
+				redis.call("HSET", KEYS[1], id, data)
+				redis.call("ZADD", KEYS[4], now + tonumber(ARGV[2]), id)
+				table.insert(jobs, data)
+			end
+		end
+	end
+	return jobs
+`)
+
+// completeJobScript deletes a job (ARGV[1]) from the jobs hash (KEYS[1]) and the leased set (KEYS[2])
+// if it is still leased under ARGV[2]
+var completeJobScript = RegisterScript("job-queue.complete", `
+	local data = redis.call("HGET", KEYS[1], ARGV[1])
+	if not data or cjson.decode(data).lease ~= ARGV[2] then
+		return 0
+	end
+	redis.call("ZREM", KEYS[2], ARGV[1])
+	redis.call("HDEL", KEYS[1], ARGV[1])
+	return 1
+`)
+
+// retryJobScript releases a job (ARGV[1]) leased under ARGV[2] after a failure (ARGV[4]). The job is
+// scheduled (KEYS[2]) again in ARGV[3] milliseconds, or dead-lettered (KEYS[4]) when ARGV[5] is 1 or
+// it has no attempt left. It returns 1 when retried, 2 when dead-lettered and 0 when the lease was lost.
+var retryJobScript = RegisterScript("job-queue.retry", luaNowMs+`
+	local data = redis.call("HGET", KEYS[1], ARGV[1])
+	if not data then
+		return 0
+	end
+	local job = cjson.decode(data)
+	if job.lease ~= ARGV[2] then
+		return 0
+	end
+	job.lease = nil
+	job.last_error = ARGV[4]
+	redis.call("ZREM", KEYS[3], ARGV[1])
+	redis.call("HSET", KEYS[1], ARGV[1], cjson.encode(job))
+
+	local maxAttempts = tonumber(job.max_attempts) or 0
+	if ARGV[5] == "1" or (maxAttempts > 0 and (tonumber(job.attempts) or 0) >= maxAttempts) then
+		redis.call("ZADD", KEYS[4], now, ARGV[1])
+		return 2
+	end
+	redis.call("ZADD", KEYS[2], now + tonumber(ARGV[3]), ARGV[1])
+	return 1
+`)
+
+// extendJobLeaseScript extends the lease ARGV[2] of a job (ARGV[1]) in the leased set (KEYS[2]) to ARGV[3] milliseconds
+var extendJobLeaseScript = RegisterScript("job-queue.extend-lease", luaNowMs+`
+	local data = redis.call("HGET", KEYS[1], ARGV[1])
+	if not data or cjson.decode(data).lease ~= ARGV[2] then
+		return 0
+	end
+	redis.call("ZADD", KEYS[2], "XX", now + tonumber(ARGV[3]), ARGV[1])
+	return 1
+`)
+
+// redriveJobsScript makes the dead-lettered jobs (KEYS[3]) ready (KEYS[2]) again with their attempts reset
+var redriveJobsScript = RegisterScript("job-queue.redrive", luaNowMs+luaJobReadyScore+`
+	local ids = redis.call("ZRANGE", KEYS[3], 0, -1)
+	for _, id in ipairs(ids) do
+		local data = redis.call("HGET", KEYS[1], id)
+		if data then
+			local job = cjson.decode(data)
+			job.attempts = 0
+			data = cjson.encode(job)
+			redis.call("HSET", KEYS[1], id, data)
+			redis.call("ZADD", KEYS[2], readyScore(data, now), id)
+		end
+	end
+	redis.call("DEL", KEYS[3])
+	return #ids
+`)
+
+// Job is a unit of work of a JobQueue
+type Job struct {
+	ID       string `json:"id"`
+	Queue    string `json:"queue"`
+	Payload  string `json:"payload"`
+	Priority int    `json:"priority"`
+	// Attempts is the number of deliveries of the job, including the current one
+	Attempts    int               `json:"attempts"`
+	MaxAttempts int               `json:"max_attempts"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	EnqueuedAt  time.Time         `json:"enqueued_at"`
+	RunAt       time.Time         `json:"run_at"`
+	// LastError is the error of the last failed attempt
+	LastError string `json:"last_error,omitempty"`
+	// Lease identifies the current delivery, only its holder can complete or retry the job
+	Lease string `json:"lease,omitempty"`
+}
+
+// DecodePayload unmarshals the JSON payload of the job into dest
+func (j *Job) DecodePayload(dest interface{}) error {
+	if err := json.Unmarshal([]byte(j.Payload), dest); err != nil {
+		return fmt.Errorf("failed to decode payload of job %s: %w", j.ID, err)
+	}
+	return nil
+}
+
+// JobHandler defines an interface that processes a job, mirroring sqs.ContextHandler.
+// A job whose handler returns an error is retried with backoff until MaxAttempts.
+type JobHandler interface {
+	HandleJob(ctx context.Context, job *Job) error
+}
+
+// JobHandlerFunc defines a function that handles a job
+type JobHandlerFunc func(ctx context.Context, job *Job) error
+
+var _ JobHandler = JobHandlerFunc(nil)
+
+// HandleJob implements the JobHandler interface for JobHandlerFunc
+func (f JobHandlerFunc) HandleJob(ctx context.Context, job *Job) error {
+	return f(ctx, job)
+}
+
+// JobOptions represents per-job options of JobQueue.Enqueue
+type JobOptions struct {
+	// ID identifies the job, a job is not enqueued again while a job with the same ID exists
+	ID string
+	// Priority orders the ready jobs, from MaxJobPriority to MinJobPriority
+	Priority int
+	// Delay postpones the job
+	Delay time.Duration
+	// RunAt schedules the job, it takes precedence over Delay
+	RunAt time.Time
+	// MaxAttempts overrides the MaxAttempts of the queue
+	MaxAttempts int
+	// Attributes are metadata delivered with the job
+	Attributes map[string]string
+}
+
+// NewJobOptions creates new job options with default values
+func NewJobOptions() *JobOptions {
+	return &JobOptions{}
+}
+
+// WithID sets the ID of the job
+func (jo *JobOptions) WithID(id string) *JobOptions {
+	jo.ID = id
+	return jo
+}
+
+// WithPriority sets the priority of the job
+func (jo *JobOptions) WithPriority(priority int) *JobOptions {
+	if priority < MinJobPriority || priority > MaxJobPriority {
+		panic(fmt.Sprintf("invalid priority: %d, must be between %d and %d", priority, MinJobPriority, MaxJobPriority))
+	}
+	jo.Priority = priority
+	return jo
+}
+
+// WithDelay postpones the job
+func (jo *JobOptions) WithDelay(delay time.Duration) *JobOptions {
+	if delay < 0 {
+		panic(fmt.Sprintf("invalid delay: %v, must be non-negative", delay))
+	}
+	jo.Delay = delay
+	return jo
+}
+
+// WithRunAt schedules the job
+func (jo *JobOptions) WithRunAt(runAt time.Time) *JobOptions {
+	jo.RunAt = runAt
+	return jo
+}
+
+// WithMaxAttempts sets the number of attempts of the job
+func (jo *JobOptions) WithMaxAttempts(maxAttempts int) *JobOptions {
+	if maxAttempts < 1 {
+		panic(fmt.Sprintf("invalid max attempts: %d, must be greater than 0", maxAttempts))
+	}
+	jo.MaxAttempts = maxAttempts
+	return jo
+}
+
+// WithAttribute adds an attribute to the job
+func (jo *JobOptions) WithAttribute(name, value string) *JobOptions {
+	if jo.Attributes == nil {
+		jo.Attributes = make(map[string]string)
+	}
+	jo.Attributes[name] = value
+	return jo
+}
+
+// JobQueueConfig defines the configuration options for job queues and their workers
+type JobQueueConfig struct {
+	// Namespace is the namespace for organizing queues
+	Namespace string
+	// PoolSize is the number of jobs a worker handles concurrently
+	PoolSize int
+	// LogLevel controls the logging verbosity
+	LogLevel LogLevel
+	// PollInterval is the delay between polls of an idle worker
+	PollInterval time.Duration
+	// VisibilityTimeout is the lease of a delivered job, it is extended while the handler runs.
+	// A job whose lease expires, because its worker crashed, is delivered again.
+	VisibilityTimeout time.Duration
+	// MaxAttempts is the number of deliveries after which a failing job is dead-lettered
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry, it doubles on each attempt
+	RetryBackoff time.Duration
+	// MaxRetryBackoff caps the delay between retries
+	MaxRetryBackoff time.Duration
+}
+
+// NewJobQueueConfig creates a new job queue configuration with default values
+func NewJobQueueConfig() *JobQueueConfig {
+	return &JobQueueConfig{
+		Namespace:         "jobs",
+		PoolSize:          1,
+		LogLevel:          InfoLevel,
+		PollInterval:      1 * time.Second,
+		VisibilityTimeout: 30 * time.Second,
+		MaxAttempts:       5,
+		RetryBackoff:      1 * time.Second,
+		MaxRetryBackoff:   5 * time.Minute,
+	}
+}
+
+// WithNamespace sets the namespace for organizing queues
+func (jc *JobQueueConfig) WithNamespace(namespace string) *JobQueueConfig {
+	jc.Namespace = namespace
+	return jc
+}
+
+// WithPoolSize sets the number of jobs a worker handles concurrently
+func (jc *JobQueueConfig) WithPoolSize(poolSize int) *JobQueueConfig {
+	if poolSize < 1 {
+		panic(fmt.Sprintf("invalid pool size: %d, must be greater than 0", poolSize))
+	}
+	jc.PoolSize = poolSize
+	return jc
+}
+
+// WithLogLevel sets the logging verbosity
+func (jc *JobQueueConfig) WithLogLevel(logLevel LogLevel) *JobQueueConfig {
+	jc.LogLevel = logLevel
+	return jc
+}
+
+// WithPollInterval sets the delay between polls of an idle worker
+func (jc *JobQueueConfig) WithPollInterval(interval time.Duration) *JobQueueConfig {
+	if interval <= 0 {
+		panic(fmt.Sprintf("invalid poll interval: %v, must be positive", interval))
+	}
+	jc.PollInterval = interval
+	return jc
+}
+
+// WithVisibilityTimeout sets the lease of a delivered job
+func (jc *JobQueueConfig) WithVisibilityTimeout(timeout time.Duration) *JobQueueConfig {
+	if timeout < time.Second {
+		panic(fmt.Sprintf("invalid visibility timeout: %v, must be at least 1s", timeout))
+	}
+	jc.VisibilityTimeout = timeout
+	return jc
+}
+
+// WithMaxAttempts sets the number of deliveries after which a failing job is dead-lettered
+func (jc *JobQueueConfig) WithMaxAttempts(maxAttempts int) *JobQueueConfig {
+	if maxAttempts < 1 {
+		panic(fmt.Sprintf("invalid max attempts: %d, must be greater than 0", maxAttempts))
+	}
+	jc.MaxAttempts = maxAttempts
+	return jc
+}
+
+// WithRetryBackoff sets the delay before the first retry and the maximum delay between retries
+func (jc *JobQueueConfig) WithRetryBackoff(backoff, maxBackoff time.Duration) *JobQueueConfig {
+	if backoff < 0 || maxBackoff < backoff {
+		panic(fmt.Sprintf("invalid retry backoff: %v up to %v", backoff, maxBackoff))
+	}
+	jc.RetryBackoff = backoff
+	jc.MaxRetryBackoff = maxBackoff
+	return jc
+}
+
+// retryDelay returns the delay before retrying a job that failed its attempt, with jitter so
+// jobs failing together are not retried together
+func (jc *JobQueueConfig) retryDelay(attempt int) time.Duration {
+	delay := jc.RetryBackoff
+	for i := 1; i < attempt && delay < jc.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, jc.MaxRetryBackoff)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// JobQueueStats represents the number of jobs of a queue by state
+type JobQueueStats struct {
+	Ready        int64 `json:"ready"`
+	Scheduled    int64 `json:"scheduled"`
+	Leased       int64 `json:"leased"`
+	DeadLettered int64 `json:"dead_lettered"`
+}
+
+// JobQueue is a Redis job queue with delayed and scheduled jobs, priorities, retries with
+// backoff and visibility leases. The keys of a queue share a cluster slot ({Namespace::queue}::...).
+type JobQueue struct {
+	client redis.UniversalClient
+	name   string
+	config *JobQueueConfig
+}
+
+// NewJobQueue creates a job queue. A nil config uses the defaults of NewJobQueueConfig.
+func NewJobQueue(client redis.UniversalClient, name string, config *JobQueueConfig) *JobQueue {
+	if config == nil {
+		config = NewJobQueueConfig()
+	}
+	return &JobQueue{
+		client: client,
+		name:   name,
+		config: config,
+	}
+}
+
+// Name returns the name of the queue
+func (q *JobQueue) Name() string {
+	return q.name
+}
+
+// buildQueueKey constructs the full queue name using Namespace::queue format
+func (q *JobQueue) buildQueueKey() string {
+	if q.config.Namespace != "" {
+		return q.config.Namespace + "::" + q.name
+	}
+	return q.name
+}
+
+// jobsKey is the hash holding the jobs by ID
+func (q *JobQueue) jobsKey() string {
+	return slotKey(q.buildQueueKey(), "jobs")
+}
+
+// readyKey is the sorted set of the jobs waiting for a worker
+func (q *JobQueue) readyKey() string {
+	return slotKey(q.buildQueueKey(), "ready")
+}
+
+// scheduledKey is the sorted set of the delayed jobs and of the jobs waiting for a retry, by run time
+func (q *JobQueue) scheduledKey() string {
+	return slotKey(q.buildQueueKey(), "scheduled")
+}
+
+// leasedKey is the sorted set of the delivered jobs, by lease expiration
+func (q *JobQueue) leasedKey() string {
+	return slotKey(q.buildQueueKey(), "leased")
+}
+
+// deadLetterKey is the sorted set of the jobs that exhausted their attempts
+func (q *JobQueue) deadLetterKey() string {
+	return slotKey(q.buildQueueKey(), "dead-letter")
+}
+
+// Enqueue adds a job with a string payload. It returns the job, or nil without error when a
+// job with the same ID exists. A nil opts enqueues a job ready now with the default priority.
+func (q *JobQueue) Enqueue(ctx context.Context, payload string, opts *JobOptions) (*Job, error) {
+	if opts == nil {
+		opts = NewJobOptions()
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          opts.ID,
+		Queue:       q.name,
+		Payload:     payload,
+		Priority:    opts.Priority,
+		MaxAttempts: opts.MaxAttempts,
+		Attributes:  opts.Attributes,
+		EnqueuedAt:  now,
+		RunAt:       opts.RunAt,
+	}
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = q.config.MaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = now.Add(opts.Delay)
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	// Jobs due now are ready at once, the Redis clock decides for the others
+	runAt := int64(0)
+	if job.RunAt.After(now) {
+		runAt = job.RunAt.UnixMilli()
+	}
+	added, err := enqueueJobScript.Run(ctx, q.client, []string{q.jobsKey(), q.readyKey(), q.scheduledKey()},
+		job.ID, data, runAt).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job in %s: %w", q.name, err)
+	}
+	if added == 0 {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// EnqueueJSON adds a job with a payload marshaled to JSON
+func (q *JobQueue) EnqueueJSON(ctx context.Context, payload interface{}, opts *JobOptions) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+	return q.Enqueue(ctx, string(data), opts)
+}
+
+// Dequeue leases up to count jobs for the visibility timeout, highest priority first.
+// A job must be completed, retried or have its lease extended before the lease expires,
+// otherwise it is delivered again.
+func (q *JobQueue) Dequeue(ctx context.Context, count int, visibilityTimeout time.Duration) ([]*Job, error) {
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = q.config.VisibilityTimeout
+	}
+
+	results, err := dequeueJobsScript.Run(ctx, q.client,
+		[]string{q.jobsKey(), q.readyKey(), q.scheduledKey(), q.leasedKey(), q.deadLetterKey()},
+		count, visibilityTimeout.Milliseconds(), uuid.NewString()).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue jobs from %s: %w", q.name, err)
+	}
+
+	jobs := make([]*Job, 0, len(results))
+	for _, data := range results {
+		job, err := decodeJob(data)
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Complete deletes a job whose handler succeeded. It returns false if the lease of the job was lost.
+func (q *JobQueue) Complete(ctx context.Context, job *Job) (bool, error) {
+	completed, err := completeJobScript.Run(ctx, q.client, []string{q.jobsKey(), q.leasedKey()}, job.ID, job.Lease).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to complete job %s: %w", job.ID, err)
+	}
+	return completed == 1, nil
+}
+
+// Retry releases a job whose attempt failed, so it runs again after delay. A job without attempts
+// left, or whose cause wraps ErrPermanentJobFailure, is dead-lettered. It reports whether the job was
+// dead-lettered, and returns false for both when the lease of the job was lost.
+func (q *JobQueue) Retry(ctx context.Context, job *Job, cause error, delay time.Duration) (retried bool, deadLettered bool, err error) {
+	permanent := "0"
+	if errors.Is(cause, ErrPermanentJobFailure) {
+		permanent = "1"
+	}
+	lastError := ""
+	if cause != nil {
+		lastError = cause.Error()
+	}
+
+	result, err := retryJobScript.Run(ctx, q.client,
+		[]string{q.jobsKey(), q.scheduledKey(), q.leasedKey(), q.deadLetterKey()},
+		job.ID, job.Lease, delay.Milliseconds(), lastError, permanent).Int64()
+	if err != nil {
+		return false, false, fmt.Errorf("failed to retry job %s: %w", job.ID, err)
+	}
+	return result == 1, result == 2, nil
+}
+
+// ExtendLease extends the lease of a job to timeout from now. It returns false if the lease was lost.
+func (q *JobQueue) ExtendLease(ctx context.Context, job *Job, timeout time.Duration) (bool, error) {
+	extended, err := extendJobLeaseScript.Run(ctx, q.client, []string{q.jobsKey(), q.leasedKey()},
+		job.ID, job.Lease, timeout.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to extend the lease of job %s: %w", job.ID, err)
+	}
+	return extended == 1, nil
+}
+
+// Get returns a job of the queue, or nil if it does not exist or was completed
+func (q *JobQueue) Get(ctx context.Context, id string) (*Job, error) {
+	data, err := q.client.HGet(ctx, q.jobsKey(), id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", id, err)
+	}
+	return decodeJob(data)
+}
+
+// DeadLetters returns up to count dead-lettered jobs, oldest first
+func (q *JobQueue) DeadLetters(ctx context.Context, count int64) ([]*Job, error) {
+	ids, err := q.client.ZRange(ctx, q.deadLetterKey(), 0, count-1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	values, err := q.client.HMGet(ctx, q.jobsKey(), ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(values))
+	for _, value := range values {
+		if data, ok := value.(string); ok {
+			job, err := decodeJob(data)
+			if err != nil {
+				return jobs, err
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// RedriveDeadLetters makes every dead-lettered job ready again with its attempts reset
+func (q *JobQueue) RedriveDeadLetters(ctx context.Context) (int64, error) {
+	return redriveJobsScript.Run(ctx, q.client, []string{q.jobsKey(), q.readyKey(), q.deadLetterKey()}).Int64()
+}
+
+// Stats returns the number of jobs of the queue by state
+func (q *JobQueue) Stats(ctx context.Context) (JobQueueStats, error) {
+	pipe := q.client.Pipeline()
+	ready := pipe.ZCard(ctx, q.readyKey())
+	scheduled := pipe.ZCard(ctx, q.scheduledKey())
+	leased := pipe.ZCard(ctx, q.leasedKey())
+	deadLettered := pipe.ZCard(ctx, q.deadLetterKey())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return JobQueueStats{}, fmt.Errorf("failed to get stats of %s: %w", q.name, err)
+	}
+
+	return JobQueueStats{
+		Ready:        ready.Val(),
+		Scheduled:    scheduled.Val(),
+		Leased:       leased.Val(),
+		DeadLettered: deadLettered.Val(),
+	}, nil
+}
+
+// Purge deletes every job of the queue
+func (q *JobQueue) Purge(ctx context.Context) error {
+	keys := []string{q.jobsKey(), q.readyKey(), q.scheduledKey(), q.leasedKey(), q.deadLetterKey()}
+	return q.client.Del(ctx, keys...).Err()
+}
+
+// decodeJob unmarshals a job stored by the queue
+func decodeJob(data string) (*Job, error) {
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+	return &job, nil
+}
+
+// JobWorkerHealthCheck represents the health check response for a job worker
+type JobWorkerHealthCheck struct {
+	Status  HealthStatus      `json:"status"`
+	Details map[string]string `json:"details"`
+}
+
+// JobWorker handles the jobs of a queue with a pool of PoolSize concurrent handlers.
+// The lease of a job is extended while its handler runs; a failed job is retried with
+// exponential backoff and dead-lettered after MaxAttempts.
+type JobWorker struct {
+	queue            *JobQueue
+	config           *JobQueueConfig
+	handler          JobHandler
+	isRunning        int32 // atomic flag to track if worker is running
+	jobsProcessed    int64 // atomic counter for completed jobs
+	jobsFailed       int64 // atomic counter for failed attempts
+	jobsRetried      int64 // atomic counter for scheduled retries
+	jobsDeadLettered int64 // atomic counter for dead-lettered jobs
+	jobsInFlight     int64 // atomic gauge of the jobs being handled
+	lastPoll         int64 // unix nanoseconds of the last successful poll
+	wg               sync.WaitGroup
+	ctx              context.Context    // internal context for lifecycle management
+	cancel           context.CancelFunc // cancel function to stop worker
+}
+
+// NewJobWorker creates and returns a new JobWorker.
+//
+// If the provided JobQueueConfig is nil or its fields are zero, the defaults of
+// NewJobQueueConfig are used, except LogLevel which defaults to Silent as for NewSubscriber.
+func NewJobWorker(client redis.UniversalClient, queueName string, handler JobHandler, config *JobQueueConfig) (*JobWorker, error) {
+	defaults := NewJobQueueConfig()
+	defaults.LogLevel = Silent
+	if config != nil {
+		merged := *config
+		if merged.PoolSize == 0 {
+			merged.PoolSize = defaults.PoolSize
+		}
+		if merged.LogLevel == 0 {
+			merged.LogLevel = defaults.LogLevel
+		}
+		if merged.PollInterval == 0 {
+			merged.PollInterval = defaults.PollInterval
+		}
+		if merged.VisibilityTimeout == 0 {
+			merged.VisibilityTimeout = defaults.VisibilityTimeout
+		}
+		if merged.MaxAttempts == 0 {
+			merged.MaxAttempts = defaults.MaxAttempts
+		}
+		if merged.MaxRetryBackoff == 0 {
+			merged.MaxRetryBackoff = max(defaults.MaxRetryBackoff, merged.RetryBackoff)
+		}
+		defaults = &merged
+	}
+	config = defaults
+
+	if queueName == "" {
+		return nil, fmt.Errorf("queue name is required")
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("handler cannot be nil")
+	}
+	if config.PoolSize < 1 {
+		return nil, fmt.Errorf("pool size must be greater than 0")
+	}
+	if config.VisibilityTimeout < time.Second {
+		return nil, fmt.Errorf("visibility timeout must be at least 1s")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &JobWorker{
+		queue:   NewJobQueue(client, queueName, config),
+		config:  config,
+		handler: handler,
+		ctx:     ctx,
+		cancel:  cancel,
+	}, nil
+}
+
+// Queue returns the queue of the worker, to enqueue jobs or read its stats
+func (w *JobWorker) Queue() *JobQueue {
+	return w.queue
+}
+
+// Start begins handling jobs with PoolSize concurrent handlers, until the provided context
+// is canceled or Stop() is called
+func (w *JobWorker) Start(ctx context.Context) {
+	atomic.StoreInt64(&w.lastPoll, time.Now().UnixNano())
+	atomic.StoreInt32(&w.isRunning, 1)
+	defer atomic.StoreInt32(&w.isRunning, 0)
+
+	// Create a combined context that responds to both the provided context and internal cancellation
+	combinedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Monitor internal context for Stop() calls
+	go func() {
+		select {
+		case <-w.ctx.Done():
+			cancel()
+		case <-combinedCtx.Done():
+		}
+	}()
+
+	for i := 0; i < w.config.PoolSize; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.pollJobs(combinedCtx)
+		}()
+	}
+
+	w.wg.Wait()
+}
+
+// pollJobs leases and handles one job at a time until the context is cancelled
+func (w *JobWorker) pollJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		jobs, err := w.queue.Dequeue(ctx, 1, w.config.VisibilityTimeout)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logf(ErrorLevel, "failed to dequeue jobs from %s: %v", w.queue.name, err)
+			}
+			sleepContext(ctx, w.config.PollInterval)
+			continue
+		}
+		atomic.StoreInt64(&w.lastPoll, time.Now().UnixNano())
+
+		if len(jobs) == 0 {
+			sleepContext(ctx, w.config.PollInterval)
+			continue
+		}
+		for _, job := range jobs {
+			w.handleJob(ctx, job)
+		}
+	}
+}
+
+// handleJob runs the handler of a job while extending its lease, then completes or retries the job
+func (w *JobWorker) handleJob(ctx context.Context, job *Job) {
+	atomic.AddInt64(&w.jobsInFlight, 1)
+	defer atomic.AddInt64(&w.jobsInFlight, -1)
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		w.extendLease(jobCtx, job)
+	}()
+
+	err := w.handler.HandleJob(jobCtx, job)
+	cancel()
+	<-refreshed
+
+	// The job outcome is recorded even when the worker is stopping
+	ctx = context.WithoutCancel(ctx)
+	if err == nil {
+		completed, err := w.queue.Complete(ctx, job)
+		if err != nil || !completed {
+			w.logf(ErrorLevel, "failed to complete job %s of %s (lease lost: %t): %v", job.ID, w.queue.name, !completed, err)
+			return
+		}
+		atomic.AddInt64(&w.jobsProcessed, 1)
+		w.logf(InfoLevel, "successfully processed job %s of %s", job.ID, w.queue.name)
+		return
+	}
+
+	atomic.AddInt64(&w.jobsFailed, 1)
+	w.logf(ErrorLevel, "error processing job %s of %s (attempt %d/%d): %v", job.ID, w.queue.name, job.Attempts, job.MaxAttempts, err)
+
+	retried, deadLettered, retryErr := w.queue.Retry(ctx, job, err, w.config.retryDelay(job.Attempts))
+	switch {
+	case retryErr != nil:
+		w.logf(ErrorLevel, "failed to release job %s of %s, it is retried when its lease expires: %v", job.ID, w.queue.name, retryErr)
+	case deadLettered:
+		atomic.AddInt64(&w.jobsDeadLettered, 1)
+		w.logf(ErrorLevel, "job %s of %s dead-lettered after %d attempts", job.ID, w.queue.name, job.Attempts)
+	case retried:
+		atomic.AddInt64(&w.jobsRetried, 1)
+	}
+}
+
+// extendLease extends the lease of a job every third of the visibility timeout until the context is cancelled
+func (w *JobWorker) extendLease(ctx context.Context, job *Job) {
+	ticker := time.NewTicker(w.config.VisibilityTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			extended, err := w.queue.ExtendLease(ctx, job, w.config.VisibilityTimeout)
+			if err != nil && ctx.Err() == nil {
+				w.logf(ErrorLevel, "failed to extend the lease of job %s of %s: %v", job.ID, w.queue.name, err)
+			}
+			if err == nil && !extended {
+				w.logf(ErrorLevel, "lease of job %s of %s was lost, it may run twice", job.ID, w.queue.name)
+				return
+			}
+		}
+	}
+}
+
+// Stop gracefully stops the worker by canceling its internal context.
+// Jobs being handled see their context cancelled and are retried if their handler fails.
+// It's safe to call Stop() multiple times.
+func (w *JobWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// Close stops the worker and waits for the jobs being handled
+func (w *JobWorker) Close() error {
+	w.Stop()
+	w.wg.Wait()
+	atomic.StoreInt32(&w.isRunning, 0)
+	return nil
+}
+
+// logf logs messages based on the configured log level
+func (w *JobWorker) logf(level LogLevel, format string, v ...interface{}) {
+	logAtLevel(w.config.LogLevel, level, format, v...)
+}
+
+// HealthCheck returns the health status and details of the job worker
+func (w *JobWorker) HealthCheck() JobWorkerHealthCheck {
+	isRunning := atomic.LoadInt32(&w.isRunning) == 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Test Redis connectivity
+	redisAvailable := w.queue.client.Ping(ctx).Err() == nil
+
+	// Determine status based on both running state and Redis connectivity
+	status := StatusDown
+	if isRunning && redisAvailable {
+		status = StatusUp
+	}
+
+	details := map[string]string{
+		"queue":              w.queue.name,
+		"namespace":          w.config.Namespace,
+		"pool_size":          strconv.Itoa(w.config.PoolSize),
+		"log_level":          logLevelString(w.config.LogLevel),
+		"poll_interval":      w.config.PollInterval.String(),
+		"visibility_timeout": w.config.VisibilityTimeout.String(),
+		"max_attempts":       strconv.Itoa(w.config.MaxAttempts),
+		"is_running":         strconv.FormatBool(isRunning),
+		"jobs_processed":     strconv.FormatInt(atomic.LoadInt64(&w.jobsProcessed), 10),
+		"jobs_failed":        strconv.FormatInt(atomic.LoadInt64(&w.jobsFailed), 10),
+		"jobs_retried":       strconv.FormatInt(atomic.LoadInt64(&w.jobsRetried), 10),
+		"jobs_dead_lettered": strconv.FormatInt(atomic.LoadInt64(&w.jobsDeadLettered), 10),
+		"jobs_in_flight":     strconv.FormatInt(atomic.LoadInt64(&w.jobsInFlight), 10),
+		"redis_available":    strconv.FormatBool(redisAvailable),
+	}
+	if lastPoll := atomic.LoadInt64(&w.lastPoll); lastPoll != 0 {
+		details["last_poll"] = time.Unix(0, lastPoll).UTC().Format(time.RFC3339)
+	}
+	if redisAvailable {
+		if stats, err := w.queue.Stats(ctx); err == nil {
+			details["jobs_ready"] = strconv.FormatInt(stats.Ready, 10)
+			details["jobs_scheduled"] = strconv.FormatInt(stats.Scheduled, 10)
+			details["jobs_leased"] = strconv.FormatInt(stats.Leased, 10)
+			details["jobs_in_dead_letter"] = strconv.FormatInt(stats.DeadLettered, 10)
+		}
+	}
+
+	return JobWorkerHealthCheck{
+		Status:  status,
+		Details: details,
+	}
+}
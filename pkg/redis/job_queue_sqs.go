@@ -0,0 +1,317 @@
+package redis
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/redis/go-redis/v9"
+
+	pkgsqs "go-api/pkg/sqs"
+)
+
+const (
+	// jobQueueURLScheme prefixes the queue URLs of JobQueueSQSClient
+	jobQueueURLScheme = "redis://"
+	// jobMessageGroupAttribute is the job attribute holding the SQS message group
+	jobMessageGroupAttribute = "sqs.MessageGroupId"
+	// stringDataType is the SQS data type of the message attributes delivered by JobQueueSQSClient
+	stringDataType = "String"
+)
+
+// JobQueueSQSClient implements sqs.Client on top of job queues, so sqs.Sender and sqs.Worker run against
+// Redis in development. Queues need no creation, visibility timeouts and receive counts behave as in SQS,
+// and messages received MaxAttempts times are dead-lettered as with a redrive policy. Message attributes
+// are delivered as strings, and messages of a group are ordered by the worker but not by the queue.
+type JobQueueSQSClient struct {
+	client redis.UniversalClient
+	config *JobQueueConfig
+}
+
+var _ pkgsqs.Client = (*JobQueueSQSClient)(nil)
+
+// NewJobQueueSQSClient creates a SQS client storing its queues in Redis. A nil config uses the
+// defaults of NewJobQueueConfig.
+func NewJobQueueSQSClient(client redis.UniversalClient, config *JobQueueConfig) *JobQueueSQSClient {
+	if config == nil {
+		config = NewJobQueueConfig()
+	}
+	return &JobQueueSQSClient{
+		client: client,
+		config: config,
+	}
+}
+
+// queue returns the job queue of a queue URL
+func (c *JobQueueSQSClient) queue(queueURL *string) (*JobQueue, error) {
+	if queueURL == nil || !strings.HasPrefix(*queueURL, jobQueueURLScheme) {
+		return nil, &types.QueueDoesNotExist{Message: aws.String("queue URL is invalid")}
+	}
+	name := (*queueURL)[strings.LastIndex(*queueURL, "/")+1:]
+	return NewJobQueue(c.client, name, c.config), nil
+}
+
+// GetQueueUrl returns the URL of the queue with the given name, every name is valid
+func (c *JobQueueSQSClient) GetQueueUrl(_ context.Context, params *sqs.GetQueueUrlInput, _ ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	if params == nil || params.QueueName == nil || *params.QueueName == "" {
+		return nil, fmt.Errorf("InvalidParameterValue: queue name is required")
+	}
+	return &sqs.GetQueueUrlOutput{
+		QueueUrl: aws.String(jobQueueURLScheme + c.config.Namespace + "/" + *params.QueueName),
+	}, nil
+}
+
+// SendMessage enqueues a single message
+func (c *JobQueueSQSClient) SendMessage(ctx context.Context, params *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	queue, err := c.queue(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := c.send(ctx, queue, aws.ToString(params.MessageBody), params.MessageAttributes,
+		aws.ToString(params.MessageGroupId), aws.ToString(params.MessageDeduplicationId), params.DelaySeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqs.SendMessageOutput{
+		MessageId:        aws.String(messageID),
+		MD5OfMessageBody: aws.String(md5Hex(aws.ToString(params.MessageBody))),
+	}, nil
+}
+
+// SendMessageBatch enqueues up to 10 messages, reporting per-entry failures
+func (c *JobQueueSQSClient) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	queue, err := c.queue(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Entries) == 0 || len(params.Entries) > pkgsqs.MaxBatchEntries {
+		return nil, &types.TooManyEntriesInBatchRequest{Message: aws.String("a batch holds 1 to 10 entries")}
+	}
+
+	output := &sqs.SendMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		messageID, err := c.send(ctx, queue, aws.ToString(entry.MessageBody), entry.MessageAttributes,
+			aws.ToString(entry.MessageGroupId), aws.ToString(entry.MessageDeduplicationId), entry.DelaySeconds)
+		if err != nil {
+			output.Failed = append(output.Failed, types.BatchResultErrorEntry{
+				Id:          entry.Id,
+				Code:        aws.String("InternalError"),
+				Message:     aws.String(err.Error()),
+				SenderFault: false,
+			})
+			continue
+		}
+		output.Successful = append(output.Successful, types.SendMessageBatchResultEntry{
+			Id:               entry.Id,
+			MessageId:        aws.String(messageID),
+			MD5OfMessageBody: aws.String(md5Hex(aws.ToString(entry.MessageBody))),
+		})
+	}
+	return output, nil
+}
+
+// send enqueues a message as a job. The deduplication ID is the job ID, so a duplicate is
+// ignored while the first message is in the queue.
+func (c *JobQueueSQSClient) send(ctx context.Context, queue *JobQueue, body string, attributes map[string]types.MessageAttributeValue,
+	groupID string, deduplicationID string, delaySeconds int32) (string, error) {
+	if delaySeconds < 0 || delaySeconds > pkgsqs.MaxDelaySeconds {
+		return "", fmt.Errorf("InvalidParameterValue: DelaySeconds must be between 0 and %d", pkgsqs.MaxDelaySeconds)
+	}
+
+	opts := NewJobOptions().
+		WithID(deduplicationID).
+		WithDelay(time.Duration(delaySeconds) * time.Second)
+	for name, value := range attributes {
+		opts.WithAttribute(name, aws.ToString(value.StringValue))
+	}
+	if groupID != "" {
+		opts.WithAttribute(jobMessageGroupAttribute, groupID)
+	}
+
+	job, err := queue.Enqueue(ctx, body, opts)
+	if err != nil {
+		return "", err
+	}
+	if job == nil {
+		// Duplicate of a message still in the queue
+		return deduplicationID, nil
+	}
+	return job.ID, nil
+}
+
+// ReceiveMessage leases up to MaxNumberOfMessages messages, polling until WaitTimeSeconds for the first one
+func (c *JobQueueSQSClient) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	queue, err := c.queue(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	maxMessages := int(params.MaxNumberOfMessages)
+	if maxMessages == 0 {
+		maxMessages = 1
+	}
+	if maxMessages < 1 || maxMessages > 10 {
+		return nil, fmt.Errorf("InvalidParameterValue: MaxNumberOfMessages must be between 1 and 10")
+	}
+	visibilityTimeout := time.Duration(params.VisibilityTimeout) * time.Second
+
+	deadline := time.Now().Add(time.Duration(params.WaitTimeSeconds) * time.Second)
+	for {
+		jobs, err := queue.Dequeue(ctx, maxMessages, visibilityTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if len(jobs) > 0 || !time.Now().Before(deadline) {
+			output := &sqs.ReceiveMessageOutput{}
+			for _, job := range jobs {
+				output.Messages = append(output.Messages, jobMessage(job))
+			}
+			return output, nil
+		}
+		if !sleepContext(ctx, min(c.config.PollInterval, time.Until(deadline))) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// jobMessage converts a leased job to a SQS message, the lease is the receipt handle
+func jobMessage(job *Job) types.Message {
+	msg := types.Message{
+		MessageId:     aws.String(job.ID),
+		ReceiptHandle: aws.String(job.Lease),
+		Body:          aws.String(job.Payload),
+		MD5OfBody:     aws.String(md5Hex(job.Payload)),
+		Attributes: map[string]string{
+			string(types.MessageSystemAttributeNameApproximateReceiveCount): strconv.Itoa(job.Attempts),
+			string(types.MessageSystemAttributeNameSentTimestamp):           strconv.FormatInt(job.EnqueuedAt.UnixMilli(), 10),
+		},
+	}
+	for name, value := range job.Attributes {
+		if name == jobMessageGroupAttribute {
+			msg.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)] = value
+			continue
+		}
+		if msg.MessageAttributes == nil {
+			msg.MessageAttributes = make(map[string]types.MessageAttributeValue)
+		}
+		msg.MessageAttributes[name] = types.MessageAttributeValue{
+			DataType:    aws.String(stringDataType),
+			StringValue: aws.String(value),
+		}
+	}
+	return msg
+}
+
+// leasedJob returns the job identified by a receipt handle
+func leasedJob(receiptHandle *string) (*Job, error) {
+	lease := aws.ToString(receiptHandle)
+	_, id, found := strings.Cut(lease, ":")
+	if !found || id == "" {
+		return nil, &types.ReceiptHandleIsInvalid{Message: aws.String("receipt handle is invalid")}
+	}
+	return &Job{ID: id, Lease: lease}, nil
+}
+
+// DeleteMessage completes the message identified by its latest receipt handle
+func (c *JobQueueSQSClient) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	queue, err := c.queue(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	job, err := leasedJob(params.ReceiptHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := queue.Complete(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, &types.ReceiptHandleIsInvalid{Message: aws.String("receipt handle is invalid")}
+	}
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+// ChangeMessageVisibility changes the visibility timeout of an in-flight message.
+// A timeout of zero makes the message visible again immediately.
+func (c *JobQueueSQSClient) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, _ ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	if params.VisibilityTimeout < 0 || params.VisibilityTimeout > 43200 {
+		return nil, fmt.Errorf("InvalidParameterValue: VisibilityTimeout must be between 0 and 43200")
+	}
+	queue, err := c.queue(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	job, err := leasedJob(params.ReceiptHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	extended, err := queue.ExtendLease(ctx, job, time.Duration(params.VisibilityTimeout)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if !extended {
+		return nil, &types.MessageNotInflight{Message: aws.String("message is not in flight")}
+	}
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+// PurgeQueue deletes all messages of the queue
+func (c *JobQueueSQSClient) PurgeQueue(ctx context.Context, params *sqs.PurgeQueueInput, _ ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error) {
+	queue, err := c.queue(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := queue.Purge(ctx); err != nil {
+		return nil, err
+	}
+	return &sqs.PurgeQueueOutput{}, nil
+}
+
+// GetQueueAttributes returns the requested queue attributes, including the approximate message counts
+func (c *JobQueueSQSClient) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, _ ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	queue, err := c.queue(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := queue.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all := map[string]string{
+		string(types.QueueAttributeNameApproximateNumberOfMessages):           strconv.FormatInt(stats.Ready, 10),
+		string(types.QueueAttributeNameApproximateNumberOfMessagesNotVisible): strconv.FormatInt(stats.Leased, 10),
+		string(types.QueueAttributeNameApproximateNumberOfMessagesDelayed):    strconv.FormatInt(stats.Scheduled, 10),
+		string(types.QueueAttributeNameVisibilityTimeout):                     strconv.Itoa(int(c.config.VisibilityTimeout / time.Second)),
+		string(types.QueueAttributeNameMaximumMessageSize):                    strconv.Itoa(pkgsqs.MaxMessageSize),
+	}
+
+	attributes := make(map[string]string)
+	for _, name := range params.AttributeNames {
+		if name == types.QueueAttributeNameAll {
+			return &sqs.GetQueueAttributesOutput{Attributes: all}, nil
+		}
+		if value, exists := all[string(name)]; exists {
+			attributes[string(name)] = value
+		}
+	}
+	return &sqs.GetQueueAttributesOutput{Attributes: attributes}, nil
+}
+
+// md5Hex returns the MD5 hex digest SQS reports for message bodies
+func md5Hex(value string) string {
+	sum := md5.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
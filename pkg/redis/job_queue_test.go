@@ -0,0 +1,252 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newTestJobQueue creates a queue on miniredis whose clock only moves with the returned advance,
+// the scripts read it with TIME
+func newTestJobQueue(t *testing.T, config *JobQueueConfig) (*JobQueue, func(time.Duration)) {
+	t.Helper()
+	client, server := newTestClient(t)
+
+	now := time.Now().Truncate(time.Millisecond)
+	server.SetTime(now)
+	advance := func(d time.Duration) {
+		now = now.Add(d)
+		server.SetTime(now)
+		server.FastForward(d)
+	}
+	return NewJobQueue(client.GetClient(), "test", config.WithNamespace("test-jobs")), advance
+}
+
+// dequeuePayloads dequeues up to count jobs and returns their payloads in delivery order
+func dequeuePayloads(t *testing.T, queue *JobQueue, count int) []string {
+	t.Helper()
+	jobs, err := queue.Dequeue(context.Background(), count, time.Minute)
+	if err != nil {
+		t.Fatalf("Dequeue() error = %v", err)
+	}
+	payloads := make([]string, len(jobs))
+	for i, job := range jobs {
+		payloads[i] = job.Payload
+	}
+	return payloads
+}
+
+func TestJobQueuePriorityOrdering(t *testing.T) {
+	queue, advance := newTestJobQueue(t, NewJobQueueConfig())
+	ctx := context.Background()
+
+	enqueued := []struct {
+		payload  string
+		priority int
+	}{
+		{"low", MinJobPriority},
+		{"default-1", 0},
+		{"high", MaxJobPriority},
+		{"default-2", 0},
+		{"urgent", 50},
+	}
+	for _, e := range enqueued {
+		if _, err := queue.Enqueue(ctx, e.payload, NewJobOptions().WithPriority(e.priority)); err != nil {
+			t.Fatalf("Enqueue(%s) error = %v", e.payload, err)
+		}
+		// Jobs of the same priority are delivered in the order they became ready
+		advance(time.Millisecond)
+	}
+
+	got := dequeuePayloads(t, queue, 10)
+	want := []string{"high", "urgent", "default-1", "default-2", "low"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Dequeue() = %v, want %v", got, want)
+	}
+}
+
+func TestJobQueuePromotesDelayedJobs(t *testing.T) {
+	queue, advance := newTestJobQueue(t, NewJobQueueConfig())
+	ctx := context.Background()
+
+	if _, err := queue.Enqueue(ctx, "delayed", NewJobOptions().WithDelay(time.Minute).WithPriority(MaxJobPriority)); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if _, err := queue.Enqueue(ctx, "ready", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	stats, err := queue.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Ready != 1 || stats.Scheduled != 1 {
+		t.Errorf("Stats() = %+v, want 1 ready and 1 scheduled", stats)
+	}
+	jobs, err := queue.Dequeue(ctx, 10, time.Minute)
+	if err != nil || len(jobs) != 1 || jobs[0].Payload != "ready" {
+		t.Fatalf("Dequeue() before the delay = %v, %v, want the ready job", jobs, err)
+	}
+	if completed, err := queue.Complete(ctx, jobs[0]); err != nil || !completed {
+		t.Fatalf("Complete() = %v, %v, want true", completed, err)
+	}
+
+	advance(30 * time.Second)
+	if got := dequeuePayloads(t, queue, 10); len(got) != 0 {
+		t.Fatalf("Dequeue() during the delay = %v, want none", got)
+	}
+
+	advance(31 * time.Second)
+	if got := dequeuePayloads(t, queue, 10); fmt.Sprint(got) != "[delayed]" {
+		t.Errorf("Dequeue() after the delay = %v, want [delayed]", got)
+	}
+}
+
+func TestJobQueueRedeliversExpiredLeases(t *testing.T) {
+	queue, advance := newTestJobQueue(t, NewJobQueueConfig())
+	ctx := context.Background()
+
+	if _, err := queue.Enqueue(ctx, "payload", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	first, err := queue.Dequeue(ctx, 1, 10*time.Second)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("Dequeue() = %v, %v, want 1 job", first, err)
+	}
+
+	advance(9 * time.Second)
+	if jobs, err := queue.Dequeue(ctx, 1, 10*time.Second); err != nil || len(jobs) != 0 {
+		t.Fatalf("Dequeue() while leased = %v, %v, want none", jobs, err)
+	}
+
+	advance(time.Second)
+	second, err := queue.Dequeue(ctx, 1, 10*time.Second)
+	if err != nil || len(second) != 1 {
+		t.Fatalf("Dequeue() after the lease expired = %v, %v, want 1 job", second, err)
+	}
+	if second[0].ID != first[0].ID || second[0].Attempts != 2 {
+		t.Errorf("redelivered job = %+v, want %s at attempt 2", second[0], first[0].ID)
+	}
+	if second[0].Lease == first[0].Lease {
+		t.Errorf("redelivered lease = %s, want a new lease", second[0].Lease)
+	}
+}
+
+func TestJobQueueRejectsStaleLease(t *testing.T) {
+	queue, advance := newTestJobQueue(t, NewJobQueueConfig())
+	ctx := context.Background()
+
+	if _, err := queue.Enqueue(ctx, "payload", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	stale, err := queue.Dequeue(ctx, 1, time.Second)
+	if err != nil || len(stale) != 1 {
+		t.Fatalf("Dequeue() = %v, %v, want 1 job", stale, err)
+	}
+	advance(time.Second)
+	current, err := queue.Dequeue(ctx, 1, time.Minute)
+	if err != nil || len(current) != 1 {
+		t.Fatalf("Dequeue() after the lease expired = %v, %v, want 1 job", current, err)
+	}
+
+	if extended, err := queue.ExtendLease(ctx, stale[0], time.Minute); err != nil || extended {
+		t.Errorf("ExtendLease() with a stale lease = %v, %v, want false", extended, err)
+	}
+	if retried, deadLettered, err := queue.Retry(ctx, stale[0], errors.New("failed"), 0); err != nil || retried || deadLettered {
+		t.Errorf("Retry() with a stale lease = %v, %v, %v, want false, false", retried, deadLettered, err)
+	}
+	if completed, err := queue.Complete(ctx, stale[0]); err != nil || completed {
+		t.Errorf("Complete() with a stale lease = %v, %v, want false", completed, err)
+	}
+
+	if completed, err := queue.Complete(ctx, current[0]); err != nil || !completed {
+		t.Fatalf("Complete() with the current lease = %v, %v, want true", completed, err)
+	}
+	if job, err := queue.Get(ctx, current[0].ID); err != nil || job != nil {
+		t.Errorf("Get() after Complete() = %v, %v, want nil", job, err)
+	}
+}
+
+func TestJobQueueDeadLetters(t *testing.T) {
+	tests := []struct {
+		name string
+		// fail ends an attempt of the job
+		fail          func(t *testing.T, queue *JobQueue, job *Job, advance func(time.Duration))
+		attempts      int
+		wantLastError string
+	}{
+		{
+			name: "retries until the attempts run out",
+			fail: func(t *testing.T, queue *JobQueue, job *Job, _ func(time.Duration)) {
+				retried, deadLettered, err := queue.Retry(context.Background(), job, errors.New("unavailable"), 0)
+				if err != nil || retried == deadLettered {
+					t.Fatalf("Retry() = %v, %v, %v", retried, deadLettered, err)
+				}
+			},
+			attempts:      3,
+			wantLastError: "unavailable",
+		},
+		{
+			name: "dead-letters a permanent failure at once",
+			fail: func(t *testing.T, queue *JobQueue, job *Job, _ func(time.Duration)) {
+				cause := fmt.Errorf("invalid payload: %w", ErrPermanentJobFailure)
+				if _, deadLettered, err := queue.Retry(context.Background(), job, cause, 0); err != nil || !deadLettered {
+					t.Fatalf("Retry() = %v, %v, want dead-lettered", deadLettered, err)
+				}
+			},
+			attempts:      1,
+			wantLastError: "invalid payload: permanent job failure",
+		},
+		{
+			name: "dead-letters a job whose lease expires on every attempt",
+			fail: func(_ *testing.T, _ *JobQueue, _ *Job, advance func(time.Duration)) {
+				advance(time.Minute)
+			},
+			attempts:      3,
+			wantLastError: "lease expired too many times",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue, advance := newTestJobQueue(t, NewJobQueueConfig().WithMaxAttempts(3))
+			ctx := context.Background()
+
+			enqueued, err := queue.Enqueue(ctx, "payload", nil)
+			if err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			for attempt := 1; attempt <= tt.attempts; attempt++ {
+				jobs, err := queue.Dequeue(ctx, 1, time.Minute)
+				if err != nil || len(jobs) != 1 {
+					t.Fatalf("Dequeue() attempt %d = %v, %v, want 1 job", attempt, jobs, err)
+				}
+				if jobs[0].Attempts != attempt {
+					t.Errorf("Attempts = %d, want %d", jobs[0].Attempts, attempt)
+				}
+				tt.fail(t, queue, jobs[0], advance)
+			}
+
+			if jobs, err := queue.Dequeue(ctx, 1, time.Minute); err != nil || len(jobs) != 0 {
+				t.Fatalf("Dequeue() after the last attempt = %v, %v, want none", jobs, err)
+			}
+			deadLetters, err := queue.DeadLetters(ctx, 10)
+			if err != nil || len(deadLetters) != 1 {
+				t.Fatalf("DeadLetters() = %v, %v, want 1 job", deadLetters, err)
+			}
+			if deadLetters[0].ID != enqueued.ID || deadLetters[0].LastError != tt.wantLastError || deadLetters[0].Lease != "" {
+				t.Errorf("DeadLetters() = %+v, want %s with last error %q", deadLetters[0], enqueued.ID, tt.wantLastError)
+			}
+
+			if redriven, err := queue.RedriveDeadLetters(ctx); err != nil || redriven != 1 {
+				t.Fatalf("RedriveDeadLetters() = %d, %v, want 1", redriven, err)
+			}
+			jobs, err := queue.Dequeue(ctx, 1, time.Minute)
+			if err != nil || len(jobs) != 1 || jobs[0].Attempts != 1 {
+				t.Errorf("Dequeue() after RedriveDeadLetters() = %v, %v, want the job at attempt 1", jobs, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrUnknownCacheName is returned for a cache name without a TTL in the client configuration
+	ErrUnknownCacheName = errors.New("unknown cache name")
+	// ErrUnknownRateLimiter is returned for a cache name without a registered rate limiter
+	ErrUnknownRateLimiter = errors.New("unknown rate limiter")
+	// ErrLockNotHeld is returned when force-releasing a lock that nobody holds
+	ErrLockNotHeld = errors.New("lock is not held")
+	// ErrNotALock is returned when force-releasing a key that is not a lock, reader/writer lock or semaphore
+	ErrNotALock = errors.New("key is not a lock")
+)
+
+// lockKeySuffixes are the suffixes of the keys kept next to a lock, they are never released alone
+var lockKeySuffixes = []string{"fencing", "writer", "readers", "writer-intent"}
+
+// CacheKeyspaceStats represents the keys stored in Redis under a cache name
+type CacheKeyspaceStats struct {
+	Name string `json:"name"`
+	// TTL is the TTL configured for the cache name
+	TTL  string `json:"ttl"`
+	Keys int64  `json:"keys"`
+	// MemoryBytes is estimated from the memory used by a sample of the keys
+	MemoryBytes int64 `json:"memory_bytes"`
+	SampledKeys int   `json:"sampled_keys"`
+}
+
+// KeyInfo represents the type, expiration and memory usage of a key
+type KeyInfo struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+	// TTL is the remaining time to live, empty when the key never expires
+	TTL         string `json:"ttl,omitempty"`
+	MemoryBytes int64  `json:"memory_bytes"`
+}
+
+// LockInfo represents a lock of the lock registry
+type LockInfo struct {
+	Name     string `json:"name"`
+	Key      string `json:"key"`
+	Acquired bool   `json:"acquired"`
+}
+
+// flushBatchSize is the number of keys scanned and deleted per command when flushing a cache
+const flushBatchSize = 500
+
+// KeyspaceAdmin inspects and maintains the keys of the caches, locks and rate limiters of a client
+type KeyspaceAdmin struct {
+	client     *Client
+	localCache *LocalCache
+	sampleSize int
+}
+
+// NewKeyspaceAdmin creates a keyspace admin. localCache may be nil, otherwise flushed
+// caches are also evicted from the local tier of every instance.
+func NewKeyspaceAdmin(client *Client, localCache *LocalCache) *KeyspaceAdmin {
+	return &KeyspaceAdmin{
+		client:     client,
+		localCache: localCache,
+		sampleSize: 100,
+	}
+}
+
+// WithSampleSize sets the number of keys whose memory usage is measured to estimate the memory of a cache
+func (a *KeyspaceAdmin) WithSampleSize(sampleSize int) *KeyspaceAdmin {
+	if sampleSize < 1 {
+		panic(fmt.Sprintf("invalid sample size: %d, must be greater than 0", sampleSize))
+	}
+	a.sampleSize = sampleSize
+	return a
+}
+
+// CacheNames returns the cache names with a TTL in the client configuration
+func (a *KeyspaceAdmin) CacheNames() []string {
+	names := make([]string, 0, len(a.client.GetConfig().CacheTTLs))
+	for name := range a.client.GetConfig().CacheTTLs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CacheStats returns the number of keys of a cache name and an estimate of their memory
+func (a *KeyspaceAdmin) CacheStats(ctx context.Context, cacheName string) (*CacheKeyspaceStats, error) {
+	ttl, ok := a.client.GetConfig().CacheTTLs[cacheName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCacheName, cacheName)
+	}
+
+	keys, err := ScanKeys(ctx, a.client, cacheName+"::*", 1000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan cache %s: %w", cacheName, err)
+	}
+
+	stats := &CacheKeyspaceStats{
+		Name: cacheName,
+		TTL:  ttl.String(),
+		Keys: int64(len(keys)),
+	}
+	sample := keys[:min(len(keys), a.sampleSize)]
+	if len(sample) == 0 {
+		return stats, nil
+	}
+
+	cmds, err := a.client.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range sample {
+			pipe.MemoryUsage(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to measure cache %s: %w", cacheName, err)
+	}
+
+	var sampledBytes int64
+	for _, cmd := range cmds {
+		// Keys expiring during the scan are left out of the sample
+		if usage, err := cmd.(*redis.IntCmd).Result(); err == nil {
+			sampledBytes += usage
+			stats.SampledKeys++
+		}
+	}
+	if stats.SampledKeys > 0 {
+		stats.MemoryBytes = sampledBytes * stats.Keys / int64(stats.SampledKeys)
+	}
+	return stats, nil
+}
+
+// InspectKey returns the type, expiration and memory usage of a key, or nil if it does not exist
+func (a *KeyspaceAdmin) InspectKey(ctx context.Context, key string) (*KeyInfo, error) {
+	rdb := a.client.GetClient()
+	keyType, err := rdb.Type(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect key %s: %w", key, err)
+	}
+	if keyType == "none" {
+		return nil, nil
+	}
+
+	info := &KeyInfo{Key: key, Type: keyType}
+	ttl, err := rdb.PTTL(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect key %s: %w", key, err)
+	}
+	if ttl > 0 {
+		info.TTL = ttl.Round(time.Millisecond).String()
+	}
+
+	// MEMORY USAGE is not available on every Redis compatible server
+	if usage, err := rdb.MemoryUsage(ctx, key).Result(); err == nil {
+		info.MemoryBytes = usage
+	}
+	return info, nil
+}
+
+// FlushCache deletes every key of a cache name, evicting them from the local tier of every
+// instance. Keys are found with SCAN and deleted in batches, so Redis is never blocked by a
+// KEYS or a single huge DEL. It returns the number of keys found.
+func (a *KeyspaceAdmin) FlushCache(ctx context.Context, cacheName string) (int64, error) {
+	if _, ok := a.client.GetConfig().CacheTTLs[cacheName]; !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCacheName, cacheName)
+	}
+
+	pattern := cacheName + "::*"
+	keys, err := ScanKeys(ctx, a.client, pattern, flushBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan cache %s: %w", cacheName, err)
+	}
+	for start := 0; start < len(keys); start += flushBatchSize {
+		batch := keys[start:min(start+flushBatchSize, len(keys))]
+		if err := a.client.Delete(ctx, batch...); err != nil {
+			return 0, fmt.Errorf("failed to flush cache %s: %w", cacheName, err)
+		}
+	}
+
+	if a.localCache != nil {
+		if err := a.localCache.invalidatePattern(ctx, pattern); err != nil {
+			return int64(len(keys)), err
+		}
+	}
+	return int64(len(keys)), nil
+}
+
+// Locks returns the locks of the lock registry, sorted by name
+func (a *KeyspaceAdmin) Locks() []LockInfo {
+	locks := make([]LockInfo, 0)
+	for name, lock := range lockRegistry.GetLocks() {
+		locks = append(locks, LockInfo{
+			Name:     name,
+			Key:      lock.GetFullKey(),
+			Acquired: lock.IsAcquired(),
+		})
+	}
+	sort.Slice(locks, func(i, j int) bool {
+		return locks[i].Name < locks[j].Name
+	})
+	return locks
+}
+
+// ForceReleaseLock deletes a lock by its full key (LockNamespace::lockKey), whoever holds it, so a
+// lock left by a stuck holder can be taken again. Reader/writer locks and semaphores are released
+// too. The fencing token counter is kept, so the next holder still gets a greater token.
+// Only the key of a registered lock or a key under the namespace of a lock is released, it
+// returns ErrNotALock otherwise and ErrLockNotHeld if the lock does not exist.
+func (a *KeyspaceAdmin) ForceReleaseLock(ctx context.Context, fullKey string) error {
+	if !isLockKey(fullKey) {
+		return fmt.Errorf("%w: %s", ErrNotALock, fullKey)
+	}
+
+	keys := []string{
+		fullKey,
+		slotKey(fullKey, "writer"),
+		slotKey(fullKey, "readers"),
+		slotKey(fullKey, "writer-intent"),
+	}
+
+	var deleted int64
+	for _, key := range keys {
+		n, err := a.client.GetClient().Del(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", fullKey, err)
+		}
+		deleted += n
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: %s", ErrLockNotHeld, fullKey)
+	}
+	return nil
+}
+
+// isLockKey reports whether fullKey is the key of a registered lock or a key under the namespace
+// of a registered lock, a cache load lock or a leader election
+func isLockKey(fullKey string) bool {
+	for _, suffix := range lockKeySuffixes {
+		if strings.HasSuffix(fullKey, "::"+suffix) {
+			return false
+		}
+	}
+
+	namespaces := []string{defaultLoadLockNamespace, defaultLeaderNamespace}
+	for _, lock := range lockRegistry.GetLocks() {
+		if lock.GetFullKey() == fullKey {
+			return true
+		}
+		if lock.GetLockNamespace() != "" {
+			namespaces = append(namespaces, lock.GetLockNamespace())
+		}
+	}
+	for _, namespace := range namespaces {
+		if strings.HasPrefix(fullKey, namespace+"::") && len(fullKey) > len(namespace)+2 {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimiters returns the metrics of the registered rate limiters by cache name
+func (a *KeyspaceAdmin) RateLimiters(ctx context.Context) map[string]map[string]string {
+	return GetRateLimiterMetrics(ctx)
+}
+
+// ResetRateLimiter deletes the counters of a registered rate limiter, returning the number of deleted keys
+func (a *KeyspaceAdmin) ResetRateLimiter(ctx context.Context, cacheName string) (int64, error) {
+	limiter, ok := rateLimiterRegistry.GetRateLimiter(cacheName)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownRateLimiter, cacheName)
+	}
+	return limiter.Reset(ctx)
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeyspaceAdminForceReleaseLock(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	admin := NewKeyspaceAdmin(client, nil)
+
+	semaphore := NewSemaphore(client, "release", 2, testLockOptions(time.Minute).
+		WithLockNamespace("test-semaphores").
+		WithCacheName("test-release-semaphore"))
+	t.Cleanup(func() { lockRegistry.UnregisterLock("test-release-semaphore") })
+	if _, err := semaphore.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if err := client.Set(ctx, "weather::recife", "sunny", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := server.Set(fencingTokenKey("test-semaphores::release"), "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{name: "cache key", key: "weather::recife", wantErr: ErrNotALock},
+		{name: "fencing token counter", key: fencingTokenKey("test-semaphores::release"), wantErr: ErrNotALock},
+		{name: "bare namespace", key: "test-semaphores::", wantErr: ErrNotALock},
+		{name: "lock not held", key: "test-semaphores::other", wantErr: ErrLockNotHeld},
+		{name: "leader election not held", key: defaultLeaderNamespace + "::scheduler", wantErr: ErrLockNotHeld},
+		{name: "semaphore", key: "test-semaphores::release"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := admin.ForceReleaseLock(ctx, tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ForceReleaseLock(%s) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Refused keys are left untouched
+	for _, key := range []string{"weather::recife", fencingTokenKey("test-semaphores::release")} {
+		if !server.Exists(key) {
+			t.Errorf("%s was deleted", key)
+		}
+	}
+	if holders, err := semaphore.Holders(ctx); err != nil || holders != 0 {
+		t.Errorf("Holders() after ForceReleaseLock() = %d, %v, want 0", holders, err)
+	}
+}
@@ -0,0 +1,360 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultLeaderNamespace is the lock namespace of elections when none is configured
+const defaultLeaderNamespace = "leader-election"
+
+// LeaderElectorOptions represents options for leader election
+type LeaderElectorOptions struct {
+	// Identity identifies this instance as leader, defaults to the hostname with a random suffix
+	Identity string
+	// Lock configures the leadership lock: TTL, RefreshInterval, RetryDelay between campaign
+	// attempts and LockNamespace. InfiniteRetry, PersistentRefresh, Owner and CacheName are set by the elector.
+	Lock *LockOptions
+	// RecampaignDelay is the delay before campaigning again after losing leadership
+	RecampaignDelay time.Duration
+}
+
+// NewLeaderElectorOptions creates new leader elector options with default values
+func NewLeaderElectorOptions() *LeaderElectorOptions {
+	return &LeaderElectorOptions{
+		Identity: defaultLeaderIdentity(),
+		Lock: NewLockOptions().
+			WithTTL(15 * time.Second).
+			WithRefreshInterval(5 * time.Second).
+			WithRetryDelay(1 * time.Second).
+			WithLockNamespace(defaultLeaderNamespace),
+		RecampaignDelay: 1 * time.Second,
+	}
+}
+
+// WithIdentity sets the identity published while this instance is leader
+func (o *LeaderElectorOptions) WithIdentity(identity string) *LeaderElectorOptions {
+	if identity == "" {
+		panic("leader identity must not be empty")
+	}
+	o.Identity = identity
+	return o
+}
+
+// WithLockOptions sets the options of the leadership lock
+func (o *LeaderElectorOptions) WithLockOptions(opts *LockOptions) *LeaderElectorOptions {
+	if opts == nil {
+		panic("leader lock options must not be nil")
+	}
+	o.Lock = opts
+	return o
+}
+
+// WithRecampaignDelay sets the delay before campaigning again after losing leadership
+func (o *LeaderElectorOptions) WithRecampaignDelay(delay time.Duration) *LeaderElectorOptions {
+	if delay < 0 {
+		panic(fmt.Sprintf("invalid recampaign delay: %v, must be non-negative", delay))
+	}
+	o.RecampaignDelay = delay
+	return o
+}
+
+// DefaultLeaderElectorOptions returns default leader elector options
+func DefaultLeaderElectorOptions() *LeaderElectorOptions {
+	return NewLeaderElectorOptions()
+}
+
+// defaultLeaderIdentity returns the hostname with a random suffix, so several processes on the same host differ
+func defaultLeaderIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "instance"
+	}
+	return hostname + "-" + uuid.NewString()[:8]
+}
+
+// LeaderElector elects a single leader among the instances campaigning for the same name.
+// Leadership is a Lock refreshed in the background, when a refresh fails the leader is
+// revoked and campaigns again, so another instance takes over within one lock TTL.
+type LeaderElector struct {
+	client    *Client
+	name      string
+	opts      *LeaderElectorOptions
+	onElected []func(ctx context.Context)
+	onRevoked []func()
+
+	mu        sync.RWMutex
+	lock      *Lock
+	cancel    context.CancelFunc
+	done      chan struct{}
+	lastError string
+	leading   int32
+	elections uint64
+	revokes   uint64
+}
+
+// NewLeaderElector creates a leader elector for the given election name
+func NewLeaderElector(client *Client, name string, opts *LeaderElectorOptions) *LeaderElector {
+	if name == "" {
+		panic("election name must not be empty")
+	}
+	if opts == nil {
+		opts = DefaultLeaderElectorOptions()
+	}
+	if opts.Lock.TTL > 0 && opts.Lock.RefreshInterval >= opts.Lock.TTL {
+		panic(fmt.Sprintf("invalid leader refresh interval: %v, must be lower than the TTL %v", opts.Lock.RefreshInterval, opts.Lock.TTL))
+	}
+
+	return &LeaderElector{
+		client: client,
+		name:   name,
+		opts:   opts,
+	}
+}
+
+// OnElected registers a callback called when this instance becomes leader.
+// Its context is cancelled when leadership is revoked. Callbacks must not block.
+func (e *LeaderElector) OnElected(fn func(ctx context.Context)) *LeaderElector {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onElected = append(e.onElected, fn)
+	return e
+}
+
+// OnRevoked registers a callback called when this instance stops being leader,
+// either because a refresh failed or because it resigned. Callbacks must not block.
+func (e *LeaderElector) OnRevoked(fn func()) *LeaderElector {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onRevoked = append(e.onRevoked, fn)
+	return e
+}
+
+// Campaign starts campaigning for leadership in the background until the context is
+// cancelled or Resign is called. After losing leadership the elector campaigns again.
+func (e *LeaderElector) Campaign(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cancel != nil {
+		return fmt.Errorf("already campaigning for %s", e.name)
+	}
+
+	campaignCtx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	go e.run(campaignCtx, e.done)
+	return nil
+}
+
+// Resign stops campaigning and releases leadership if held, calling the OnRevoked callbacks
+func (e *LeaderElector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel, e.done = nil, nil
+	e.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsLeader reports whether this instance currently holds a valid leadership
+func (e *LeaderElector) IsLeader() bool {
+	if atomic.LoadInt32(&e.leading) == 0 {
+		return false
+	}
+
+	e.mu.RLock()
+	lock := e.lock
+	e.mu.RUnlock()
+	return lock != nil && lock.Validity() > 0
+}
+
+// Identity returns the identity of this instance
+func (e *LeaderElector) Identity() string {
+	return e.opts.Identity
+}
+
+// Name returns the election name
+func (e *LeaderElector) Name() string {
+	return e.name
+}
+
+// Token returns the fencing token of the current leadership term, 0 when not leader.
+// Writes made as leader can be validated with ValidateFencingToken.
+func (e *LeaderElector) Token() int64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.lock == nil || atomic.LoadInt32(&e.leading) == 0 {
+		return 0
+	}
+	return e.lock.Token()
+}
+
+// Leader returns the identity of the current leader among all instances, an empty string when there is none
+func (e *LeaderElector) Leader(ctx context.Context) (string, error) {
+	// The lock only reads the holder, registering it would replace the leadership lock in the registry
+	opts := e.lockOptions()
+	opts.CacheName = ""
+	return NewLock(e.client, e.name, opts).Holder(ctx)
+}
+
+// run campaigns until the context is cancelled
+func (e *LeaderElector) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	defer func() {
+		// The campaign context may be cancelled by the caller instead of Resign
+		e.mu.Lock()
+		if e.done == done {
+			e.cancel, e.done = nil, nil
+		}
+		e.mu.Unlock()
+	}()
+
+	for {
+		lock := NewLock(e.client, e.name, e.lockOptions())
+		if _, err := lock.Lock(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			e.setLastError(err)
+			if !sleepContext(ctx, e.opts.Lock.RetryDelay) {
+				return
+			}
+			continue
+		}
+
+		e.lead(ctx, lock)
+
+		if !sleepContext(ctx, e.opts.RecampaignDelay) {
+			return
+		}
+	}
+}
+
+// lead holds leadership until a refresh fails or the context is cancelled
+func (e *LeaderElector) lead(ctx context.Context, lock *Lock) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	e.mu.Lock()
+	e.lock = lock
+	e.lastError = ""
+	onElected := append([]func(ctx context.Context){}, e.onElected...)
+	e.mu.Unlock()
+
+	atomic.StoreInt32(&e.leading, 1)
+	atomic.AddUint64(&e.elections, 1)
+	refreshErr := lock.AutoRefresh(leaderCtx)
+	for _, fn := range onElected {
+		fn(leaderCtx)
+	}
+
+	if err := <-refreshErr; err != nil && ctx.Err() == nil {
+		e.setLastError(err)
+	}
+
+	cancel()
+	atomic.StoreInt32(&e.leading, 0)
+	atomic.AddUint64(&e.revokes, 1)
+
+	e.mu.RLock()
+	onRevoked := append([]func(){}, e.onRevoked...)
+	e.mu.RUnlock()
+	for _, fn := range onRevoked {
+		fn()
+	}
+
+	// Release the lock so the next leader does not wait for it to expire. It fails harmlessly
+	// when the lock already expired or was taken over.
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), e.opts.Lock.TTL)
+	defer releaseCancel()
+	lock.Unlock(releaseCtx)
+}
+
+// lockOptions returns the options of the leadership lock
+func (e *LeaderElector) lockOptions() *LockOptions {
+	opts := *e.opts.Lock
+	if opts.LockNamespace == "" {
+		opts.LockNamespace = defaultLeaderNamespace
+	}
+	return opts.
+		WithInfiniteRetry(true).
+		WithPersistentRefresh(false).
+		WithOwner(e.opts.Identity).
+		WithCacheName(e.name)
+}
+
+// setLastError records the last campaign or refresh error for health checks
+func (e *LeaderElector) setLastError(err error) {
+	e.mu.Lock()
+	e.lastError = err.Error()
+	e.mu.Unlock()
+}
+
+// sleepContext waits for the delay, returning false if the context is cancelled first
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// LeaderElectorHealthCheck represents the health check response for a leader elector
+type LeaderElectorHealthCheck struct {
+	Status  HealthStatus      `json:"status"`
+	Details map[string]string `json:"details"`
+}
+
+// HealthCheck returns the election state of this instance. A follower is healthy,
+// the elector is DOWN only when it is not campaigning.
+func (e *LeaderElector) HealthCheck() LeaderElectorHealthCheck {
+	e.mu.RLock()
+	campaigning := e.cancel != nil
+	lastError := e.lastError
+	e.mu.RUnlock()
+
+	status := StatusUp
+	if !campaigning {
+		status = StatusDown
+	}
+
+	details := map[string]string{
+		"name":        e.name,
+		"identity":    e.opts.Identity,
+		"campaigning": strconv.FormatBool(campaigning),
+		"leader":      strconv.FormatBool(e.IsLeader()),
+		"elections":   strconv.FormatUint(atomic.LoadUint64(&e.elections), 10),
+		"revocations": strconv.FormatUint(atomic.LoadUint64(&e.revokes), 10),
+	}
+	if token := e.Token(); token > 0 {
+		details["fencing_token"] = strconv.FormatInt(token, 10)
+	}
+	if lastError != "" {
+		details["last_error"] = lastError
+	}
+
+	return LeaderElectorHealthCheck{
+		Status:  status,
+		Details: details,
+	}
+}
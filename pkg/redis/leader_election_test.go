@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls the condition until it is true or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("condition not met within %s", timeout)
+}
+
+// newTestLeaderElector creates an elector refreshing its leadership every 50ms
+func newTestLeaderElector(t *testing.T, client *Client, name, identity string) *LeaderElector {
+	t.Helper()
+	elector := NewLeaderElector(client, name, NewLeaderElectorOptions().
+		WithIdentity(identity).
+		WithRecampaignDelay(10*time.Millisecond).
+		WithLockOptions(NewLockOptions().
+			WithTTL(time.Second).
+			WithRefreshInterval(50*time.Millisecond).
+			WithRetryDelay(10*time.Millisecond).
+			WithLockNamespace("test-leaders")))
+	t.Cleanup(func() {
+		_ = elector.Resign(context.Background())
+	})
+	return elector
+}
+
+func TestLeaderElectorCampaign(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	first := newTestLeaderElector(t, client, "campaign", "first")
+	second := newTestLeaderElector(t, client, "campaign", "second")
+
+	if err := first.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+	waitFor(t, time.Second, first.IsLeader)
+	if err := second.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+	if err := first.Campaign(ctx); err == nil {
+		t.Error("Campaign() twice error = nil, want already campaigning")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if second.IsLeader() || second.Token() != 0 {
+		t.Fatalf("second IsLeader() = %v with token %d while first leads", second.IsLeader(), second.Token())
+	}
+	if leader, err := second.Leader(ctx); err != nil || leader != "first" {
+		t.Errorf("Leader() = %q, %v, want first", leader, err)
+	}
+	if health := second.HealthCheck(); health.Status != StatusUp || health.Details["leader"] != "false" {
+		t.Errorf("HealthCheck() of the follower = %+v, want UP and not leader", health)
+	}
+
+	// Resigning hands the leadership over without waiting for the lock to expire
+	firstToken := first.Token()
+	if err := first.Resign(ctx); err != nil {
+		t.Fatalf("Resign() error = %v", err)
+	}
+	waitFor(t, time.Second, second.IsLeader)
+	if second.Token() <= firstToken {
+		t.Errorf("Token() of the new leader = %d, want greater than %d", second.Token(), firstToken)
+	}
+	if leader, err := first.Leader(ctx); err != nil || leader != "second" {
+		t.Errorf("Leader() after Resign() = %q, %v, want second", leader, err)
+	}
+	if health := first.HealthCheck(); health.Status != StatusDown {
+		t.Errorf("HealthCheck() after Resign() = %+v, want DOWN", health)
+	}
+}
+
+func TestLeaderElectorRecampaignsAfterLosingLeadership(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	elector := newTestLeaderElector(t, client, "recampaign", "leader")
+
+	var elected, revoked int32
+	elector.OnElected(func(context.Context) { atomic.AddInt32(&elected, 1) })
+	elector.OnRevoked(func() { atomic.AddInt32(&revoked, 1) })
+
+	if err := elector.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+	waitFor(t, time.Second, elector.IsLeader)
+	firstToken := elector.Token()
+
+	// Another instance or an operator removed the lock, the next refresh fails
+	server.Del("test-leaders::recampaign")
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt32(&revoked) == 1 })
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt32(&elected) == 2 && elector.IsLeader() })
+
+	if token := elector.Token(); token <= firstToken {
+		t.Errorf("Token() of the second term = %d, want greater than %d", token, firstToken)
+	}
+	health := elector.HealthCheck()
+	if health.Details["elections"] != "2" || health.Details["revocations"] != "1" {
+		t.Errorf("HealthCheck() details = %v, want 2 elections and 1 revocation", health.Details)
+	}
+}
+
+func TestLeaderElectorLeaderKeepsRegistry(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	elector := newTestLeaderElector(t, client, "registry", "leader")
+
+	if err := elector.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+	waitFor(t, time.Second, elector.IsLeader)
+	registered := lockRegistry.GetLocks()["registry"]
+	if registered == nil || !registered.IsAcquired() {
+		t.Fatalf("registered lock = %v, want the acquired leadership lock", registered)
+	}
+
+	for i := 0; i < 3; i++ {
+		if leader, err := elector.Leader(ctx); err != nil || leader != "leader" {
+			t.Fatalf("Leader() = %q, %v, want leader", leader, err)
+		}
+	}
+	if got := lockRegistry.GetLocks()["registry"]; got != registered || !got.IsAcquired() {
+		t.Errorf("registered lock after Leader() = %v, want the acquired leadership lock", got)
+	}
+}
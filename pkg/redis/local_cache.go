@@ -0,0 +1,658 @@
+package redis
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EvictionPolicy selects which entry the local cache evicts when it is full
+type EvictionPolicy string
+
+const (
+	// EvictionLRU evicts the least recently used entry
+	EvictionLRU EvictionPolicy = "lru"
+	// EvictionLFU evicts the least frequently used entry, the least recently used among ties
+	EvictionLFU EvictionPolicy = "lfu"
+)
+
+// defaultInvalidationChannel is the channel local caches use to broadcast invalidations
+const defaultInvalidationChannel = "cache-invalidation"
+
+// ParseEvictionPolicy converts a string eviction policy to EvictionPolicy
+func ParseEvictionPolicy(policy string) EvictionPolicy {
+	switch strings.ToLower(policy) {
+	case "lfu":
+		return EvictionLFU
+	default:
+		return EvictionLRU
+	}
+}
+
+// LocalCacheOptions represents options for the in-process cache tier
+type LocalCacheOptions struct {
+	// MaxEntries is the maximum number of entries kept in memory
+	MaxEntries int
+	// DefaultTTL is the time to live of entries whose cache name has no specific TTL
+	DefaultTTL time.Duration
+	// CacheTTLs maps cache names to the time to live of their entries
+	CacheTTLs map[string]time.Duration
+	// EvictionPolicy selects the entry evicted when the cache is full
+	EvictionPolicy EvictionPolicy
+	// InvalidationChannel is the pub/sub channel used to broadcast invalidations
+	InvalidationChannel string
+	// PubSubConfig configures the invalidation publisher and subscriber
+	PubSubConfig *PubSubConfig
+}
+
+// NewLocalCacheOptions creates a new local cache options with default values
+func NewLocalCacheOptions() *LocalCacheOptions {
+	return &LocalCacheOptions{
+		MaxEntries:          10000,
+		DefaultTTL:          1 * time.Minute,
+		CacheTTLs:           make(map[string]time.Duration),
+		EvictionPolicy:      EvictionLRU,
+		InvalidationChannel: defaultInvalidationChannel,
+	}
+}
+
+// WithMaxEntries sets the maximum number of entries kept in memory
+func (lco *LocalCacheOptions) WithMaxEntries(maxEntries int) *LocalCacheOptions {
+	if maxEntries < 1 {
+		panic(fmt.Sprintf("invalid max entries: %d, must be greater than 0", maxEntries))
+	}
+	lco.MaxEntries = maxEntries
+	return lco
+}
+
+// WithDefaultTTL sets the time to live of entries whose cache name has no specific TTL
+func (lco *LocalCacheOptions) WithDefaultTTL(ttl time.Duration) *LocalCacheOptions {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("invalid default TTL: %v, must be positive", ttl))
+	}
+	lco.DefaultTTL = ttl
+	return lco
+}
+
+// WithCacheTTL sets the time to live of the entries of a cache name
+func (lco *LocalCacheOptions) WithCacheTTL(cacheName string, ttl time.Duration) *LocalCacheOptions {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("invalid TTL for cache %s: %v, must be positive", cacheName, ttl))
+	}
+	if lco.CacheTTLs == nil {
+		lco.CacheTTLs = make(map[string]time.Duration)
+	}
+	lco.CacheTTLs[cacheName] = ttl
+	return lco
+}
+
+// WithEvictionPolicy sets the entry evicted when the cache is full
+func (lco *LocalCacheOptions) WithEvictionPolicy(policy EvictionPolicy) *LocalCacheOptions {
+	if policy != EvictionLRU && policy != EvictionLFU {
+		panic(fmt.Sprintf("invalid eviction policy: %s, must be lru or lfu", policy))
+	}
+	lco.EvictionPolicy = policy
+	return lco
+}
+
+// WithInvalidationChannel sets the pub/sub channel used to broadcast invalidations
+func (lco *LocalCacheOptions) WithInvalidationChannel(channel string) *LocalCacheOptions {
+	if channel == "" {
+		panic("invalid invalidation channel: must not be empty")
+	}
+	lco.InvalidationChannel = channel
+	return lco
+}
+
+// WithPubSubConfig sets the configuration of the invalidation publisher and subscriber
+func (lco *LocalCacheOptions) WithPubSubConfig(config *PubSubConfig) *LocalCacheOptions {
+	lco.PubSubConfig = config
+	return lco
+}
+
+// LocalCacheStats is a point-in-time snapshot of the local cache counters
+type LocalCacheStats struct {
+	Hits                  uint64
+	Misses                uint64
+	Evictions             uint64
+	Expirations           uint64
+	Entries               int
+	InvalidationsSent     uint64
+	InvalidationsReceived uint64
+}
+
+// LocalCacheHealthCheck represents the health check response for the local cache
+type LocalCacheHealthCheck struct {
+	Status  HealthStatus      `json:"status"`
+	Details map[string]string `json:"details"`
+}
+
+// invalidationMessage is broadcast to the other instances when entries change.
+// Keys are full cache keys, Patterns are Redis glob patterns over full cache keys.
+type invalidationMessage struct {
+	Origin   string   `json:"origin"`
+	Keys     []string `json:"keys,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// LocalCache is an in-process cache tier placed in front of one or more Cache instances
+// with CacheOptions.WithLocalCache. Entries are keyed by their full Redis key, bounded
+// by MaxEntries and expire after the TTL of their cache name.
+//
+// Writes and deletes made through a Cache evict the entry in every instance: they are
+// broadcast on InvalidationChannel and applied by the subscriber started with Start.
+// A value read from Redis just before a concurrent invalidation can still be served
+// until its local TTL, so the local TTL bounds how stale a value can get.
+type LocalCache struct {
+	client     *Client
+	opts       *LocalCacheOptions
+	instanceID string
+	publisher  *Publisher
+	subscriber *Subscriber
+
+	mu    sync.Mutex
+	store localStore
+
+	hits                  uint64
+	misses                uint64
+	evictions             uint64
+	expirations           uint64
+	invalidationsSent     uint64
+	invalidationsReceived uint64
+	isRunning             int32
+}
+
+// NewLocalCache creates a new local cache tier.
+// Call Start to receive the invalidations broadcast by the other instances.
+func NewLocalCache(client *Client, opts *LocalCacheOptions) *LocalCache {
+	if opts == nil {
+		opts = NewLocalCacheOptions()
+	}
+	if opts.MaxEntries < 1 {
+		opts.MaxEntries = 10000
+	}
+	if opts.DefaultTTL <= 0 {
+		opts.DefaultTTL = 1 * time.Minute
+	}
+	if opts.InvalidationChannel == "" {
+		opts.InvalidationChannel = defaultInvalidationChannel
+	}
+
+	lc := &LocalCache{
+		client:     client,
+		opts:       opts,
+		instanceID: uuid.NewString(),
+	}
+	if opts.EvictionPolicy == EvictionLFU {
+		lc.store = newLFUStore()
+	} else {
+		lc.store = newLRUStore()
+	}
+	if client != nil {
+		lc.publisher = NewPublisher(client.GetClient(), opts.PubSubConfig)
+	}
+	return lc
+}
+
+// Start subscribes to the invalidation channel and applies the invalidations sent by
+// the other instances until ctx is canceled or Close is called
+func (lc *LocalCache) Start(ctx context.Context) error {
+	if lc.client == nil {
+		return fmt.Errorf("local cache has no redis client")
+	}
+
+	subscriber, err := NewSubscriber(lc.client.GetClient(), HandlerFunc(lc.handleInvalidation), lc.opts.PubSubConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create invalidation subscriber: %w", err)
+	}
+	if err := subscriber.Subscribe(ctx, lc.opts.InvalidationChannel); err != nil {
+		return fmt.Errorf("failed to subscribe to invalidation channel: %w", err)
+	}
+
+	lc.mu.Lock()
+	lc.subscriber = subscriber
+	lc.mu.Unlock()
+
+	atomic.StoreInt32(&lc.isRunning, 1)
+	go func() {
+		defer atomic.StoreInt32(&lc.isRunning, 0)
+		subscriber.Start(ctx)
+	}()
+	return nil
+}
+
+// Close stops receiving invalidations and drops every entry
+func (lc *LocalCache) Close() error {
+	lc.mu.Lock()
+	subscriber := lc.subscriber
+	lc.subscriber = nil
+	lc.store.clear()
+	lc.mu.Unlock()
+
+	if subscriber != nil {
+		return subscriber.Close()
+	}
+	return nil
+}
+
+// get returns the data stored for a full key
+func (lc *LocalCache) get(fullKey string) ([]byte, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	entry, ok := lc.store.get(fullKey)
+	if ok && time.Now().After(entry.expiresAt) {
+		lc.store.remove(fullKey)
+		atomic.AddUint64(&lc.expirations, 1)
+		ok = false
+	}
+	if !ok {
+		atomic.AddUint64(&lc.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddUint64(&lc.hits, 1)
+	return entry.data, true
+}
+
+// set stores the data of a full key. A positive remoteTTL caps the local TTL,
+// so a value never outlives its Redis copy.
+func (lc *LocalCache) set(cacheName string, fullKey string, data []byte, remoteTTL time.Duration) {
+	ttl := lc.ttlFor(cacheName)
+	if remoteTTL > 0 && remoteTTL < ttl {
+		ttl = remoteTTL
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	// Room is made before storing a new key, otherwise LFU would evict the new entry,
+	// whose access count is the lowest
+	if !lc.store.contains(fullKey) {
+		for lc.store.len() >= lc.opts.MaxEntries {
+			lc.store.evict()
+			atomic.AddUint64(&lc.evictions, 1)
+		}
+	}
+	lc.store.put(&localEntry{key: fullKey, data: data, expiresAt: time.Now().Add(ttl)})
+}
+
+// ttlFor returns the local TTL of a cache name
+func (lc *LocalCache) ttlFor(cacheName string) time.Duration {
+	if ttl, ok := lc.opts.CacheTTLs[cacheName]; ok {
+		return ttl
+	}
+	return lc.opts.DefaultTTL
+}
+
+// invalidate evicts full keys locally and broadcasts the eviction to the other instances
+func (lc *LocalCache) invalidate(ctx context.Context, fullKeys ...string) error {
+	lc.evictKeys(fullKeys)
+	return lc.broadcast(ctx, invalidationMessage{Keys: fullKeys})
+}
+
+// invalidatePattern evicts the full keys matching a Redis glob pattern locally and
+// broadcasts the eviction to the other instances
+func (lc *LocalCache) invalidatePattern(ctx context.Context, pattern string) error {
+	lc.evictPatterns([]string{pattern})
+	return lc.broadcast(ctx, invalidationMessage{Patterns: []string{pattern}})
+}
+
+// broadcast publishes an invalidation to the other instances
+func (lc *LocalCache) broadcast(ctx context.Context, message invalidationMessage) error {
+	if lc.publisher == nil {
+		return nil
+	}
+
+	message.Origin = lc.instanceID
+	if err := lc.publisher.PublishJSON(ctx, lc.opts.InvalidationChannel, message); err != nil {
+		return fmt.Errorf("failed to broadcast cache invalidation: %w", err)
+	}
+	atomic.AddUint64(&lc.invalidationsSent, 1)
+	return nil
+}
+
+// handleInvalidation applies an invalidation received from another instance
+func (lc *LocalCache) handleInvalidation(ctx context.Context, channel string, payload string) error {
+	var message invalidationMessage
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		return fmt.Errorf("invalid cache invalidation message: %w", err)
+	}
+
+	// Our own invalidations were applied before they were published
+	if message.Origin == lc.instanceID {
+		return nil
+	}
+
+	atomic.AddUint64(&lc.invalidationsReceived, 1)
+	lc.evictKeys(message.Keys)
+	lc.evictPatterns(message.Patterns)
+	return nil
+}
+
+// evictKeys removes full keys from the store
+func (lc *LocalCache) evictKeys(fullKeys []string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	for _, fullKey := range fullKeys {
+		lc.store.remove(fullKey)
+	}
+}
+
+// evictPatterns removes the full keys matching Redis glob patterns from the store
+func (lc *LocalCache) evictPatterns(patterns []string) {
+	if len(patterns) == 0 {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	for _, fullKey := range lc.store.keys() {
+		for _, pattern := range patterns {
+			if matchGlob(pattern, fullKey) {
+				lc.store.remove(fullKey)
+				break
+			}
+		}
+	}
+}
+
+// Stats returns a snapshot of the local cache counters
+func (lc *LocalCache) Stats() LocalCacheStats {
+	lc.mu.Lock()
+	entries := lc.store.len()
+	lc.mu.Unlock()
+
+	return LocalCacheStats{
+		Hits:                  atomic.LoadUint64(&lc.hits),
+		Misses:                atomic.LoadUint64(&lc.misses),
+		Evictions:             atomic.LoadUint64(&lc.evictions),
+		Expirations:           atomic.LoadUint64(&lc.expirations),
+		Entries:               entries,
+		InvalidationsSent:     atomic.LoadUint64(&lc.invalidationsSent),
+		InvalidationsReceived: atomic.LoadUint64(&lc.invalidationsReceived),
+	}
+}
+
+// HealthCheck returns the health status and details of the local cache.
+// The local cache is DOWN when it is not receiving invalidations, since entries could
+// then be served stale until their local TTL.
+func (lc *LocalCache) HealthCheck() LocalCacheHealthCheck {
+	stats := lc.Stats()
+	isRunning := atomic.LoadInt32(&lc.isRunning) == 1
+
+	status := StatusUp
+	if !isRunning {
+		status = StatusDown
+	}
+
+	details := map[string]string{
+		"is_running":             strconv.FormatBool(isRunning),
+		"eviction_policy":        string(lc.opts.EvictionPolicy),
+		"max_entries":            strconv.Itoa(lc.opts.MaxEntries),
+		"default_ttl":            lc.opts.DefaultTTL.String(),
+		"invalidation_channel":   lc.opts.InvalidationChannel,
+		"entries":                strconv.Itoa(stats.Entries),
+		"hits":                   strconv.FormatUint(stats.Hits, 10),
+		"misses":                 strconv.FormatUint(stats.Misses, 10),
+		"evictions":              strconv.FormatUint(stats.Evictions, 10),
+		"expirations":            strconv.FormatUint(stats.Expirations, 10),
+		"invalidations_sent":     strconv.FormatUint(stats.InvalidationsSent, 10),
+		"invalidations_received": strconv.FormatUint(stats.InvalidationsReceived, 10),
+	}
+
+	return LocalCacheHealthCheck{
+		Status:  status,
+		Details: details,
+	}
+}
+
+// matchGlob reports whether key matches a Redis glob pattern supporting *, ? and \ escapes.
+// Unlike path.Match, * also matches separators, as it does in Redis KEYS.
+func matchGlob(pattern string, key string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(key); i++ {
+				if matchGlob(pattern, key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(key) == 0 {
+				return false
+			}
+		default:
+			if pattern[0] == '\\' && len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			if len(key) == 0 || key[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		key = key[1:]
+	}
+	return len(key) == 0
+}
+
+// localEntry is a value held by the local cache
+type localEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+
+	// frequency and sequence order entries in the LFU heap, index is their heap position
+	frequency uint64
+	sequence  uint64
+	index     int
+}
+
+// localStore is the eviction structure behind LocalCache, guarded by LocalCache.mu
+type localStore interface {
+	get(key string) (*localEntry, bool)
+	contains(key string) bool
+	put(entry *localEntry)
+	remove(key string)
+	evict()
+	keys() []string
+	len() int
+	clear()
+}
+
+// lruStore evicts the least recently used entry
+type lruStore struct {
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// newLRUStore creates an empty LRU store
+func newLRUStore() *lruStore {
+	return &lruStore{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (s *lruStore) get(key string) (*localEntry, bool) {
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(element)
+	return element.Value.(*localEntry), true
+}
+
+func (s *lruStore) contains(key string) bool {
+	_, ok := s.entries[key]
+	return ok
+}
+
+func (s *lruStore) put(entry *localEntry) {
+	if element, ok := s.entries[entry.key]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+		return
+	}
+	s.entries[entry.key] = s.order.PushFront(entry)
+}
+
+func (s *lruStore) remove(key string) {
+	if element, ok := s.entries[key]; ok {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+}
+
+func (s *lruStore) evict() {
+	if element := s.order.Back(); element != nil {
+		s.remove(element.Value.(*localEntry).key)
+	}
+}
+
+func (s *lruStore) keys() []string {
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (s *lruStore) len() int {
+	return len(s.entries)
+}
+
+func (s *lruStore) clear() {
+	s.entries = make(map[string]*list.Element)
+	s.order.Init()
+}
+
+// lfuStore evicts the least frequently used entry, using a min-heap on access count
+type lfuStore struct {
+	entries  map[string]*localEntry
+	heap     lfuHeap
+	sequence uint64
+}
+
+// newLFUStore creates an empty LFU store
+func newLFUStore() *lfuStore {
+	return &lfuStore{entries: make(map[string]*localEntry)}
+}
+
+func (s *lfuStore) get(key string) (*localEntry, bool) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	s.touch(entry)
+	return entry, true
+}
+
+func (s *lfuStore) contains(key string) bool {
+	_, ok := s.entries[key]
+	return ok
+}
+
+func (s *lfuStore) put(entry *localEntry) {
+	if existing, ok := s.entries[entry.key]; ok {
+		existing.data = entry.data
+		existing.expiresAt = entry.expiresAt
+		s.touch(existing)
+		return
+	}
+	s.sequence++
+	entry.frequency = 1
+	entry.sequence = s.sequence
+	s.entries[entry.key] = entry
+	heap.Push(&s.heap, entry)
+}
+
+// touch records an access to an entry
+func (s *lfuStore) touch(entry *localEntry) {
+	s.sequence++
+	entry.frequency++
+	entry.sequence = s.sequence
+	heap.Fix(&s.heap, entry.index)
+}
+
+func (s *lfuStore) remove(key string) {
+	if entry, ok := s.entries[key]; ok {
+		heap.Remove(&s.heap, entry.index)
+		delete(s.entries, key)
+	}
+}
+
+func (s *lfuStore) evict() {
+	if len(s.heap) > 0 {
+		entry := heap.Pop(&s.heap).(*localEntry)
+		delete(s.entries, entry.key)
+	}
+}
+
+func (s *lfuStore) keys() []string {
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (s *lfuStore) len() int {
+	return len(s.entries)
+}
+
+func (s *lfuStore) clear() {
+	s.entries = make(map[string]*localEntry)
+	s.heap = nil
+}
+
+// lfuHeap orders entries by access count, then by last access
+type lfuHeap []*localEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].frequency != h[j].frequency {
+		return h[i].frequency < h[j].frequency
+	}
+	return h[i].sequence < h[j].sequence
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x interface{}) {
+	entry := x.(*localEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
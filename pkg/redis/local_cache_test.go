@@ -0,0 +1,187 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// startTestLocalCache starts a local cache receiving the invalidations of the other instances
+func startTestLocalCache(t *testing.T, client *Client, server *miniredis.Miniredis) *LocalCache {
+	t.Helper()
+	subscribers := server.PubSubNumSub(defaultInvalidationChannel)[defaultInvalidationChannel]
+	localCache := NewLocalCache(client, NewLocalCacheOptions())
+	if err := localCache.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { localCache.Close() })
+
+	// The subscription is confirmed asynchronously, a message published before would be lost
+	waitFor(t, time.Second, func() bool {
+		return server.PubSubNumSub(defaultInvalidationChannel)[defaultInvalidationChannel] > subscribers
+	})
+	return localCache
+}
+
+func TestLocalCacheEviction(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  EvictionPolicy
+		evicted string
+	}{
+		// a was read more often, but b was read last
+		{name: "lru", policy: EvictionLRU, evicted: "test::a"},
+		{name: "lfu", policy: EvictionLFU, evicted: "test::b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localCache := NewLocalCache(nil, NewLocalCacheOptions().
+				WithMaxEntries(2).
+				WithEvictionPolicy(tt.policy))
+
+			localCache.set("test", "test::a", []byte("a"), 0)
+			localCache.set("test", "test::b", []byte("b"), 0)
+			localCache.get("test::a")
+			localCache.get("test::a")
+			localCache.get("test::b")
+			localCache.set("test", "test::c", []byte("c"), 0)
+
+			for _, key := range []string{"test::a", "test::b", "test::c"} {
+				if _, ok := localCache.get(key); ok == (key == tt.evicted) {
+					t.Errorf("get(%s) found = %v, want the %s entry evicted", key, ok, tt.evicted)
+				}
+			}
+			if stats := localCache.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+				t.Errorf("Stats() = %+v, want 1 eviction and 2 entries", stats)
+			}
+		})
+	}
+}
+
+func TestLocalCacheTTL(t *testing.T) {
+	tests := []struct {
+		name      string
+		cacheName string
+		remoteTTL time.Duration
+		want      time.Duration
+	}{
+		{name: "cache TTL", cacheName: "weather", want: time.Minute},
+		{name: "default TTL", cacheName: "shorturl", want: 10 * time.Second},
+		{name: "capped by a shorter Redis TTL", cacheName: "weather", remoteTTL: 5 * time.Second, want: 5 * time.Second},
+		{name: "longer Redis TTL", cacheName: "weather", remoteTTL: time.Hour, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localCache := NewLocalCache(nil, NewLocalCacheOptions().
+				WithDefaultTTL(10*time.Second).
+				WithCacheTTL("weather", time.Minute))
+
+			start := time.Now()
+			localCache.set(tt.cacheName, "key", []byte("value"), tt.remoteTTL)
+			entry, ok := localCache.store.get("key")
+			if !ok {
+				t.Fatal("set() did not store the entry")
+			}
+			if ttl := entry.expiresAt.Sub(start); ttl < tt.want || ttl > tt.want+time.Second {
+				t.Errorf("local TTL = %v, want %v", ttl, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalCacheExpiresWithRedisTTL(t *testing.T) {
+	localCache := NewLocalCache(nil, NewLocalCacheOptions().WithCacheTTL("test", time.Minute))
+
+	localCache.set("test", "test::key", []byte("value"), 20*time.Millisecond)
+	if _, ok := localCache.get("test::key"); !ok {
+		t.Fatal("get() missed a fresh entry")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := localCache.get("test::key"); ok {
+		t.Error("get() found an entry that expired in Redis")
+	}
+	if stats := localCache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Expirations != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit, 1 miss and 1 expiration", stats)
+	}
+}
+
+func TestLocalCacheInvalidationAcrossInstances(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	first := startTestLocalCache(t, client, server)
+	second := startTestLocalCache(t, client, server)
+
+	for _, localCache := range []*LocalCache{first, second} {
+		localCache.set("weather", "weather::recife", []byte("sunny"), 0)
+		localCache.set("weather", "weather::olinda", []byte("rainy"), 0)
+		localCache.set("shorturl", "shorturl::abc", []byte("https://example"), 0)
+	}
+
+	if err := first.invalidate(ctx, "weather::recife"); err != nil {
+		t.Fatalf("invalidate() error = %v", err)
+	}
+	if _, ok := first.get("weather::recife"); ok {
+		t.Error("the invalidating instance still holds weather::recife")
+	}
+	waitFor(t, time.Second, func() bool { return second.Stats().InvalidationsReceived == 1 })
+	if _, ok := second.get("weather::recife"); ok {
+		t.Error("the other instance still holds weather::recife")
+	}
+
+	if err := second.invalidatePattern(ctx, "weather::*"); err != nil {
+		t.Fatalf("invalidatePattern() error = %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return first.Stats().InvalidationsReceived == 1 })
+	for _, localCache := range []*LocalCache{first, second} {
+		if _, ok := localCache.get("weather::olinda"); ok {
+			t.Error("an instance still holds weather::olinda after the pattern invalidation")
+		}
+		if _, ok := localCache.get("shorturl::abc"); !ok {
+			t.Error("the pattern invalidation evicted shorturl::abc")
+		}
+	}
+
+	// Each instance also received its own message and ignored it
+	if stats := first.Stats(); stats.InvalidationsSent != 1 || stats.InvalidationsReceived != 1 {
+		t.Errorf("first Stats() = %+v, want 1 invalidation sent and 1 received", stats)
+	}
+	if stats := second.Stats(); stats.InvalidationsSent != 1 || stats.InvalidationsReceived != 1 {
+		t.Errorf("second Stats() = %+v, want 1 invalidation sent and 1 received", stats)
+	}
+}
+
+func TestLocalCacheIgnoresOwnInvalidations(t *testing.T) {
+	tests := []struct {
+		name        string
+		origin      func(localCache *LocalCache) string
+		wantEvicted bool
+	}{
+		{name: "own message", origin: func(localCache *LocalCache) string { return localCache.instanceID }},
+		{name: "other instance", origin: func(*LocalCache) string { return "other" }, wantEvicted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localCache := NewLocalCache(nil, NewLocalCacheOptions())
+			// A value written again after the invalidation was published must survive its delivery
+			localCache.set("test", "test::key", []byte("new"), 0)
+
+			payload, err := json.Marshal(invalidationMessage{Origin: tt.origin(localCache), Keys: []string{"test::key"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := localCache.handleInvalidation(context.Background(), defaultInvalidationChannel, string(payload)); err != nil {
+				t.Fatalf("handleInvalidation() error = %v", err)
+			}
+
+			if _, ok := localCache.get("test::key"); ok == tt.wantEvicted {
+				t.Errorf("get() found = %v, want evicted %v", ok, tt.wantEvicted)
+			}
+		})
+	}
+}
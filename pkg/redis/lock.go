@@ -2,32 +2,47 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"math"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
+// RegisteredLock is a lock reported by the LockRegistry: Lock, RWLock and Semaphore
+type RegisteredLock interface {
+	// IsAcquired returns true if the lock is currently held by this instance
+	IsAcquired() bool
+	// GetCacheName returns the name identifying the lock in health checks
+	GetCacheName() string
+	// GetFullKey returns the full lock key with namespace
+	GetFullKey() string
+	// GetLockNamespace returns the namespace of the lock key
+	GetLockNamespace() string
+}
+
 // LockRegistry tracks active locks for health check
 type LockRegistry struct {
-	locks map[string]*Lock
+	locks map[string]RegisteredLock
 	mu    sync.RWMutex
 }
 
 // Global lock registry
 var lockRegistry = &LockRegistry{
-	locks: make(map[string]*Lock),
+	locks: make(map[string]RegisteredLock),
 }
 
 // RegisterLock registers a lock with the registry
-func (lr *LockRegistry) RegisterLock(lock *Lock) {
+func (lr *LockRegistry) RegisterLock(lock RegisteredLock) {
 	lr.mu.Lock()
 	defer lr.mu.Unlock()
 
-	if lock.opts.CacheName != "" {
-		lr.locks[lock.opts.CacheName] = lock
+	if lock.GetCacheName() != "" {
+		lr.locks[lock.GetCacheName()] = lock
 	}
 }
 
@@ -39,6 +54,18 @@ func (lr *LockRegistry) UnregisterLock(cacheName string) {
 	delete(lr.locks, cacheName)
 }
 
+// GetLocks returns the registered locks by cache name
+func (lr *LockRegistry) GetLocks() map[string]RegisteredLock {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	locks := make(map[string]RegisteredLock, len(lr.locks))
+	for cacheName, lock := range lr.locks {
+		locks[cacheName] = lock
+	}
+	return locks
+}
+
 // GetLockStatus returns the status of all registered locks
 func (lr *LockRegistry) GetLockStatus() map[string]bool {
 	lr.mu.RLock()
@@ -69,6 +96,15 @@ type LockOptions struct {
 	PersistentRefresh bool
 	// InfiniteRetry indicates if the lock should retry indefinitely
 	InfiniteRetry bool
+	// Owner identifies the holder, it is stored in the lock value so other instances can read it with Holder
+	Owner string
+	// ClockDriftFactor is the fraction of the TTL subtracted from the lock validity
+	// to compensate for clock drift between the client and the Redis nodes
+	ClockDriftFactor float64
+	// FencingTokenRetention is how long the fencing token counter is kept after the last acquisition,
+	// 0 keeps it forever. Once it expires tokens restart from 1, so locks whose tokens are checked
+	// with ValidateFencingToken must keep it forever, while locks per dynamic key should set it.
+	FencingTokenRetention time.Duration
 }
 
 // NewLockOptions creates a new lock options with default values
@@ -82,6 +118,9 @@ func NewLockOptions() *LockOptions {
 		CacheName:         "",
 		PersistentRefresh: false,
 		InfiniteRetry:     false,
+		ClockDriftFactor:  0.01,
+		// Named locks keep their counter, so tokens keep increasing across holders
+		FencingTokenRetention: 0,
 	}
 }
 
@@ -145,33 +184,161 @@ func (lo *LockOptions) WithInfiniteRetry(infinite bool) *LockOptions {
 	return lo
 }
 
+// WithOwner sets the identity of the holder stored in the lock value
+func (lo *LockOptions) WithOwner(owner string) *LockOptions {
+	lo.Owner = owner
+	return lo
+}
+
+// WithClockDriftFactor sets the fraction of the TTL reserved for clock drift
+func (lo *LockOptions) WithClockDriftFactor(factor float64) *LockOptions {
+	if factor < 0 || factor >= 1 {
+		panic(fmt.Sprintf("invalid clock drift factor: %v, must be in [0, 1)", factor))
+	}
+	lo.ClockDriftFactor = factor
+	return lo
+}
+
+// WithFencingTokenRetention sets how long the fencing token counter is kept after the last acquisition
+func (lo *LockOptions) WithFencingTokenRetention(retention time.Duration) *LockOptions {
+	if retention < 0 {
+		panic(fmt.Sprintf("invalid fencing token retention: %v, must be non-negative", retention))
+	}
+	lo.FencingTokenRetention = retention
+	return lo
+}
+
 // DefaultLockOptions returns default lock options
 func DefaultLockOptions() *LockOptions {
 	return NewLockOptions()
 }
 
-// Lock represents a distributed lock
+// fencingTokenKey builds the key of the fencing token counter of a lock, in the slot of the lock key.
+// The counter expires after FencingTokenRetention, it never expires when the retention is 0.
+func fencingTokenKey(lockKey string) string {
+	return slotKey(lockKey, "fencing")
+}
+
+// fencingResourceNamespace prefixes the keys holding the highest token seen by each fenced resource
+const fencingResourceNamespace = "fencing"
+
+// lockOwnerSeparator separates the owner from the unique part of a lock value
+const lockOwnerSeparator = "|"
+
+// clockDriftAllowance is added to the clock drift of every lock to account for the
+// precision of Redis expirations
+const clockDriftAllowance = 2 * time.Millisecond
+
+// ErrStaleFencingToken is returned by ValidateFencingToken when a newer lock holder already wrote
+var ErrStaleFencingToken = errors.New("stale fencing token")
+
+var _ RegisteredLock = (*Lock)(nil)
+
+// acquireLockScript sets the lock key (KEYS[1]) if it does not exist and increments its
+// fencing token counter (KEYS[2]). ARGV[1] is the lock value, ARGV[2] the TTL and ARGV[3] the
+// retention of the counter, both in milliseconds (0 for none). It returns the new token, or 0 when the lock is held.
+var acquireLockScript = RegisterScript("lock.acquire", `
+	local acquired
+	if tonumber(ARGV[2]) > 0 then
+		acquired = redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2])
+	else
+		acquired = redis.call("SET", KEYS[1], ARGV[1], "NX")
+	end
+	if acquired then
+		local token = redis.call("INCR", KEYS[2])
+		if tonumber(ARGV[3]) > 0 then
+			redis.call("PEXPIRE", KEYS[2], ARGV[3])
+		end
+		return token
+	end
+	return 0
+`)
+
+// raiseFencingTokenScript raises a fencing token counter (KEYS[1]) to at least ARGV[1],
+// keeping it for ARGV[2] milliseconds (0 for ever)
+var raiseFencingTokenScript = RegisterScript("lock.raise-fencing-token", `
+	local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+	if current < tonumber(ARGV[1]) then
+		redis.call("SET", KEYS[1], ARGV[1], "KEEPTTL")
+	end
+	if tonumber(ARGV[2]) > 0 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	return 1
+`)
+
+// releaseLockScript deletes the lock key (KEYS[1]) only if it still holds our value (ARGV[1])
+var releaseLockScript = RegisterScript("lock.release", `
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("DEL", KEYS[1])
+	else
+		return 0
+	end
+`)
+
+// refreshLockScript extends the lock key (KEYS[1]) to ARGV[2] milliseconds (0 for none)
+// only if it still holds our value (ARGV[1])
+var refreshLockScript = RegisterScript("lock.refresh", `
+	if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+		return 0
+	end
+	if tonumber(ARGV[2]) > 0 then
+		return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	redis.call("PERSIST", KEYS[1])
+	return 1
+`)
+
+// validateFencingTokenScript records ARGV[1] as the highest token seen by a resource (KEYS[1]),
+// rejecting tokens lower than the recorded one. It returns {accepted, highest token}.
+var validateFencingTokenScript = RegisterScript("lock.validate-fencing-token", `
+	local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+	local token = tonumber(ARGV[1])
+	if token < current then
+		return {0, current}
+	end
+	redis.call("SET", KEYS[1], ARGV[1])
+	return {1, token}
+`)
+
+// Lock represents a distributed lock.
+// Every acquisition returns a fencing token that is greater than the tokens of all previous
+// holders, so downstream writes can reject a holder whose lock expired while it was paused.
+// A lock created with NewRedlock is held on a majority of independent Redis nodes.
 type Lock struct {
-	client      *Client
-	key         string
-	value       string
-	opts        *LockOptions
-	refreshStop chan struct{}
-	refreshing  bool
-	acquired    bool
+	client    *Client
+	clients   []*Client
+	key       string
+	value     string
+	opts      *LockOptions
+	refresher autoRefresher
+	acquired  bool
+	mu        sync.RWMutex
+	token     int64
+	expiresAt time.Time
 }
 
 // NewLock creates a new distributed lock
 func NewLock(client *Client, key string, opts *LockOptions) *Lock {
+	return NewRedlock([]*Client{client}, key, opts)
+}
+
+// NewRedlock creates a distributed lock held on a quorum (a majority) of independent Redis nodes,
+// following the Redlock algorithm. The nodes must not be replicas of each other.
+// With a single client it behaves exactly like NewLock.
+func NewRedlock(clients []*Client, key string, opts *LockOptions) *Lock {
+	if len(clients) == 0 {
+		panic("redlock requires at least one client")
+	}
 	if opts == nil {
 		opts = DefaultLockOptions()
 	}
 	lock := &Lock{
-		client:      client,
-		key:         key,
-		value:       generateLockValue(),
-		opts:        opts,
-		refreshStop: make(chan struct{}),
+		client:  clients[0],
+		clients: clients,
+		key:     key,
+		value:   generateLockValue(opts.Owner),
+		opts:    opts,
 	}
 
 	// Register lock if it has a cache name
@@ -190,68 +357,142 @@ func (l *Lock) buildLockKey() string {
 	return l.key
 }
 
-// Lock attempts to acquire the lock
-func (l *Lock) Lock(ctx context.Context) error {
-	fullKey := l.buildLockKey()
+// quorum returns the number of nodes that must agree for the lock to be held
+func (l *Lock) quorum() int {
+	return len(l.clients)/2 + 1
+}
 
-	// Handle infinite retry scenario
-	if l.opts.InfiniteRetry {
-		for {
-			// Try to acquire the lock using SET with NX and EX options
-			result, err := l.client.GetClient().SetNX(ctx, fullKey, l.value, l.opts.TTL).Result()
-			if err != nil {
-				return fmt.Errorf("failed to acquire lock: %w", err)
-			}
+// clockDrift returns the part of the TTL reserved for clock drift
+func (l *Lock) clockDrift() time.Duration {
+	return lockClockDrift(l.opts.TTL, l.opts.ClockDriftFactor)
+}
 
-			if result {
-				l.acquired = true
-				return nil
-			}
+// lockClockDrift returns the clock drift compensation of a TTL
+func lockClockDrift(ttl time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(ttl)*factor) + clockDriftAllowance
+}
 
-			// Wait before retrying
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(l.opts.RetryDelay):
-				continue
-			}
-		}
+// lockValidity returns how long a lock stays valid after taking elapsed to acquire it on the nodes
+func lockValidity(ttl time.Duration, elapsed time.Duration, factor float64) time.Duration {
+	return ttl - elapsed - lockClockDrift(ttl, factor)
+}
+
+// Lock attempts to acquire the lock and returns its fencing token.
+// Pass the token along with every write made under the lock, see ValidateFencingToken.
+func (l *Lock) Lock(ctx context.Context) (int64, error) {
+	acquired, err := acquireWithRetry(ctx, l.opts, l.tryAcquire)
+	if err != nil {
+		return 0, err
 	}
+	if !acquired {
+		return 0, fmt.Errorf("failed to acquire lock after %d attempts", l.opts.MaxRetries)
+	}
+	return l.Token(), nil
+}
 
-	// Handle finite retry scenario
-	for attempt := 0; attempt < l.opts.MaxRetries; attempt++ {
-		// Try to acquire the lock using SET with NX and EX options
-		result, err := l.client.GetClient().SetNX(ctx, fullKey, l.value, l.opts.TTL).Result()
-		if err != nil {
-			return fmt.Errorf("failed to acquire lock: %w", err)
+// acquireWithRetry calls try until it acquires, following the retry options of a lock.
+// It returns false when the retries are exhausted.
+func acquireWithRetry(ctx context.Context, opts *LockOptions, try func(ctx context.Context) (bool, error)) (bool, error) {
+	for attempt := 0; ; attempt++ {
+		acquired, err := try(ctx)
+		if err != nil || acquired {
+			return acquired, err
 		}
 
-		if result {
-			l.acquired = true
-			return nil
+		if !opts.InfiniteRetry && attempt >= opts.MaxRetries {
+			return false, nil
 		}
 
 		// Wait before retrying
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(l.opts.RetryDelay):
+			return false, ctx.Err()
+		case <-time.After(opts.RetryDelay):
+		}
+	}
+}
+
+// tryAcquire makes a single attempt to acquire the lock on a quorum of nodes.
+// Nodes that fail are tolerated as long as a quorum can still be reached.
+func (l *Lock) tryAcquire(ctx context.Context) (bool, error) {
+	fullKey := l.buildLockKey()
+	keys := []string{fullKey, fencingTokenKey(fullKey)}
+	start := time.Now()
+
+	var acquiredOn []*Client
+	var token int64
+	var lastErr error
+	failures := 0
+	for _, client := range l.clients {
+		nodeToken, err := acquireLockScript.Run(ctx, client.GetClient(), keys, l.value, l.opts.TTL.Milliseconds(),
+			l.opts.FencingTokenRetention.Milliseconds()).Int64()
+		if err != nil {
+			lastErr = err
+			failures++
 			continue
 		}
+		if nodeToken > 0 {
+			acquiredOn = append(acquiredOn, client)
+			token = max(token, nodeToken)
+		}
 	}
 
-	// Final attempt
-	result, err := l.client.GetClient().SetNX(ctx, fullKey, l.value, l.opts.TTL).Result()
-	if err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
+	if len(l.clients)-failures < l.quorum() {
+		l.releaseOn(ctx, acquiredOn)
+		return false, fmt.Errorf("failed to acquire lock: %w", lastErr)
+	}
+	if len(acquiredOn) < l.quorum() {
+		l.releaseOn(ctx, acquiredOn)
+		return false, nil
+	}
+
+	// Counters of different nodes drift apart, every node of the quorum is raised to the
+	// token, so any later quorum, which shares at least one node, issues a greater token
+	if len(l.clients) > 1 && !l.raiseFencingToken(ctx, acquiredOn, keys[1], token) {
+		l.releaseOn(ctx, acquiredOn)
+		return false, nil
+	}
+
+	if l.opts.TTL > 0 && lockValidity(l.opts.TTL, time.Since(start), l.opts.ClockDriftFactor) <= 0 {
+		// Acquiring took longer than the TTL, the lock may already have expired on some nodes
+		l.releaseOn(ctx, acquiredOn)
+		return false, nil
 	}
 
-	if result {
-		l.acquired = true
-		return nil
+	l.mu.Lock()
+	l.acquired = true
+	l.token = token
+	l.expiresAt = l.validUntil(start)
+	l.mu.Unlock()
+	return true, nil
+}
+
+// raiseFencingToken raises the token counter of the nodes, reporting whether a quorum succeeded
+func (l *Lock) raiseFencingToken(ctx context.Context, clients []*Client, tokenKey string, token int64) bool {
+	raised := 0
+	for _, client := range clients {
+		if err := raiseFencingTokenScript.Run(ctx, client.GetClient(), []string{tokenKey}, token,
+			l.opts.FencingTokenRetention.Milliseconds()).Err(); err == nil {
+			raised++
+		}
 	}
+	return raised >= l.quorum()
+}
 
-	return fmt.Errorf("failed to acquire lock after %d attempts", l.opts.MaxRetries)
+// releaseOn deletes the lock from the nodes, ignoring failures and cancellation
+func (l *Lock) releaseOn(ctx context.Context, clients []*Client) {
+	ctx = context.WithoutCancel(ctx)
+	for _, client := range clients {
+		releaseLockScript.Run(ctx, client.GetClient(), []string{l.buildLockKey()}, l.value)
+	}
+}
+
+// validUntil returns the time until which a lock set at start is valid, the zero time when it never expires
+func (l *Lock) validUntil(start time.Time) time.Time {
+	if l.opts.TTL <= 0 {
+		return time.Time{}
+	}
+	return start.Add(l.opts.TTL - l.clockDrift())
 }
 
 // Unlock releases the lock
@@ -260,25 +501,29 @@ func (l *Lock) Unlock(ctx context.Context) error {
 	l.StopAutoRefresh()
 
 	// Use Lua script to ensure we only delete our own lock
-	script := `
-		if redis.call("GET", KEYS[1]) == ARGV[1] then
-			return redis.call("DEL", KEYS[1])
-		else
-			return 0
-		end
-	`
-
 	fullKey := l.buildLockKey()
-	result, err := l.client.GetClient().Eval(ctx, script, []string{fullKey}, l.value).Result()
-	if err != nil {
-		return fmt.Errorf("failed to release lock: %w", err)
+	released := 0
+	var lastErr error
+	for _, client := range l.clients {
+		result, err := releaseLockScript.Run(ctx, client.GetClient(), []string{fullKey}, l.value).Int64()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if result > 0 {
+			released++
+		}
 	}
 
-	if result.(int64) == 0 {
+	if released < l.quorum() {
+		if lastErr != nil {
+			return fmt.Errorf("failed to release lock: %w", lastErr)
+		}
+		l.setAcquired(false)
 		return fmt.Errorf("lock was not held by this client")
 	}
 
-	l.acquired = false
+	l.setAcquired(false)
 
 	// Unregister lock from registry
 	if l.opts.CacheName != "" {
@@ -291,58 +536,180 @@ func (l *Lock) Unlock(ctx context.Context) error {
 // Refresh extends the lock's TTL
 func (l *Lock) Refresh(ctx context.Context) error {
 	// Use Lua script to ensure we only refresh our own lock
-	script := `
-		if redis.call("GET", KEYS[1]) == ARGV[1] then
-			return redis.call("EXPIRE", KEYS[1], ARGV[2])
-		else
-			return 0
-		end
-	`
-
 	fullKey := l.buildLockKey()
-	result, err := l.client.GetClient().Eval(ctx, script, []string{fullKey}, l.value, int(l.opts.TTL.Seconds())).Result()
-	if err != nil {
-		return fmt.Errorf("failed to refresh lock: %w", err)
+	start := time.Now()
+	refreshed := 0
+	var lastErr error
+	for _, client := range l.clients {
+		result, err := refreshLockScript.Run(ctx, client.GetClient(), []string{fullKey}, l.value, l.opts.TTL.Milliseconds()).Int64()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if result > 0 {
+			refreshed++
+		}
 	}
 
-	if result.(int64) == 0 {
+	if refreshed < l.quorum() {
+		if lastErr != nil {
+			return fmt.Errorf("failed to refresh lock: %w", lastErr)
+		}
+		l.setAcquired(false)
 		return fmt.Errorf("lock was not held by this client or has expired")
 	}
 
+	l.mu.Lock()
+	l.expiresAt = l.validUntil(start)
+	l.mu.Unlock()
 	return nil
 }
 
-// IsLocked checks if the lock is currently held
-func (l *Lock) IsLocked(ctx context.Context) (bool, error) {
+// Holder returns the owner of the instance currently holding the lock on a quorum of nodes,
+// an empty string when the lock is free or its holder set no owner
+func (l *Lock) Holder(ctx context.Context) (string, error) {
 	fullKey := l.buildLockKey()
-	value, err := l.client.Get(ctx, fullKey)
+	votes := make(map[string]int)
+	var lastErr error
+	for _, client := range l.clients {
+		value, err := client.Get(ctx, fullKey)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if value != "" {
+			votes[value]++
+		}
+	}
+
+	for value, count := range votes {
+		if count >= l.quorum() {
+			return lockValueOwner(value), nil
+		}
+	}
+	if lastErr != nil {
+		return "", fmt.Errorf("failed to read lock holder: %w", lastErr)
+	}
+	return "", nil
+}
+
+// Token returns the fencing token of the last acquisition, 0 if the lock was never acquired
+func (l *Lock) Token() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.token
+}
+
+// Validity returns how long the lock is still guaranteed to be held, accounting for clock drift.
+// It is 0 when the lock is not acquired or has expired, so long-running work can check it
+// before each side effect. A lock without TTL never expires.
+func (l *Lock) Validity() time.Duration {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if !l.acquired {
+		return 0
+	}
+	if l.expiresAt.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return max(time.Until(l.expiresAt), 0)
+}
+
+// setAcquired updates the acquired flag
+func (l *Lock) setAcquired(acquired bool) {
+	l.mu.Lock()
+	l.acquired = acquired
+	l.mu.Unlock()
+}
+
+// ValidateFencingToken records a write made with a fencing token on a resource and rejects it
+// with ErrStaleFencingToken when a greater token was already used on the same resource, meaning
+// the lock expired and another holder took over. Tokens equal to the last one are accepted.
+func ValidateFencingToken(ctx context.Context, client *Client, resource string, token int64) error {
+	key := fencingResourceNamespace + "::" + resource
+	result, err := validateFencingTokenScript.Run(ctx, client.GetClient(), []string{key}, token).Int64Slice()
 	if err != nil {
-		if err == redis.Nil {
-			return false, nil
+		return fmt.Errorf("failed to validate fencing token: %w", err)
+	}
+
+	if result[0] == 0 {
+		return fmt.Errorf("%w: token %d is older than %d for %s", ErrStaleFencingToken, token, result[1], resource)
+	}
+	return nil
+}
+
+// IsLocked checks if the lock is currently held by this instance on a quorum of nodes
+func (l *Lock) IsLocked(ctx context.Context) (bool, error) {
+	fullKey := l.buildLockKey()
+	held := 0
+	var lastErr error
+	for _, client := range l.clients {
+		value, err := client.Get(ctx, fullKey)
+		if err != nil {
+			if err != redis.Nil {
+				lastErr = err
+			}
+			continue
+		}
+		if value == l.value {
+			held++
 		}
-		return false, err
 	}
 
-	return value == l.value, nil
+	if held < l.quorum() && lastErr != nil {
+		return false, lastErr
+	}
+	return held >= l.quorum(), nil
 }
 
 // AutoRefresh starts a goroutine that automatically refreshes the lock
 // If the context is cancelled, the auto-refresh will stop
 func (l *Lock) AutoRefresh(ctx context.Context) <-chan error {
+	return l.refresher.start(ctx, l.opts, l.Refresh)
+}
+
+// StopAutoRefresh stops the auto-refresh goroutine
+func (l *Lock) StopAutoRefresh() {
+	l.refresher.stop()
+}
+
+// IsAutoRefreshing returns true if auto-refresh is currently running
+func (l *Lock) IsAutoRefreshing() bool {
+	return l.refresher.isRunning()
+}
+
+// autoRefresher refreshes a lock every RefreshInterval in a goroutine, it is shared by all lock types
+type autoRefresher struct {
+	mu          sync.Mutex
+	refreshStop chan struct{}
+	refreshing  bool
+}
+
+// start starts refreshing with the refresh function of a lock
+func (r *autoRefresher) start(ctx context.Context, opts *LockOptions, refresh func(ctx context.Context) error) <-chan error {
 	errChan := make(chan error, 1)
 
-	if l.refreshing {
+	r.mu.Lock()
+	if r.refreshing {
+		r.mu.Unlock()
 		errChan <- fmt.Errorf("auto-refresh is already running")
 		return errChan
 	}
 
 	// Create new channel for this refresh session
-	l.refreshStop = make(chan struct{})
-	l.refreshing = true
+	refreshStop := make(chan struct{})
+	r.refreshStop = refreshStop
+	r.refreshing = true
+	r.mu.Unlock()
 
 	go func() {
 		defer func() {
-			l.refreshing = false
+			r.mu.Lock()
+			if r.refreshStop == refreshStop {
+				r.refreshing = false
+			}
+			r.mu.Unlock()
 			// Always send a completion signal when the goroutine exits
 			select {
 			case errChan <- nil:
@@ -351,7 +718,7 @@ func (l *Lock) AutoRefresh(ctx context.Context) <-chan error {
 			}
 		}()
 
-		ticker := time.NewTicker(l.opts.RefreshInterval)
+		ticker := time.NewTicker(opts.RefreshInterval)
 		defer ticker.Stop()
 
 		for {
@@ -365,7 +732,7 @@ func (l *Lock) AutoRefresh(ctx context.Context) <-chan error {
 					// Channel might be closed, ignore
 				}
 				return
-			case <-l.refreshStop:
+			case <-refreshStop:
 				// Lock was released, stop refreshing
 				// Completion signal will be sent by defer
 				return
@@ -373,11 +740,11 @@ func (l *Lock) AutoRefresh(ctx context.Context) <-chan error {
 				// For persistent refresh, use background context to avoid cancellation
 				// For non-persistent refresh, use the provided context
 				refreshCtx := ctx
-				if l.opts.PersistentRefresh {
+				if opts.PersistentRefresh {
 					refreshCtx = context.Background()
 				}
-				if err := l.Refresh(refreshCtx); err != nil {
-					if !l.opts.PersistentRefresh {
+				if err := refresh(refreshCtx); err != nil {
+					if !opts.PersistentRefresh {
 						// For non-persistent refresh, send the refresh error
 						select {
 						case errChan <- err:
@@ -396,22 +763,27 @@ func (l *Lock) AutoRefresh(ctx context.Context) <-chan error {
 	return errChan
 }
 
-// StopAutoRefresh stops the auto-refresh goroutine
-func (l *Lock) StopAutoRefresh() {
-	if l.refreshing {
+// stop stops the auto-refresh goroutine
+func (r *autoRefresher) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.refreshing {
 		select {
-		case <-l.refreshStop:
+		case <-r.refreshStop:
 			// Channel already closed, nothing to do
 		default:
-			close(l.refreshStop)
+			close(r.refreshStop)
 		}
-		l.refreshing = false
+		r.refreshing = false
 	}
 }
 
-// IsAutoRefreshing returns true if auto-refresh is currently running
-func (l *Lock) IsAutoRefreshing() bool {
-	return l.refreshing
+// isRunning returns true if auto-refresh is currently running
+func (r *autoRefresher) isRunning() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refreshing
 }
 
 // LockWithFunc executes a function while holding a lock
@@ -419,7 +791,7 @@ func LockWithFunc(ctx context.Context, client *Client, key string, opts *LockOpt
 	lock := NewLock(client, key, opts)
 
 	// Acquire lock
-	if err := lock.Lock(ctx); err != nil {
+	if _, err := lock.Lock(ctx); err != nil {
 		return fmt.Errorf("failed to acquire lock: %w", err)
 	}
 
@@ -446,6 +818,8 @@ func LockWithTimeout(ctx context.Context, client *Client, key string, opts *Lock
 
 // IsAcquired returns true if the lock is currently acquired by this instance
 func (l *Lock) IsAcquired() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	return l.acquired
 }
 
@@ -464,6 +838,11 @@ func (l *Lock) GetFullKey() string {
 	return l.buildLockKey()
 }
 
+// GetLockNamespace returns the namespace of the lock key
+func (l *Lock) GetLockNamespace() string {
+	return l.opts.LockNamespace
+}
+
 // NewSingleAttemptLock creates a lock for scenario 1 (single attempt, no retry)
 func NewSingleAttemptLock(client *Client, key string, ttl time.Duration, namespace string) *Lock {
 	opts := NewLockOptions().
@@ -514,7 +893,19 @@ func GetLockStatus() map[string]bool {
 	return lockRegistry.GetLockStatus()
 }
 
-// generateLockValue generates a unique value for the lock
-func generateLockValue() string {
-	return strconv.FormatInt(time.Now().UnixNano(), 10)
+// generateLockValue generates a unique value for the lock, so instances started
+// at the same time never release each other's lock. The owner, if any, prefixes it.
+func generateLockValue(owner string) string {
+	if owner == "" {
+		return uuid.NewString()
+	}
+	return owner + lockOwnerSeparator + uuid.NewString()
+}
+
+// lockValueOwner returns the owner stored in a lock value
+func lockValueOwner(value string) string {
+	if i := strings.LastIndex(value, lockOwnerSeparator); i >= 0 {
+		return value[:i]
+	}
+	return ""
 }
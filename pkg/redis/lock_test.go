@@ -0,0 +1,365 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestClient starts an in-memory Redis server and returns a client connected to it
+func newTestClient(t testing.TB) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+
+	port, err := strconv.Atoi(server.Port())
+	if err != nil {
+		t.Fatalf("invalid miniredis port %q: %v", server.Port(), err)
+	}
+
+	client := NewClient(NewRedisConfig().
+		WithHost(server.Host()).
+		WithPort(port).
+		WithMaxRetries(0).
+		WithDialTimeout(200 * time.Millisecond))
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+// newTestClients starts n independent in-memory Redis servers
+func newTestClients(t testing.TB, n int) ([]*Client, []*miniredis.Miniredis) {
+	t.Helper()
+	clients := make([]*Client, n)
+	servers := make([]*miniredis.Miniredis, n)
+	for i := range clients {
+		clients[i], servers[i] = newTestClient(t)
+	}
+	return clients, servers
+}
+
+// testLockOptions returns options for a single acquisition attempt
+func testLockOptions(ttl time.Duration) *LockOptions {
+	return NewLockOptions().
+		WithTTL(ttl).
+		WithMaxRetries(0).
+		WithLockNamespace("test-locks")
+}
+
+func TestLockValidity(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		elapsed time.Duration
+		factor  float64
+		want    time.Duration
+	}{
+		{name: "no elapsed time", ttl: 10 * time.Second, factor: 0.01, want: 10*time.Second - 100*time.Millisecond - clockDriftAllowance},
+		{name: "elapsed time", ttl: 10 * time.Second, elapsed: 2 * time.Second, factor: 0.01, want: 8*time.Second - 100*time.Millisecond - clockDriftAllowance},
+		{name: "no drift factor", ttl: time.Second, elapsed: 500 * time.Millisecond, want: 500*time.Millisecond - clockDriftAllowance},
+		{name: "expired while acquiring", ttl: 100 * time.Millisecond, elapsed: 99 * time.Millisecond, factor: 0.01, want: -2 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lockValidity(tt.ttl, tt.elapsed, tt.factor); got != tt.want {
+				t.Errorf("lockValidity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockQuorum(t *testing.T) {
+	for nodes, want := range map[int]int{1: 1, 2: 2, 3: 2, 4: 3, 5: 3} {
+		lock := NewRedlock(make([]*Client, nodes), "quorum", nil)
+		if got := lock.quorum(); got != want {
+			t.Errorf("quorum of %d nodes = %d, want %d", nodes, got, want)
+		}
+	}
+}
+
+func TestLockFencingTokensIncrease(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	var previous int64
+	for i := 0; i < 5; i++ {
+		lock := NewLock(client, "ordered", testLockOptions(time.Second))
+		token, err := lock.Lock(ctx)
+		if err != nil {
+			t.Fatalf("Lock() #%d error = %v", i, err)
+		}
+		if token <= previous {
+			t.Fatalf("Lock() #%d token = %d, want greater than %d", i, token, previous)
+		}
+		if lock.Token() != token {
+			t.Errorf("Token() = %d, want %d", lock.Token(), token)
+		}
+		if err := lock.Unlock(ctx); err != nil {
+			t.Fatalf("Unlock() #%d error = %v", i, err)
+		}
+		previous = token
+	}
+}
+
+func TestLockFencingTokenRetention(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	permanent := NewLock(client, "permanent", testLockOptions(time.Second))
+	if _, err := permanent.Lock(ctx); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if ttl := server.TTL(fencingTokenKey(permanent.buildLockKey())); ttl != 0 {
+		t.Errorf("counter TTL without retention = %v, want none", ttl)
+	}
+
+	opts := testLockOptions(time.Second).WithFencingTokenRetention(time.Minute)
+	retained := NewLock(client, "retained", opts)
+	if _, err := retained.Lock(ctx); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if err := retained.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	tokenKey := fencingTokenKey(retained.buildLockKey())
+	if ttl := server.TTL(tokenKey); ttl != time.Minute {
+		t.Errorf("counter TTL = %v, want %v", ttl, time.Minute)
+	}
+
+	server.FastForward(30 * time.Second)
+	token, err := NewLock(client, "retained", opts).Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if token != 2 {
+		t.Errorf("Lock() token within retention = %d, want 2", token)
+	}
+	if ttl := server.TTL(tokenKey); ttl != time.Minute {
+		t.Errorf("counter TTL after acquisition = %v, want refreshed to %v", ttl, time.Minute)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if server.Exists(tokenKey) {
+		t.Error("counter still exists after its retention")
+	}
+}
+
+func TestLockHeldByAnotherInstance(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	holder := NewLock(client, "contended", testLockOptions(time.Second))
+	if _, err := holder.Lock(ctx); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	contender := NewLock(client, "contended", testLockOptions(time.Second))
+	if _, err := contender.Lock(ctx); err == nil {
+		t.Fatal("Lock() of a held lock succeeded")
+	}
+	if contender.Token() != 0 || contender.Validity() != 0 {
+		t.Errorf("contender Token() = %d, Validity() = %v, want 0", contender.Token(), contender.Validity())
+	}
+	if err := contender.Unlock(ctx); err == nil {
+		t.Error("Unlock() of a lock held by another instance succeeded")
+	}
+
+	if locked, err := holder.IsLocked(ctx); err != nil || !locked {
+		t.Errorf("holder IsLocked() = %v, %v, want true", locked, err)
+	}
+}
+
+func TestLockExpiryDuringWork(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	ttl := 100 * time.Millisecond
+
+	paused := NewLock(client, "report", testLockOptions(ttl))
+	pausedToken, err := paused.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if validity := paused.Validity(); validity <= 0 || validity > ttl {
+		t.Fatalf("Validity() = %v, want within (0, %v]", validity, ttl)
+	}
+	if err := ValidateFencingToken(ctx, client, "report", pausedToken); err != nil {
+		t.Fatalf("ValidateFencingToken() before the pause error = %v", err)
+	}
+
+	// The holder pauses longer than the TTL, and the lock expires in Redis
+	time.Sleep(ttl + 20*time.Millisecond)
+	server.FastForward(ttl + 20*time.Millisecond)
+
+	if validity := paused.Validity(); validity != 0 {
+		t.Errorf("Validity() after expiry = %v, want 0", validity)
+	}
+
+	active := NewLock(client, "report", testLockOptions(time.Second))
+	activeToken, err := active.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() after expiry error = %v", err)
+	}
+	if activeToken <= pausedToken {
+		t.Fatalf("new holder token = %d, want greater than %d", activeToken, pausedToken)
+	}
+	if err := ValidateFencingToken(ctx, client, "report", activeToken); err != nil {
+		t.Fatalf("ValidateFencingToken() of the new holder error = %v", err)
+	}
+
+	// The paused holder resumes, its writes and lock operations must all be rejected
+	if err := ValidateFencingToken(ctx, client, "report", pausedToken); !errors.Is(err, ErrStaleFencingToken) {
+		t.Errorf("ValidateFencingToken() of the paused holder error = %v, want %v", err, ErrStaleFencingToken)
+	}
+	if err := paused.Refresh(ctx); err == nil {
+		t.Error("Refresh() of an expired lock succeeded")
+	}
+	if paused.IsAcquired() {
+		t.Error("IsAcquired() after a failed refresh = true, want false")
+	}
+	if err := paused.Unlock(ctx); err == nil {
+		t.Error("Unlock() of an expired lock succeeded")
+	}
+
+	if locked, err := active.IsLocked(ctx); err != nil || !locked {
+		t.Errorf("new holder IsLocked() = %v, %v, want true", locked, err)
+	}
+	if err := ValidateFencingToken(ctx, client, "report", activeToken); err != nil {
+		t.Errorf("ValidateFencingToken() repeated by the new holder error = %v", err)
+	}
+}
+
+func TestLockRefreshExtendsValidity(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	ttl := 100 * time.Millisecond
+
+	lock := NewLock(client, "refreshed", testLockOptions(ttl))
+	if _, err := lock.Lock(ctx); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := lock.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	server.FastForward(60 * time.Millisecond)
+
+	if lock.Validity() <= 0 {
+		t.Error("Validity() after refresh = 0, want positive")
+	}
+	if locked, err := lock.IsLocked(ctx); err != nil || !locked {
+		t.Errorf("IsLocked() after refresh = %v, %v, want true", locked, err)
+	}
+}
+
+func TestRedlockQuorum(t *testing.T) {
+	clients, servers := newTestClients(t, 3)
+	ctx := context.Background()
+
+	lock := NewRedlock(clients, "quorum", testLockOptions(time.Second))
+	if _, err := lock.Lock(ctx); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	for i, server := range servers {
+		if !server.Exists("test-locks::quorum") {
+			t.Errorf("lock key missing on node %d", i)
+		}
+	}
+
+	// Holding a single node is not enough to take the lock
+	servers[0].Del("test-locks::quorum")
+	contender := NewRedlock(clients, "quorum", testLockOptions(time.Second))
+	if _, err := contender.Lock(ctx); err == nil {
+		t.Fatal("Lock() with a single free node succeeded")
+	}
+	if servers[0].Exists("test-locks::quorum") {
+		t.Error("failed acquisition left its key on node 0")
+	}
+
+	if err := lock.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	for i, server := range servers {
+		if server.Exists("test-locks::quorum") {
+			t.Errorf("lock key still present on node %d", i)
+		}
+	}
+}
+
+func TestRedlockNodeFailures(t *testing.T) {
+	clients, servers := newTestClients(t, 3)
+	ctx := context.Background()
+
+	servers[2].Close()
+	lock := NewRedlock(clients, "failures", testLockOptions(time.Second))
+	if _, err := lock.Lock(ctx); err != nil {
+		t.Fatalf("Lock() with one node down error = %v", err)
+	}
+	if err := lock.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() with one node down error = %v", err)
+	}
+
+	servers[1].Close()
+	lock = NewRedlock(clients, "failures", testLockOptions(time.Second))
+	if _, err := lock.Lock(ctx); err == nil {
+		t.Fatal("Lock() with two nodes down succeeded")
+	}
+	if servers[0].Exists("test-locks::failures") {
+		t.Error("failed acquisition left its key on node 0")
+	}
+}
+
+func TestRedlockFencingTokenOrdering(t *testing.T) {
+	clients, servers := newTestClients(t, 3)
+	ctx := context.Background()
+
+	// Counters of the nodes drifted apart, for example after a node was replaced
+	if err := servers[0].Set(fencingTokenKey("test-locks::ordered"), "10"); err != nil {
+		t.Fatal(err)
+	}
+
+	first := NewRedlock(clients, "ordered", testLockOptions(time.Second))
+	firstToken, err := first.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if firstToken != 11 {
+		t.Errorf("first token = %d, want 11", firstToken)
+	}
+	if err := first.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+
+	// The next quorum no longer includes the node that issued the first token
+	servers[0].Close()
+	second := NewRedlock(clients, "ordered", testLockOptions(time.Second))
+	secondToken, err := second.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() with one node down error = %v", err)
+	}
+	if secondToken <= firstToken {
+		t.Errorf("second token = %d, want greater than %d", secondToken, firstToken)
+	}
+}
+
+func TestValidateFencingTokenPerResource(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	if err := ValidateFencingToken(ctx, client, "orders", 5); err != nil {
+		t.Fatalf("ValidateFencingToken() error = %v", err)
+	}
+	if err := ValidateFencingToken(ctx, client, "invoices", 1); err != nil {
+		t.Errorf("ValidateFencingToken() of another resource error = %v", err)
+	}
+	if err := ValidateFencingToken(ctx, client, "orders", 4); !errors.Is(err, ErrStaleFencingToken) {
+		t.Errorf("ValidateFencingToken() of an older token error = %v, want %v", err, ErrStaleFencingToken)
+	}
+	if err := ValidateFencingToken(ctx, client, "orders", 6); err != nil {
+		t.Errorf("ValidateFencingToken() of a newer token error = %v", err)
+	}
+}
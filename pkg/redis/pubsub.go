@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -57,6 +58,9 @@ type PubSubConfig struct {
 	MaxReconnectAttempts int
 	// ChannelNamespace is the namespace for organizing channels
 	ChannelNamespace string
+	// QueueSize is the number of messages each handler can have waiting, messages received
+	// while every queue is full are dropped so a slow handler never blocks the connection
+	QueueSize int
 }
 
 // NewPubSubConfig creates a new pub/sub configuration with default values
@@ -67,6 +71,7 @@ func NewPubSubConfig() *PubSubConfig {
 		ReconnectDelay:       1 * time.Second,
 		MaxReconnectAttempts: 10,
 		ChannelNamespace:     "",
+		QueueSize:            100,
 	}
 }
 
@@ -109,14 +114,23 @@ func (psc *PubSubConfig) WithChannelNamespace(namespace string) *PubSubConfig {
 	return psc
 }
 
+// WithQueueSize sets the number of messages each handler can have waiting
+func (psc *PubSubConfig) WithQueueSize(queueSize int) *PubSubConfig {
+	if queueSize < 1 {
+		panic(fmt.Sprintf("invalid queue size: %d, must be greater than 0", queueSize))
+	}
+	psc.QueueSize = queueSize
+	return psc
+}
+
 // Publisher handles Redis publishing operations
 type Publisher struct {
-	client *redis.Client
+	client redis.UniversalClient
 	config *PubSubConfig
 }
 
 // NewPublisher creates a new publisher
-func NewPublisher(client *redis.Client, config *PubSubConfig) *Publisher {
+func NewPublisher(client redis.UniversalClient, config *PubSubConfig) *Publisher {
 	if config == nil {
 		config = NewPubSubConfig()
 	}
@@ -150,12 +164,14 @@ func (p *Publisher) PublishJSON(ctx context.Context, channel string, message int
 	return p.client.Publish(ctx, fullChannel, jsonData).Err()
 }
 
-// Subscriber polls and processes messages from Redis pub/sub channels
+// Subscriber polls and processes messages from Redis pub/sub channels and patterns.
+// A single connection receives the messages and dispatches them to the queues of PoolSize handlers.
 type Subscriber struct {
-	client               *redis.Client
+	client               redis.UniversalClient
 	channels             []string
 	patterns             []string
 	poolSize             int
+	queueSize            int
 	logLevel             LogLevel
 	reconnectDelay       time.Duration
 	maxReconnectAttempts int
@@ -163,9 +179,12 @@ type Subscriber struct {
 	handler              MessageHandler
 	isRunning            int32 // atomic flag to track if subscriber is running
 	messagesProcessed    int64 // atomic counter for processed messages
+	messagesDropped      int64 // atomic counter for messages dropped with full queues
 	reconnectAttempts    int32 // atomic counter for reconnect attempts
 	mu                   sync.RWMutex
 	sub                  *redis.PubSub
+	queues               []chan *redis.Message
+	wg                   sync.WaitGroup
 	ctx                  context.Context    // internal context for lifecycle management
 	cancel               context.CancelFunc // cancel function to stop subscriber
 }
@@ -175,14 +194,17 @@ type Subscriber struct {
 // If the provided PubSubConfig is nil or its fields are zero,
 // the following defaults will be used:
 //   - PoolSize: 1
+//   - QueueSize: 100
 //   - LogLevel: Silent
 //   - ReconnectDelay: 1 second
 //   - MaxReconnectAttempts: 10
 //
 // Validations:
 //   - PoolSize must be greater than 0.
-func NewSubscriber(client *redis.Client, handler MessageHandler, config *PubSubConfig) (*Subscriber, error) {
+//   - QueueSize must be greater than 0.
+func NewSubscriber(client redis.UniversalClient, handler MessageHandler, config *PubSubConfig) (*Subscriber, error) {
 	var poolSize = 1
+	var queueSize = 100
 	var logLevel LogLevel = Silent
 	var reconnectDelay = 1 * time.Second
 	var maxReconnectAttempts = 10
@@ -192,6 +214,9 @@ func NewSubscriber(client *redis.Client, handler MessageHandler, config *PubSubC
 		if config.PoolSize != 0 {
 			poolSize = config.PoolSize
 		}
+		if config.QueueSize != 0 {
+			queueSize = config.QueueSize
+		}
 		if config.LogLevel != 0 {
 			logLevel = config.LogLevel
 		}
@@ -207,12 +232,16 @@ func NewSubscriber(client *redis.Client, handler MessageHandler, config *PubSubC
 	if poolSize < 1 {
 		return nil, fmt.Errorf("pool size must be greater than 0")
 	}
+	if queueSize < 1 {
+		return nil, fmt.Errorf("queue size must be greater than 0")
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Subscriber{
 		client:               client,
 		poolSize:             poolSize,
+		queueSize:            queueSize,
 		logLevel:             logLevel,
 		reconnectDelay:       reconnectDelay,
 		maxReconnectAttempts: maxReconnectAttempts,
@@ -231,55 +260,91 @@ func (s *Subscriber) buildChannelName(channel string) string {
 	return channel
 }
 
-// Subscribe subscribes to one or more channels
-func (s *Subscriber) Subscribe(ctx context.Context, channels ...string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	// Apply namespace to channels
+// buildChannelNames applies the namespace to channels or patterns
+func (s *Subscriber) buildChannelNames(channels []string) []string {
 	namespacedChannels := make([]string, len(channels))
 	for i, channel := range channels {
 		namespacedChannels[i] = s.buildChannelName(channel)
 	}
+	return namespacedChannels
+}
 
-	s.channels = namespacedChannels
-	s.patterns = nil // Clear patterns when subscribing to channels
+// Subscribe adds one or more channels to the subscription, it can be called while the subscriber is running
+func (s *Subscriber) Subscribe(ctx context.Context, channels ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if s.sub != nil {
-		s.sub.Close()
+	namespacedChannels := s.buildChannelNames(channels)
+	if err := s.pubSub(ctx).Subscribe(ctx, namespacedChannels...); err != nil {
+		return fmt.Errorf("failed to subscribe to channels %v: %w", namespacedChannels, err)
 	}
-
-	s.sub = s.client.Subscribe(ctx, namespacedChannels...)
+	s.channels = appendUnique(s.channels, namespacedChannels...)
 	return nil
 }
 
-// PSubscribe subscribes to one or more patterns
+// PSubscribe adds one or more patterns to the subscription, it can be called while the subscriber is running
 func (s *Subscriber) PSubscribe(ctx context.Context, patterns ...string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Apply namespace to patterns
-	namespacedPatterns := make([]string, len(patterns))
-	for i, pattern := range patterns {
-		namespacedPatterns[i] = s.buildChannelName(pattern)
+	namespacedPatterns := s.buildChannelNames(patterns)
+	if err := s.pubSub(ctx).PSubscribe(ctx, namespacedPatterns...); err != nil {
+		return fmt.Errorf("failed to subscribe to patterns %v: %w", namespacedPatterns, err)
 	}
+	s.patterns = appendUnique(s.patterns, namespacedPatterns...)
+	return nil
+}
 
-	s.patterns = namespacedPatterns
-	s.channels = nil // Clear channels when subscribing to patterns
+// Unsubscribe removes one or more channels from the subscription
+func (s *Subscriber) Unsubscribe(ctx context.Context, channels ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	namespacedChannels := s.buildChannelNames(channels)
 	if s.sub != nil {
-		s.sub.Close()
+		if err := s.sub.Unsubscribe(ctx, namespacedChannels...); err != nil {
+			return fmt.Errorf("failed to unsubscribe from channels %v: %w", namespacedChannels, err)
+		}
 	}
+	s.channels = removeAll(s.channels, namespacedChannels...)
+	return nil
+}
 
-	s.sub = s.client.PSubscribe(ctx, namespacedPatterns...)
+// PUnsubscribe removes one or more patterns from the subscription
+func (s *Subscriber) PUnsubscribe(ctx context.Context, patterns ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	namespacedPatterns := s.buildChannelNames(patterns)
+	if s.sub != nil {
+		if err := s.sub.PUnsubscribe(ctx, namespacedPatterns...); err != nil {
+			return fmt.Errorf("failed to unsubscribe from patterns %v: %w", namespacedPatterns, err)
+		}
+	}
+	s.patterns = removeAll(s.patterns, namespacedPatterns...)
 	return nil
 }
 
+// pubSub returns the connection of the subscriber, creating it if needed. The caller must hold the lock.
+func (s *Subscriber) pubSub(ctx context.Context) *redis.PubSub {
+	if s.sub == nil {
+		s.sub = s.client.Subscribe(ctx)
+	}
+	return s.sub
+}
+
+// subscriptions returns the subscribed channels and patterns
+func (s *Subscriber) subscriptions() ([]string, []string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.channels...), append([]string(nil), s.patterns...)
+}
+
 // Start begins listening for messages and processing them concurrently.
-// It will spawn PoolSize number of workers that keep listening for messages
+// It will spawn PoolSize number of workers handling the messages received
 // until the provided context is canceled or Stop() is called.
 func (s *Subscriber) Start(ctx context.Context) {
-	if s.sub == nil {
+	if channels, patterns := s.subscriptions(); len(channels) == 0 && len(patterns) == 0 {
 		s.logf(ErrorLevel, "not subscribed to any channels or patterns")
 		return
 	}
@@ -300,69 +365,106 @@ func (s *Subscriber) Start(ctx context.Context) {
 		}
 	}()
 
-	var wg sync.WaitGroup
+	queues := make([]chan *redis.Message, s.poolSize)
+	for i := range queues {
+		queues[i] = make(chan *redis.Message, s.queueSize)
+	}
+	s.mu.Lock()
+	s.queues = queues
+	s.mu.Unlock()
 
-	for i := 0; i < s.poolSize; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			s.listenMessages(combinedCtx)
-		}()
+	for _, queue := range queues {
+		s.wg.Add(1)
+		go func(queue chan *redis.Message) {
+			defer s.wg.Done()
+			s.handleMessages(combinedCtx, queue)
+		}(queue)
 	}
 
-	wg.Wait()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.listenMessages(combinedCtx, queues)
+	}()
+
+	s.wg.Wait()
 }
 
-// listenMessages listens for messages from Redis pub/sub
-func (s *Subscriber) listenMessages(ctx context.Context) {
+// listenMessages receives the messages of the connection and dispatches them to the worker queues
+func (s *Subscriber) listenMessages(ctx context.Context, queues []chan *redis.Message) {
+	next := 0
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			if s.sub == nil {
-				s.logf(ErrorLevel, "subscriber is not initialized")
-				time.Sleep(s.reconnectDelay)
-				continue
-			}
+		s.mu.RLock()
+		sub := s.sub
+		s.mu.RUnlock()
 
-			ch := s.sub.Channel()
-			for msg := range ch {
-				go s.handleMessage(ctx, msg)
-			}
-
-			// If we get here, the channel was closed
-			// Check if context was cancelled before attempting reconnect
+		ch := sub.Channel()
+	receive:
+		for {
 			select {
 			case <-ctx.Done():
 				return
-			default:
+			case msg, ok := <-ch:
+				if !ok {
+					break receive
+				}
+				next = s.dispatch(queues, next, msg)
 			}
+		}
 
-			// Try to reconnect if not cancelled
-			if atomic.LoadInt32(&s.reconnectAttempts) < int32(s.maxReconnectAttempts) {
-				atomic.AddInt32(&s.reconnectAttempts, 1)
-				s.logf(ErrorLevel, "channel closed, attempting to reconnect (attempt %d/%d)",
-					atomic.LoadInt32(&s.reconnectAttempts), s.maxReconnectAttempts)
-
-				if err := s.reconnect(ctx); err != nil {
-					s.logf(ErrorLevel, "failed to reconnect: %v", err)
-
-					// Check again if context was cancelled
-					select {
-					case <-ctx.Done():
-						return
-					default:
-						time.Sleep(s.reconnectDelay)
-						continue
-					}
-				}
+		// If we get here, the channel was closed
+		// Check if context was cancelled before attempting reconnect
+		if ctx.Err() != nil {
+			return
+		}
 
-				atomic.StoreInt32(&s.reconnectAttempts, 0) // Reset on successful reconnect
-			} else {
+		// Try to reconnect if not cancelled, restoring every channel and pattern
+		for {
+			attempts := atomic.AddInt32(&s.reconnectAttempts, 1)
+			if int(attempts) > s.maxReconnectAttempts {
 				s.logf(ErrorLevel, "max reconnection attempts reached, stopping subscriber")
 				return
 			}
+			s.logf(ErrorLevel, "channel closed, attempting to reconnect (attempt %d/%d)", attempts, s.maxReconnectAttempts)
+
+			err := s.reconnect(ctx)
+			if err == nil {
+				break
+			}
+			s.logf(ErrorLevel, "failed to reconnect: %v", err)
+			if !sleepContext(ctx, s.reconnectDelay) {
+				return
+			}
+		}
+		atomic.StoreInt32(&s.reconnectAttempts, 0) // Reset on successful reconnect
+	}
+}
+
+// dispatch queues a message on the first worker with room, starting after the last one used.
+// The message is dropped when every queue is full. It returns the next worker to try.
+func (s *Subscriber) dispatch(queues []chan *redis.Message, next int, msg *redis.Message) int {
+	for i := 0; i < len(queues); i++ {
+		worker := (next + i) % len(queues)
+		select {
+		case queues[worker] <- msg:
+			return (worker + 1) % len(queues)
+		default:
+		}
+	}
+
+	atomic.AddInt64(&s.messagesDropped, 1)
+	s.logf(ErrorLevel, "handler queues are full, dropping message from channel %s", msg.Channel)
+	return next
+}
+
+// handleMessages processes the messages of a worker queue
+func (s *Subscriber) handleMessages(ctx context.Context, queue chan *redis.Message) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-queue:
+			s.handleMessage(ctx, msg)
 		}
 	}
 }
@@ -383,23 +485,33 @@ func (s *Subscriber) handleMessage(ctx context.Context, msg *redis.Message) {
 	atomic.AddInt64(&s.messagesProcessed, 1)
 }
 
-// reconnect attempts to reconnect to Redis pub/sub
+// reconnect replaces the connection with a new one subscribed to every channel and pattern
 func (s *Subscriber) reconnect(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if len(s.channels) == 0 && len(s.patterns) == 0 {
+		return fmt.Errorf("no channels or patterns to subscribe to")
+	}
+
 	if s.sub != nil {
 		s.sub.Close()
 	}
 
+	sub := s.client.Subscribe(ctx)
 	if len(s.channels) > 0 {
-		s.sub = s.client.Subscribe(ctx, s.channels...)
-	} else if len(s.patterns) > 0 {
-		s.sub = s.client.PSubscribe(ctx, s.patterns...)
-	} else {
-		return fmt.Errorf("no channels or patterns to subscribe to")
+		if err := sub.Subscribe(ctx, s.channels...); err != nil {
+			sub.Close()
+			return err
+		}
 	}
-
+	if len(s.patterns) > 0 {
+		if err := sub.PSubscribe(ctx, s.patterns...); err != nil {
+			sub.Close()
+			return err
+		}
+	}
+	s.sub = sub
 	return nil
 }
 
@@ -413,15 +525,14 @@ func (s *Subscriber) Stop() {
 }
 
 // Close closes the subscriber and releases resources.
-// It automatically calls Stop() to cancel any running goroutines before closing the connection.
-// It's recommended to call Stop() explicitly before Close() with a small delay to allow graceful shutdown.
+// It calls Stop() and waits for the running handlers before closing the connection.
 func (s *Subscriber) Close() error {
 	// Stop the subscriber first to cancel goroutines
 	s.Stop()
 
 	// Wait for goroutines to finish processing and exit cleanly
 	// This prevents "channel closed" errors during shutdown
-	time.Sleep(150 * time.Millisecond)
+	s.wg.Wait()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -434,15 +545,41 @@ func (s *Subscriber) Close() error {
 	return nil
 }
 
+// appendUnique appends the values missing from a slice
+func appendUnique(values []string, added ...string) []string {
+	for _, value := range added {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// removeAll returns a copy of a slice without the removed values
+func removeAll(values []string, removed ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !slices.Contains(removed, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 // logf logs messages based on the configured log level
 func (s *Subscriber) logf(level LogLevel, format string, v ...interface{}) {
-	if s.logLevel == Silent {
+	logAtLevel(s.logLevel, level, format, v...)
+}
+
+// logAtLevel prints a message of the given level if the configured log level allows it
+func logAtLevel(configured LogLevel, level LogLevel, format string, v ...interface{}) {
+	if configured == Silent {
 		return
 	}
-	if level == ErrorLevel && (s.logLevel == ErrorLevel || s.logLevel == InfoLevel) {
+	if level == ErrorLevel && (configured == ErrorLevel || configured == InfoLevel) {
 		fmt.Printf("[ERROR] "+format+"\n", v...)
 	}
-	if level == InfoLevel && s.logLevel == InfoLevel {
+	if level == InfoLevel && configured == InfoLevel {
 		fmt.Printf("[INFO] "+format+"\n", v...)
 	}
 }
@@ -465,8 +602,11 @@ func ParseLogLevel(level string) LogLevel {
 func (s *Subscriber) HealthCheck() SubscriberHealthCheck {
 	isRunning := atomic.LoadInt32(&s.isRunning) == 1
 	messagesProcessed := atomic.LoadInt64(&s.messagesProcessed)
+	messagesDropped := atomic.LoadInt64(&s.messagesDropped)
 	reconnectAttempts := atomic.LoadInt32(&s.reconnectAttempts)
 
+	channels, patterns := s.subscriptions()
+
 	// Test Redis connectivity
 	redisAvailable := s.testRedisConnectivity()
 
@@ -484,15 +624,18 @@ func (s *Subscriber) HealthCheck() SubscriberHealthCheck {
 
 	details := map[string]string{
 		"pool_size":              strconv.Itoa(s.poolSize),
+		"queue_size":             strconv.Itoa(s.queueSize),
+		"queued_messages":        strconv.Itoa(s.queuedMessages()),
 		"log_level":              s.getLogLevelString(),
 		"reconnect_delay":        s.reconnectDelay.String(),
 		"max_reconnect_attempts": strconv.Itoa(s.maxReconnectAttempts),
 		"is_running":             strconv.FormatBool(isRunning),
 		"messages_processed":     strconv.FormatInt(messagesProcessed, 10),
+		"messages_dropped":       strconv.FormatInt(messagesDropped, 10),
 		"reconnect_attempts":     strconv.FormatInt(int64(reconnectAttempts), 10),
 		"redis_available":        strconv.FormatBool(redisAvailable),
-		"channels":               fmt.Sprintf("%v", s.channels),
-		"patterns":               fmt.Sprintf("%v", s.patterns),
+		"channels":               fmt.Sprintf("%v", channels),
+		"patterns":               fmt.Sprintf("%v", patterns),
 	}
 
 	return SubscriberHealthCheck{
@@ -501,6 +644,18 @@ func (s *Subscriber) HealthCheck() SubscriberHealthCheck {
 	}
 }
 
+// queuedMessages returns the number of messages waiting in the worker queues
+func (s *Subscriber) queuedMessages() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queued := 0
+	for _, queue := range s.queues {
+		queued += len(queue)
+	}
+	return queued
+}
+
 // testRedisConnectivity tests if Redis is accessible
 func (s *Subscriber) testRedisConnectivity() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -512,7 +667,12 @@ func (s *Subscriber) testRedisConnectivity() bool {
 
 // getLogLevelString returns the string representation of the log level
 func (s *Subscriber) getLogLevelString() string {
-	switch s.logLevel {
+	return logLevelString(s.logLevel)
+}
+
+// logLevelString returns the string representation of a log level
+func logLevelString(level LogLevel) string {
+	switch level {
 	case Silent:
 		return "silent"
 	case ErrorLevel:
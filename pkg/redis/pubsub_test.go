@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// pubSubRecorder records the messages handled by a subscriber by channel
+type pubSubRecorder struct {
+	mu       sync.Mutex
+	messages map[string][]string
+}
+
+func (r *pubSubRecorder) HandleMessage(ctx context.Context, channel string, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages[channel] = append(r.messages[channel], message)
+	return nil
+}
+
+func (r *pubSubRecorder) received(channel string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.messages[channel]...)
+}
+
+// testPubSubConfig returns a configuration reconnecting every few milliseconds
+func testPubSubConfig() *PubSubConfig {
+	return NewPubSubConfig().
+		WithChannelNamespace("test").
+		WithReconnectDelay(10 * time.Millisecond)
+}
+
+// waitForSubscriptions waits until Redis has the given number of subscribers to the channels and patterns
+func waitForSubscriptions(t *testing.T, server *miniredis.Miniredis, channels int, channel string, patterns int) {
+	t.Helper()
+	waitFor(t, time.Second, func() bool {
+		return server.PubSubNumSub(channel)[channel] == channels && server.PubSubNumPat() == patterns
+	})
+}
+
+// publishAndWait publishes a message and waits until the subscriber handled it
+func publishAndWait(t *testing.T, publisher *Publisher, recorder *pubSubRecorder, channel, fullChannel, message string) {
+	t.Helper()
+	if err := publisher.Publish(context.Background(), channel, message); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	waitFor(t, time.Second, func() bool {
+		for _, received := range recorder.received(fullChannel) {
+			if received == message {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func TestSubscriberDynamicSubscriptions(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	recorder := &pubSubRecorder{messages: make(map[string][]string)}
+	publisher := NewPublisher(client.GetClient(), testPubSubConfig())
+
+	subscriber, err := NewSubscriber(client.GetClient(), recorder, testPubSubConfig())
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+	t.Cleanup(func() { subscriber.Close() })
+	if err := subscriber.Subscribe(ctx, "orders"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	go subscriber.Start(ctx)
+
+	waitForSubscriptions(t, server, 1, "test::orders", 0)
+	publishAndWait(t, publisher, recorder, "orders", "test::orders", "order-1")
+
+	// Channels and patterns are added while the subscriber is running
+	if err := subscriber.Subscribe(ctx, "payments"); err != nil {
+		t.Fatalf("Subscribe() while running error = %v", err)
+	}
+	if err := subscriber.PSubscribe(ctx, "events:*"); err != nil {
+		t.Fatalf("PSubscribe() while running error = %v", err)
+	}
+	waitForSubscriptions(t, server, 1, "test::payments", 1)
+	publishAndWait(t, publisher, recorder, "payments", "test::payments", "payment-1")
+	publishAndWait(t, publisher, recorder, "events:created", "test::events:created", "event-1")
+
+	// An unsubscribed channel receives nothing, while the others still do
+	if err := subscriber.Unsubscribe(ctx, "orders"); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	if err := subscriber.PUnsubscribe(ctx, "events:*"); err != nil {
+		t.Fatalf("PUnsubscribe() error = %v", err)
+	}
+	waitForSubscriptions(t, server, 0, "test::orders", 0)
+	if err := publisher.Publish(ctx, "orders", "order-2"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := publisher.Publish(ctx, "events:updated", "event-2"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	publishAndWait(t, publisher, recorder, "payments", "test::payments", "payment-2")
+	if got := recorder.received("test::orders"); len(got) != 1 {
+		t.Errorf("orders messages = %v, want only the message sent before Unsubscribe()", got)
+	}
+	if got := recorder.received("test::events:updated"); len(got) != 0 {
+		t.Errorf("events:updated messages = %v, want none after PUnsubscribe()", got)
+	}
+
+	health := subscriber.HealthCheck()
+	if health.Status != StatusUp || health.Details["channels"] != "[test::payments]" || health.Details["patterns"] != "[]" {
+		t.Errorf("HealthCheck() = %+v, want UP with the payments channel only", health)
+	}
+}
+
+func TestSubscriberResubscribesOnReconnect(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	recorder := &pubSubRecorder{messages: make(map[string][]string)}
+	publisher := NewPublisher(client.GetClient(), testPubSubConfig())
+
+	subscriber, err := NewSubscriber(client.GetClient(), recorder, testPubSubConfig())
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+	t.Cleanup(func() { subscriber.Close() })
+	if err := subscriber.Subscribe(ctx, "orders"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := subscriber.PSubscribe(ctx, "events:*"); err != nil {
+		t.Fatalf("PSubscribe() error = %v", err)
+	}
+	go subscriber.Start(ctx)
+	waitForSubscriptions(t, server, 1, "test::orders", 1)
+
+	// The connection is lost, the subscriber opens a new one with every channel and pattern
+	subscriber.mu.RLock()
+	lost := subscriber.sub
+	subscriber.mu.RUnlock()
+	if err := lost.Close(); err != nil {
+		t.Fatalf("Close() of the connection error = %v", err)
+	}
+	waitFor(t, time.Second, func() bool {
+		subscriber.mu.RLock()
+		defer subscriber.mu.RUnlock()
+		return subscriber.sub != lost
+	})
+	waitForSubscriptions(t, server, 1, "test::orders", 1)
+
+	publishAndWait(t, publisher, recorder, "orders", "test::orders", "order-1")
+	publishAndWait(t, publisher, recorder, "events:created", "test::events:created", "event-1")
+	if health := subscriber.HealthCheck(); health.Status != StatusUp || health.Details["reconnect_attempts"] != "0" {
+		t.Errorf("HealthCheck() after reconnecting = %+v, want UP with the attempts reset", health)
+	}
+}
@@ -37,6 +37,15 @@ func (rlr *RateLimiterRegistry) UnregisterRateLimiter(cacheName string) {
 	delete(rlr.limiters, cacheName)
 }
 
+// GetRateLimiter returns the registered rate limiter with the cache name
+func (rlr *RateLimiterRegistry) GetRateLimiter(cacheName string) (*RateLimiter, bool) {
+	rlr.mu.RLock()
+	defer rlr.mu.RUnlock()
+
+	limiter, ok := rlr.limiters[cacheName]
+	return limiter, ok
+}
+
 // GetRateLimiterMetrics returns the metrics of all registered rate limiters
 func (rlr *RateLimiterRegistry) GetRateLimiterMetrics(ctx context.Context) map[string]map[string]string {
 	rlr.mu.RLock()
@@ -76,6 +85,11 @@ type RateLimiterOptions struct {
 	CacheName string
 	// TransactionTTL is the maximum time a transaction can be active before auto-release
 	TransactionTTL time.Duration
+	// Algorithm is the algorithm enforcing the per-window limits
+	Algorithm RateLimitAlgorithm
+	// Burst is the number of transactions admitted at once by the token bucket and GCRA
+	// algorithms, 0 uses the limit of each window
+	Burst int
 }
 
 // NewRateLimiterOptions creates a new rate limiter options with default values
@@ -92,6 +106,8 @@ func NewRateLimiterOptions() *RateLimiterOptions {
 		Namespace:                "",
 		CacheName:                "",
 		TransactionTTL:           5 * time.Minute,
+		Algorithm:                AlgorithmSlidingWindow,
+		Burst:                    0, // Limit of each window by default
 	}
 }
 
@@ -185,12 +201,33 @@ func (rlo *RateLimiterOptions) WithTransactionTTL(ttl time.Duration) *RateLimite
 	return rlo
 }
 
+// WithAlgorithm sets the algorithm enforcing the per-window limits
+func (rlo *RateLimiterOptions) WithAlgorithm(algorithm RateLimitAlgorithm) *RateLimiterOptions {
+	if _, ok := rateLimitAlgorithmScripts[algorithm]; !ok {
+		panic(fmt.Sprintf("invalid rate limit algorithm: %s", algorithm))
+	}
+	rlo.Algorithm = algorithm
+	return rlo
+}
+
+// WithBurst sets the number of transactions admitted at once by the token bucket and GCRA algorithms
+func (rlo *RateLimiterOptions) WithBurst(burst int) *RateLimiterOptions {
+	if burst < 0 {
+		panic(fmt.Sprintf("invalid burst: %d, must be non-negative", burst))
+	}
+	rlo.Burst = burst
+	return rlo
+}
+
 // Validate validates the rate limiter options
 func (rlo *RateLimiterOptions) Validate() error {
 	if rlo.MaxActiveTransactions == 0 && rlo.MaxTransactionsPerSecond == 0 && rlo.MaxTransactionsPerMinute == 0 &&
 		rlo.MaxTransactionsPerHour == 0 && rlo.MaxTransactionsPerDay == 0 {
 		return fmt.Errorf("at least one limit must be configured (MaxActiveTransactions, MaxTransactionsPerSecond, MaxTransactionsPerMinute, MaxTransactionsPerHour, or MaxTransactionsPerDay)")
 	}
+	if _, ok := rateLimitAlgorithmScripts[rlo.Algorithm]; rlo.Algorithm != "" && !ok {
+		return fmt.Errorf("invalid rate limit algorithm: %s", rlo.Algorithm)
+	}
 	return nil
 }
 
@@ -230,10 +267,10 @@ func NewRateLimiter(client *Client, key string, opts *RateLimiterOptions) (*Rate
 
 	// Build key names
 	limiter.activeKeyName = limiter.buildKey("active")
-	limiter.tpsKeyName = limiter.buildKey("tps")
-	limiter.tpmKeyName = limiter.buildKey("tpm")
-	limiter.tphKeyName = limiter.buildKey("tph")
-	limiter.tpdKeyName = limiter.buildKey("tpd")
+	limiter.tpsKeyName = limiter.buildKey(limiter.windowSuffix("tps"))
+	limiter.tpmKeyName = limiter.buildKey(limiter.windowSuffix("tpm"))
+	limiter.tphKeyName = limiter.buildKey(limiter.windowSuffix("tph"))
+	limiter.tpdKeyName = limiter.buildKey(limiter.windowSuffix("tpd"))
 
 	// Register rate limiter if it has a cache name
 	if opts.CacheName != "" {
@@ -243,34 +280,32 @@ func NewRateLimiter(client *Client, key string, opts *RateLimiterOptions) (*Rate
 	return limiter, nil
 }
 
-// buildKey constructs the full key using Namespace::key::suffix format
+// buildKey constructs the full key using {Namespace::key}::suffix format
 func (rl *RateLimiter) buildKey(suffix string) string {
-	if rl.opts.Namespace != "" {
-		return rl.opts.Namespace + "::" + rl.key + "::" + suffix
-	}
-	return rl.key + "::" + suffix
+	return rl.buildKeyWithSuffix(suffix, "")
 }
 
-// buildKeyWithSuffix constructs the full key with an optional additional key suffix
+// buildKeyWithSuffix constructs the full key with an optional additional key suffix.
+// The keys of a limiter share the cluster slot of their base key, so one script updates them all.
 func (rl *RateLimiter) buildKeyWithSuffix(suffix string, additionalKey string) string {
 	baseKey := rl.key
 	if additionalKey != "" {
 		baseKey = rl.key + "::" + additionalKey
 	}
 	if rl.opts.Namespace != "" {
-		return rl.opts.Namespace + "::" + baseKey + "::" + suffix
+		baseKey = rl.opts.Namespace + "::" + baseKey
 	}
-	return baseKey + "::" + suffix
+	return slotKey(baseKey, suffix)
 }
 
 // getKeyNames returns the key names for the given additional key
 func (rl *RateLimiter) getKeyNames(additionalKey string) (activeKey, tpsKey, tpmKey, tphKey, tpdKey string) {
 	if additionalKey != "" {
 		activeKey = rl.buildKeyWithSuffix("active", additionalKey)
-		tpsKey = rl.buildKeyWithSuffix("tps", additionalKey)
-		tpmKey = rl.buildKeyWithSuffix("tpm", additionalKey)
-		tphKey = rl.buildKeyWithSuffix("tph", additionalKey)
-		tpdKey = rl.buildKeyWithSuffix("tpd", additionalKey)
+		tpsKey = rl.buildKeyWithSuffix(rl.windowSuffix("tps"), additionalKey)
+		tpmKey = rl.buildKeyWithSuffix(rl.windowSuffix("tpm"), additionalKey)
+		tphKey = rl.buildKeyWithSuffix(rl.windowSuffix("tph"), additionalKey)
+		tpdKey = rl.buildKeyWithSuffix(rl.windowSuffix("tpd"), additionalKey)
 	} else {
 		activeKey = rl.activeKeyName
 		tpsKey = rl.tpsKeyName
@@ -289,6 +324,17 @@ func (rl *RateLimiter) Acquire(ctx context.Context) (string, error) {
 // AcquireWithKey attempts to acquire a transaction slot with an optional additional key
 // The additional key will be concatenated to the base key for rate limiting purposes
 func (rl *RateLimiter) AcquireWithKey(ctx context.Context, additionalKey string) (string, error) {
+	result, err := rl.AcquireWithResult(ctx, additionalKey)
+	if err != nil {
+		return "", err
+	}
+	return result.TransactionID, nil
+}
+
+// AcquireWithResult attempts to acquire a transaction slot with an optional additional key and
+// describes the most restrictive limit afterwards. When a limit is reached it returns both the
+// result and a RateLimitExceededError, on other errors the result is nil.
+func (rl *RateLimiter) AcquireWithResult(ctx context.Context, additionalKey string) (*RateLimitResult, error) {
 	if rl.opts.WaitOnLimit {
 		return rl.acquireWithWait(ctx, additionalKey)
 	}
@@ -296,199 +342,160 @@ func (rl *RateLimiter) AcquireWithKey(ctx context.Context, additionalKey string)
 }
 
 // acquireImmediate attempts to acquire immediately or returns error
-func (rl *RateLimiter) acquireImmediate(ctx context.Context, additionalKey string) (string, error) {
-	// Check all limits using Lua script for atomicity
-	script := rl.buildAcquireScript()
-
+func (rl *RateLimiter) acquireImmediate(ctx context.Context, additionalKey string) (*RateLimitResult, error) {
 	// Get key names (dynamic if additional key is provided)
 	activeKey, tpsKey, tpmKey, tphKey, tpdKey := rl.getKeyNames(additionalKey)
 
-	now := time.Now()
-	transactionID := strconv.FormatInt(now.UnixNano(), 10)
+	transactionID := strconv.FormatInt(time.Now().UnixNano(), 10)
 
-	result, err := rl.client.GetClient().Eval(ctx, script, []string{
+	// Check all limits using the Lua script of the algorithm for atomicity
+	values, err := rl.scripts().acquire.Run(ctx, rl.client.GetClient(), []string{
 		activeKey,
 		tpsKey,
 		tpmKey,
 		tphKey,
 		tpdKey,
-	},
-		rl.opts.MaxActiveTransactions,
-		rl.opts.MaxTransactionsPerSecond,
-		rl.opts.MaxTransactionsPerMinute,
-		rl.opts.MaxTransactionsPerHour,
-		rl.opts.MaxTransactionsPerDay,
-		transactionID,
-		now.Unix(),
-		now.UnixNano(),
-		int(rl.opts.TransactionTTL.Seconds()),
-	).Result()
+	}, rl.scriptArgs(transactionID)...).Int64Slice()
 
 	if err != nil {
-		return "", fmt.Errorf("failed to acquire rate limiter: %w", err)
+		return nil, fmt.Errorf("failed to acquire rate limiter: %w", err)
+	}
+	if len(values) < 5 {
+		return nil, fmt.Errorf("unexpected rate limiter result: %v", values)
 	}
 
-	// Result: 1 = success, 0 = active limit, -1 = TPS limit, -2 = TPM limit, -3 = TPH limit, -4 = TPD limit
-	resultCode := result.(int64)
+	// Result: {code, retry after ms, remaining, reset ms, limiting code}
+	// Code: 1 = success, 0 = active limit, -1 = TPS limit, -2 = TPM limit, -3 = TPH limit, -4 = TPD limit
+	resultCode := values[0]
+	result := rl.newResult(values[4], values[2], time.Duration(values[3])*time.Millisecond)
 	if resultCode == 1 {
 		rl.transactionID = transactionID
-		return transactionID, nil
+		result.TransactionID = transactionID
+		return result, nil
 	}
 
-	switch resultCode {
-	case 0:
-		return "", fmt.Errorf("active transactions limit reached (%d/%d)", rl.opts.MaxActiveTransactions, rl.opts.MaxActiveTransactions)
-	case -1:
-		return "", fmt.Errorf("transactions per second limit reached (%d TPS)", rl.opts.MaxTransactionsPerSecond)
-	case -2:
-		return "", fmt.Errorf("transactions per minute limit reached (%d TPM)", rl.opts.MaxTransactionsPerMinute)
-	case -3:
-		return "", fmt.Errorf("transactions per hour limit reached (%d TPH)", rl.opts.MaxTransactionsPerHour)
-	case -4:
-		return "", fmt.Errorf("transactions per day limit reached (%d TPD)", rl.opts.MaxTransactionsPerDay)
-	default:
-		return "", fmt.Errorf("unknown error code: %d", resultCode)
+	result.RetryAfter = time.Duration(values[1]) * time.Millisecond
+	return result, rl.limitError(resultCode, result.RetryAfter)
+}
+
+// newResult describes the limit of a result code
+func (rl *RateLimiter) newResult(limitingCode, remaining int64, resetAfter time.Duration) *RateLimitResult {
+	result := &RateLimitResult{
+		Limit:      "active",
+		Quota:      rl.opts.MaxActiveTransactions,
+		Burst:      rl.opts.MaxActiveTransactions,
+		Remaining:  int(max(remaining, 0)),
+		ResetAfter: resetAfter,
+	}
+	if index := int(-limitingCode) - 1; index >= 0 && index < len(rateLimitWindows) {
+		window := rateLimitWindows[index]
+		result.Limit = window.name
+		result.Quota = window.limit(rl.opts)
+		result.Burst = rl.windowCapacity(result.Quota)
+		result.Window = window.duration
+	}
+	return result
+}
+
+// limitError builds the error of a script result code
+func (rl *RateLimiter) limitError(resultCode int64, retryAfter time.Duration) error {
+	if resultCode == 0 {
+		return &RateLimitExceededError{
+			Limit:   "active",
+			message: fmt.Sprintf("active transactions limit reached (%d/%d)", rl.opts.MaxActiveTransactions, rl.opts.MaxActiveTransactions),
+		}
+	}
+
+	index := int(-resultCode) - 1
+	if index < 0 || index >= len(rateLimitWindows) {
+		return fmt.Errorf("unknown error code: %d", resultCode)
+	}
+	window := rateLimitWindows[index]
+	return &RateLimitExceededError{
+		Limit:      window.name,
+		RetryAfter: retryAfter,
+		message:    fmt.Sprintf(window.message, window.limit(rl.opts)),
 	}
 }
 
 // acquireWithWait attempts to acquire with retry/wait logic
-func (rl *RateLimiter) acquireWithWait(ctx context.Context, additionalKey string) (string, error) {
+func (rl *RateLimiter) acquireWithWait(ctx context.Context, additionalKey string) (*RateLimitResult, error) {
 	deadline := time.Now().Add(rl.opts.WaitTimeout)
 
 	for {
-		transactionID, err := rl.acquireImmediate(ctx, additionalKey)
+		result, err := rl.acquireImmediate(ctx, additionalKey)
 		if err == nil {
-			return transactionID, nil
+			return result, nil
 		}
 
 		// Check if we've exceeded the timeout
 		if time.Now().After(deadline) {
-			return "", fmt.Errorf("timeout waiting for rate limiter: %w", err)
+			return result, fmt.Errorf("timeout waiting for rate limiter: %w", err)
+		}
+
+		// Wait until a transaction can be admitted, without going past the deadline
+		delay := rl.opts.RetryDelay
+		if retryAfter, ok := RetryAfter(err); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+		if remaining := time.Until(deadline); delay > remaining {
+			delay = remaining
 		}
 
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(rl.opts.RetryDelay):
+			return nil, ctx.Err()
+		case <-time.After(delay):
 			// Continue retry
 		}
 	}
 }
 
-// buildAcquireScript builds the Lua script for atomic acquire operation
-func (rl *RateLimiter) buildAcquireScript() string {
-	return `
-		local active_key = KEYS[1]
-		local tps_key = KEYS[2]
-		local tpm_key = KEYS[3]
-		local tph_key = KEYS[4]
-		local tpd_key = KEYS[5]
-		
-		local max_active = tonumber(ARGV[1])
-		local max_tps = tonumber(ARGV[2])
-		local max_tpm = tonumber(ARGV[3])
-		local max_tph = tonumber(ARGV[4])
-		local max_tpd = tonumber(ARGV[5])
-		local transaction_id = ARGV[6]
-		local now_seconds = tonumber(ARGV[7])
-		local now_nanos = tonumber(ARGV[8])
-		local transaction_ttl = tonumber(ARGV[9])
-		
-		-- Check active transactions limit
-		if max_active > 0 then
-			local active_count = tonumber(redis.call("GET", active_key)) or 0
-			if active_count >= max_active then
-				return 0
-			end
-		end
-		
-		-- Check TPS limit (sliding window of 1 second)
-		if max_tps > 0 then
-			-- Remove old entries (older than 1 second)
-			local tps_cutoff_time = now_nanos - (1 * 1000000000)
-			redis.call("ZREMRANGEBYSCORE", tps_key, "-inf", tps_cutoff_time)
-			
-			-- Count entries in the last second
-			local tps_count = redis.call("ZCOUNT", tps_key, tps_cutoff_time, "+inf")
-			if tps_count >= max_tps then
-				return -1
-			end
-		end
-		
-		-- Check TPM limit (sliding window of 60 seconds)
-		if max_tpm > 0 then
-			-- Remove old entries (older than 60 seconds)
-			local tpm_cutoff_time = now_nanos - (60 * 1000000000)
-			redis.call("ZREMRANGEBYSCORE", tpm_key, "-inf", tpm_cutoff_time)
-			
-			-- Count entries in the last minute
-			local tpm_count = redis.call("ZCOUNT", tpm_key, tpm_cutoff_time, "+inf")
-			if tpm_count >= max_tpm then
-				return -2
-			end
-		end
-		
-		-- Check TPH limit (sliding window of 3600 seconds = 1 hour)
-		if max_tph > 0 then
-			-- Remove old entries (older than 3600 seconds)
-			local tph_cutoff_time = now_nanos - (3600 * 1000000000)
-			redis.call("ZREMRANGEBYSCORE", tph_key, "-inf", tph_cutoff_time)
-			
-			-- Count entries in the last hour
-			local tph_count = redis.call("ZCOUNT", tph_key, tph_cutoff_time, "+inf")
-			if tph_count >= max_tph then
-				return -3
-			end
-		end
-		
-		-- Check TPD limit (sliding window of 86400 seconds = 1 day)
-		if max_tpd > 0 then
-			-- Remove old entries (older than 86400 seconds)
-			local tpd_cutoff_time = now_nanos - (86400 * 1000000000)
-			redis.call("ZREMRANGEBYSCORE", tpd_key, "-inf", tpd_cutoff_time)
-			
-			-- Count entries in the last day
-			local tpd_count = redis.call("ZCOUNT", tpd_key, tpd_cutoff_time, "+inf")
-			if tpd_count >= max_tpd then
-				return -4
-			end
-		end
-		
-		-- All checks passed, acquire the transaction
-		
-		-- Increment active transactions
-		if max_active > 0 then
-			redis.call("INCR", active_key)
-			redis.call("EXPIRE", active_key, transaction_ttl * 2)
-		end
-		
-		-- Add to TPS sorted set (sliding window)
-		if max_tps > 0 then
-			redis.call("ZADD", tps_key, now_nanos, transaction_id .. ":tps")
-			redis.call("EXPIRE", tps_key, 2)
-		end
-		
-		-- Add to TPM sorted set (sliding window)
-		if max_tpm > 0 then
-			redis.call("ZADD", tpm_key, now_nanos, transaction_id)
-			redis.call("EXPIRE", tpm_key, 60)
-		end
-		
-		-- Add to TPH sorted set (sliding window)
-		if max_tph > 0 then
-			redis.call("ZADD", tph_key, now_nanos, transaction_id)
-			redis.call("EXPIRE", tph_key, 3600)
-		end
-		
-		-- Add to TPD sorted set (sliding window)
-		if max_tpd > 0 then
-			redis.call("ZADD", tpd_key, now_nanos, transaction_id)
-			redis.call("EXPIRE", tpd_key, 86400)
-		end
-		
-		return 1
-	`
+// algorithm returns the configured algorithm, the sliding window when none is set
+func (rl *RateLimiter) algorithm() RateLimitAlgorithm {
+	if rl.opts.Algorithm == "" {
+		return AlgorithmSlidingWindow
+	}
+	return rl.opts.Algorithm
+}
+
+// scripts returns the Lua scripts of the configured algorithm
+func (rl *RateLimiter) scripts() rateLimitScripts {
+	return rateLimitAlgorithmScripts[rl.algorithm()]
+}
+
+// scriptArgs returns the arguments of the algorithm scripts
+func (rl *RateLimiter) scriptArgs(transactionID string) []interface{} {
+	args := []interface{}{
+		rl.opts.MaxActiveTransactions,
+		transactionID,
+		int(rl.opts.TransactionTTL.Seconds()) * 2,
+	}
+	for _, window := range rateLimitWindows {
+		args = append(args, window.limit(rl.opts), window.duration.Milliseconds(), rl.opts.Burst)
+	}
+	return args
+}
+
+// windowCapacity returns the number of transactions a window admits at once
+func (rl *RateLimiter) windowCapacity(limit int) int {
+	switch rl.algorithm() {
+	case AlgorithmTokenBucket, AlgorithmGCRA:
+		if rl.opts.Burst > 0 {
+			return rl.opts.Burst
+		}
+	}
+	return limit
+}
+
+// windowSuffix returns the key suffix of a window. Algorithms other than the sliding window
+// store a different data type, so their keys are suffixed with the algorithm name.
+func (rl *RateLimiter) windowSuffix(window string) string {
+	if algorithm := rl.algorithm(); algorithm != AlgorithmSlidingWindow {
+		return window + "::" + string(algorithm)
+	}
+	return window
 }
 
 // Release releases a transaction slot
@@ -530,8 +537,7 @@ type RateLimiterMetrics map[string]string
 // GetMetrics returns the current metrics of the rate limiter
 func (rl *RateLimiter) GetMetrics(ctx context.Context) (RateLimiterMetrics, error) {
 	metrics := make(RateLimiterMetrics)
-
-	now := time.Now()
+	metrics["algorithm"] = string(rl.algorithm())
 
 	// Get active transactions count
 	if rl.opts.MaxActiveTransactions > 0 {
@@ -545,72 +551,31 @@ func (rl *RateLimiter) GetMetrics(ctx context.Context) (RateLimiterMetrics, erro
 		metrics["active_utilization"] = fmt.Sprintf("%.1f%%", utilization)
 	}
 
-	// Get TPS count (sliding window of 1 second)
-	if rl.opts.MaxTransactionsPerSecond > 0 {
-		// Count entries in the last second
-		tpsCutoffTime := now.Add(-1 * time.Second).UnixNano()
-
-		tpsCount, err := rl.client.GetClient().ZCount(ctx, rl.tpsKeyName,
-			strconv.FormatInt(tpsCutoffTime, 10),
-			"+inf").Result()
-		if err != nil {
-			tpsCount = 0
-		}
-		metrics["transactions_per_second"] = strconv.FormatInt(tpsCount, 10)
-		metrics["max_transactions_per_second"] = strconv.Itoa(rl.opts.MaxTransactionsPerSecond)
-		utilization := float64(tpsCount) / float64(rl.opts.MaxTransactionsPerSecond) * 100
-		metrics["tps_utilization"] = fmt.Sprintf("%.1f%%", utilization)
+	// Get the transactions counting against each window capacity, in a single script
+	used, err := rl.scripts().metrics.Run(ctx, rl.client.GetClient(), []string{
+		rl.activeKeyName,
+		rl.tpsKeyName,
+		rl.tpmKeyName,
+		rl.tphKeyName,
+		rl.tpdKeyName,
+	}, rl.scriptArgs("")...).Int64Slice()
+	if err != nil || len(used) < len(rateLimitWindows) {
+		used = make([]int64, len(rateLimitWindows))
 	}
 
-	// Get TPM count (sliding window of 60 seconds)
-	if rl.opts.MaxTransactionsPerMinute > 0 {
-		// Count entries in the last minute
-		tpmCutoffTime := now.Add(-60 * time.Second).UnixNano()
-
-		tpmCount, err := rl.client.GetClient().ZCount(ctx, rl.tpmKeyName,
-			strconv.FormatInt(tpmCutoffTime, 10),
-			"+inf").Result()
-		if err != nil {
-			tpmCount = 0
+	for i, window := range rateLimitWindows {
+		limit := window.limit(rl.opts)
+		if limit == 0 {
+			continue
 		}
-		metrics["transactions_per_minute"] = strconv.FormatInt(tpmCount, 10)
-		metrics["max_transactions_per_minute"] = strconv.Itoa(rl.opts.MaxTransactionsPerMinute)
-		utilization := float64(tpmCount) / float64(rl.opts.MaxTransactionsPerMinute) * 100
-		metrics["tpm_utilization"] = fmt.Sprintf("%.1f%%", utilization)
-	}
-
-	// Get TPH count (sliding window of 3600 seconds = 1 hour)
-	if rl.opts.MaxTransactionsPerHour > 0 {
-		// Count entries in the last hour
-		tphCutoffTime := now.Add(-3600 * time.Second).UnixNano()
-
-		tphCount, err := rl.client.GetClient().ZCount(ctx, rl.tphKeyName,
-			strconv.FormatInt(tphCutoffTime, 10),
-			"+inf").Result()
-		if err != nil {
-			tphCount = 0
-		}
-		metrics["transactions_per_hour"] = strconv.FormatInt(tphCount, 10)
-		metrics["max_transactions_per_hour"] = strconv.Itoa(rl.opts.MaxTransactionsPerHour)
-		utilization := float64(tphCount) / float64(rl.opts.MaxTransactionsPerHour) * 100
-		metrics["tph_utilization"] = fmt.Sprintf("%.1f%%", utilization)
-	}
-
-	// Get TPD count (sliding window of 86400 seconds = 1 day)
-	if rl.opts.MaxTransactionsPerDay > 0 {
-		// Count entries in the last day
-		tpdCutoffTime := now.Add(-86400 * time.Second).UnixNano()
-
-		tpdCount, err := rl.client.GetClient().ZCount(ctx, rl.tpdKeyName,
-			strconv.FormatInt(tpdCutoffTime, 10),
-			"+inf").Result()
-		if err != nil {
-			tpdCount = 0
+		metrics[window.metric] = strconv.FormatInt(used[i], 10)
+		metrics["max_"+window.metric] = strconv.Itoa(limit)
+		capacity := rl.windowCapacity(limit)
+		if capacity != limit {
+			metrics[window.name+"_burst"] = strconv.Itoa(capacity)
 		}
-		metrics["transactions_per_day"] = strconv.FormatInt(tpdCount, 10)
-		metrics["max_transactions_per_day"] = strconv.Itoa(rl.opts.MaxTransactionsPerDay)
-		utilization := float64(tpdCount) / float64(rl.opts.MaxTransactionsPerDay) * 100
-		metrics["tpd_utilization"] = fmt.Sprintf("%.1f%%", utilization)
+		utilization := float64(used[i]) / float64(capacity) * 100
+		metrics[window.name+"_utilization"] = fmt.Sprintf("%.1f%%", utilization)
 	}
 
 	return metrics, nil
@@ -639,6 +604,26 @@ func (rl *RateLimiter) Cleanup(ctx context.Context) error {
 	return err
 }
 
+// Reset deletes the counters of the rate limiter, including those of every additional key,
+// so all transactions are allowed again. The limiter stays registered. It returns the number of deleted keys.
+func (rl *RateLimiter) Reset(ctx context.Context) (int64, error) {
+	var deleted int64
+	for _, pattern := range []string{rl.buildKeyWithSuffix("*", ""), rl.buildKeyWithSuffix("*", "*")} {
+		keys, err := ScanKeys(ctx, rl.client, pattern, 100)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to reset rate limiter %s: %w", rl.key, err)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if err := rl.client.Delete(ctx, keys...); err != nil {
+			return deleted, fmt.Errorf("failed to reset rate limiter %s: %w", rl.key, err)
+		}
+		deleted += int64(len(keys))
+	}
+	return deleted, nil
+}
+
 // GetRateLimiterMetrics returns the metrics of all registered rate limiters for health check
 func GetRateLimiterMetrics(ctx context.Context) map[string]map[string]string {
 	return rateLimiterRegistry.GetRateLimiterMetrics(ctx)
@@ -0,0 +1,303 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RateLimitAlgorithm selects how RateLimiter enforces the per-window limits
+type RateLimitAlgorithm string
+
+const (
+	// AlgorithmSlidingWindow keeps a sorted set entry per transaction, it is exact but its memory grows with the limit
+	AlgorithmSlidingWindow RateLimitAlgorithm = "sliding-window"
+	// AlgorithmTokenBucket refills each window bucket continuously and admits bursts up to Burst
+	AlgorithmTokenBucket RateLimitAlgorithm = "token-bucket"
+	// AlgorithmGCRA is the generic cell rate algorithm, it spaces transactions evenly and admits bursts up to Burst
+	AlgorithmGCRA RateLimitAlgorithm = "gcra"
+	// AlgorithmFixedWindow counts transactions in windows starting at the first transaction
+	AlgorithmFixedWindow RateLimitAlgorithm = "fixed-window"
+)
+
+// ParseRateLimitAlgorithm converts a configuration value into a RateLimitAlgorithm
+func ParseRateLimitAlgorithm(value string) (RateLimitAlgorithm, error) {
+	switch algorithm := RateLimitAlgorithm(strings.ToLower(strings.TrimSpace(value))); algorithm {
+	case AlgorithmSlidingWindow, AlgorithmTokenBucket, AlgorithmGCRA, AlgorithmFixedWindow:
+		return algorithm, nil
+	case "":
+		return AlgorithmSlidingWindow, nil
+	default:
+		return "", fmt.Errorf("invalid rate limit algorithm: %s", value)
+	}
+}
+
+// ErrRateLimitExceeded matches every RateLimitExceededError with errors.Is
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// RateLimitExceededError is returned by Acquire when a limit is reached
+type RateLimitExceededError struct {
+	// Limit names the limit reached: active, tps, tpm, tph or tpd
+	Limit string
+	// RetryAfter is how long until a transaction can be admitted, 0 when unknown,
+	// as for the active transactions limit which depends on releases
+	RetryAfter time.Duration
+	message    string
+}
+
+// Error returns the message of the reached limit
+func (e *RateLimitExceededError) Error() string {
+	return e.message
+}
+
+// Is reports whether the target is ErrRateLimitExceeded
+func (e *RateLimitExceededError) Is(target error) bool {
+	return target == ErrRateLimitExceeded
+}
+
+// RetryAfter returns the retry-after duration of a rate limit error
+func RetryAfter(err error) (time.Duration, bool) {
+	var exceeded *RateLimitExceededError
+	if errors.As(err, &exceeded) {
+		return exceeded.RetryAfter, true
+	}
+	return 0, false
+}
+
+// RateLimitResult describes an acquisition and the most restrictive limit after it
+type RateLimitResult struct {
+	// TransactionID identifies the acquired transaction, empty when denied
+	TransactionID string
+	// Limit names the most restrictive limit: active, tps, tpm, tph or tpd
+	Limit string
+	// Quota is the number of transactions that limit admits per window
+	Quota int
+	// Burst is the number of transactions that limit admits at once, the quota unless a burst is configured
+	Burst int
+	// Window is the window of that limit, 0 for active transactions
+	Window time.Duration
+	// Remaining is the number of transactions that limit still admits
+	Remaining int
+	// ResetAfter is how long until that limit is fully available again
+	ResetAfter time.Duration
+	// RetryAfter is how long until a transaction can be admitted when denied
+	RetryAfter time.Duration
+}
+
+// Allowed reports whether the transaction was acquired
+func (r *RateLimitResult) Allowed() bool {
+	return r.TransactionID != ""
+}
+
+// rateLimitWindow is a per-window limit of RateLimiterOptions
+type rateLimitWindow struct {
+	name     string
+	metric   string
+	duration time.Duration
+	limit    func(opts *RateLimiterOptions) int
+	message  string
+}
+
+// rateLimitWindows are the windows in the order of their KEYS and result codes (-1 to -4)
+var rateLimitWindows = []rateLimitWindow{
+	{name: "tps", metric: "transactions_per_second", duration: time.Second, limit: func(o *RateLimiterOptions) int { return o.MaxTransactionsPerSecond }, message: "transactions per second limit reached (%d TPS)"},
+	{name: "tpm", metric: "transactions_per_minute", duration: time.Minute, limit: func(o *RateLimiterOptions) int { return o.MaxTransactionsPerMinute }, message: "transactions per minute limit reached (%d TPM)"},
+	{name: "tph", metric: "transactions_per_hour", duration: time.Hour, limit: func(o *RateLimiterOptions) int { return o.MaxTransactionsPerHour }, message: "transactions per hour limit reached (%d TPH)"},
+	{name: "tpd", metric: "transactions_per_day", duration: 24 * time.Hour, limit: func(o *RateLimiterOptions) int { return o.MaxTransactionsPerDay }, message: "transactions per day limit reached (%d TPD)"},
+}
+
+// rateLimitPrelude reads the arguments shared by every algorithm script.
+// KEYS[1] is the active transactions counter and KEYS[2..5] the window keys.
+// ARGV[1] is the active limit, ARGV[2] the transaction ID, ARGV[3] the active counter TTL in
+// seconds, then for each window its limit, duration in milliseconds and burst (0 for the limit).
+const rateLimitPrelude = luaNowMs + `
+	local max_active = tonumber(ARGV[1])
+	local transaction_id = ARGV[2]
+	local active_ttl = tonumber(ARGV[3])
+	local windows = {}
+	for i = 1, 4 do
+		local base = 3 + (i - 1) * 3
+		local limit = tonumber(ARGV[base + 1])
+		local burst = tonumber(ARGV[base + 3])
+		if burst <= 0 then
+			burst = limit
+		end
+		windows[i] = {code = -i, key = KEYS[i + 1], limit = limit, window = tonumber(ARGV[base + 2]), burst = burst}
+	end
+`
+
+// rateLimitAcquireBody checks every configured window with the check(w) function of the
+// algorithm and, only if all admit the transaction, records it with commit(w).
+// It returns {code, retry after ms, remaining, reset ms, limiting code}, remaining and reset
+// being those of the most restrictive limit, identified by its code (0 for active transactions).
+const rateLimitAcquireBody = `
+	local remaining, reset, limiting = -1, 0, 1
+	if max_active > 0 then
+		local active = tonumber(redis.call("GET", KEYS[1])) or 0
+		if active >= max_active then
+			return {0, 0, 0, 0, 0}
+		end
+		remaining, limiting = max_active - active - 1, 0
+	end
+
+	for _, w in ipairs(windows) do
+		if w.limit > 0 then
+			local allowed, retry_after, left, reset_after = check(w)
+			if not allowed then
+				return {w.code, retry_after, 0, reset_after, w.code}
+			end
+			if remaining < 0 or left < remaining then
+				remaining, reset, limiting = left, reset_after, w.code
+			end
+		end
+	end
+
+	for _, w in ipairs(windows) do
+		if w.limit > 0 then
+			commit(w)
+		end
+	end
+
+	if max_active > 0 then
+		redis.call("INCR", KEYS[1])
+		redis.call("EXPIRE", KEYS[1], active_ttl)
+	end
+	return {1, 0, remaining, reset, limiting}
+`
+
+// rateLimitMetricsBody returns, for each window, the transactions counting against its capacity
+const rateLimitMetricsBody = `
+	local used = {}
+	for i, w in ipairs(windows) do
+		used[i] = 0
+		if w.limit > 0 then
+			local allowed, _, left = check(w)
+			local capacity = capacity_of(w)
+			if allowed then
+				used[i] = capacity - left - 1
+			else
+				used[i] = capacity
+			end
+		end
+	end
+	return used
+`
+
+// slidingWindowFunctions keep one sorted set member per transaction, scored in nanoseconds
+const slidingWindowFunctions = `
+	local function capacity_of(w)
+		return w.limit
+	end
+	local function check(w)
+		local now_ns = now * 1000000
+		redis.call("ZREMRANGEBYSCORE", w.key, "-inf", now_ns - w.window * 1000000)
+		local count = redis.call("ZCARD", w.key)
+		local reset_after = w.window
+		local oldest = redis.call("ZRANGE", w.key, 0, 0, "WITHSCORES")
+		if oldest[2] then
+			reset_after = math.max(0, math.ceil((tonumber(oldest[2]) - now_ns) / 1000000) + w.window)
+		end
+		if count >= w.limit then
+			return false, reset_after, 0, reset_after
+		end
+		return true, 0, w.limit - count - 1, reset_after
+	end
+	local function commit(w)
+		redis.call("ZADD", w.key, now * 1000000, transaction_id)
+		redis.call("PEXPIRE", w.key, w.window)
+	end
+`
+
+// tokenBucketFunctions keep the tokens and the last refill time of each window in a hash.
+// Buckets hold up to burst tokens and refill at limit tokens per window.
+const tokenBucketFunctions = `
+	local function capacity_of(w)
+		return w.burst
+	end
+	local function check(w)
+		local rate = w.limit / w.window
+		local state = redis.call("HMGET", w.key, "tokens", "ts")
+		local tokens = tonumber(state[1]) or w.burst
+		local ts = tonumber(state[2]) or now
+		tokens = math.min(w.burst, tokens + math.max(0, now - ts) * rate)
+		w.tokens = tokens
+		if tokens < 1 then
+			return false, math.ceil((1 - tokens) / rate), 0, math.ceil((w.burst - tokens) / rate)
+		end
+		return true, 0, math.floor(tokens - 1), math.ceil((w.burst - tokens + 1) / rate)
+	end
+	local function commit(w)
+		local rate = w.limit / w.window
+		redis.call("HSET", w.key, "tokens", tostring(w.tokens - 1), "ts", now)
+		redis.call("PEXPIRE", w.key, math.ceil(w.burst / rate))
+	end
+`
+
+// gcraFunctions keep the theoretical arrival time (TAT) of each window. Transactions are spaced
+// by window / limit, and up to burst transactions are admitted ahead of schedule.
+const gcraFunctions = `
+	local function capacity_of(w)
+		return w.burst
+	end
+	local function check(w)
+		local interval = w.window / w.limit
+		local tat = math.max(tonumber(redis.call("GET", w.key)) or now, now)
+		local new_tat = tat + interval
+		local allow_at = new_tat - interval * w.burst
+		if now < allow_at then
+			return false, math.ceil(allow_at - now), 0, math.ceil(tat - now)
+		end
+		w.tat = new_tat
+		return true, 0, math.floor((now - allow_at) / interval), math.ceil(new_tat - now)
+	end
+	local function commit(w)
+		redis.call("SET", w.key, tostring(w.tat), "PX", math.max(1, math.ceil(w.tat - now)))
+	end
+`
+
+// fixedWindowFunctions count the transactions of each window in a counter expiring with the window
+const fixedWindowFunctions = `
+	local function capacity_of(w)
+		return w.limit
+	end
+	local function check(w)
+		local count = tonumber(redis.call("GET", w.key)) or 0
+		local ttl = redis.call("PTTL", w.key)
+		if ttl < 0 then
+			ttl = w.window
+		end
+		if count >= w.limit then
+			return false, ttl, 0, ttl
+		end
+		return true, 0, w.limit - count - 1, ttl
+	end
+	local function commit(w)
+		redis.call("INCR", w.key)
+		if redis.call("PTTL", w.key) < 0 then
+			redis.call("PEXPIRE", w.key, w.window)
+		end
+	end
+`
+
+// rateLimitScripts are the acquire and metrics scripts of an algorithm
+type rateLimitScripts struct {
+	acquire *Script
+	metrics *Script
+}
+
+// rateLimitAlgorithmScripts maps each algorithm to its scripts
+var rateLimitAlgorithmScripts = map[RateLimitAlgorithm]rateLimitScripts{
+	AlgorithmSlidingWindow: newRateLimitScripts(AlgorithmSlidingWindow, slidingWindowFunctions),
+	AlgorithmTokenBucket:   newRateLimitScripts(AlgorithmTokenBucket, tokenBucketFunctions),
+	AlgorithmGCRA:          newRateLimitScripts(AlgorithmGCRA, gcraFunctions),
+	AlgorithmFixedWindow:   newRateLimitScripts(AlgorithmFixedWindow, fixedWindowFunctions),
+}
+
+// newRateLimitScripts assembles and registers the scripts of an algorithm from its functions
+func newRateLimitScripts(algorithm RateLimitAlgorithm, functions string) rateLimitScripts {
+	return rateLimitScripts{
+		acquire: RegisterScript("rate-limiter."+string(algorithm)+".acquire", rateLimitPrelude+functions+rateLimitAcquireBody),
+		metrics: RegisterScript("rate-limiter."+string(algorithm)+".metrics", rateLimitPrelude+functions+rateLimitMetricsBody),
+	}
+}
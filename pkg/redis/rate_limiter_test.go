@@ -0,0 +1,240 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAlgorithms(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm RateLimitAlgorithm
+		perMinute int
+		burst     int
+		// capacity is the number of transactions admitted at once
+		capacity int
+		// retryAfter is the wait reported once the capacity is used
+		retryAfter time.Duration
+		// refill is the wait after which one more transaction is admitted
+		refill    time.Duration
+		wantBurst bool
+	}{
+		{
+			name:       "token bucket refills one token every window / limit",
+			algorithm:  AlgorithmTokenBucket,
+			perMinute:  6,
+			burst:      3,
+			capacity:   3,
+			retryAfter: 10 * time.Second,
+			refill:     10 * time.Second,
+			wantBurst:  true,
+		},
+		{
+			name:       "gcra spaces transactions by window / limit",
+			algorithm:  AlgorithmGCRA,
+			perMinute:  6,
+			burst:      3,
+			capacity:   3,
+			retryAfter: 10 * time.Second,
+			refill:     10 * time.Second,
+			wantBurst:  true,
+		},
+		{
+			name:       "fixed window ignores the burst and resets with the window",
+			algorithm:  AlgorithmFixedWindow,
+			perMinute:  3,
+			burst:      10,
+			capacity:   3,
+			retryAfter: time.Minute,
+			refill:     time.Minute,
+		},
+		{
+			name:       "sliding window admits again once the oldest transaction leaves the window",
+			algorithm:  AlgorithmSlidingWindow,
+			perMinute:  3,
+			capacity:   3,
+			retryAfter: time.Minute,
+			refill:     time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t)
+			ctx := context.Background()
+
+			// The scripts read the clock with TIME, so the test drives it together with the TTLs
+			now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			server.SetTime(now)
+			advance := func(d time.Duration) {
+				now = now.Add(d)
+				server.SetTime(now)
+				server.FastForward(d)
+			}
+
+			opts := NewRateLimiterOptions().
+				WithMaxTransactionsPerMinute(tt.perMinute).
+				WithAlgorithm(tt.algorithm).
+				WithNamespace("test-rate-limiters")
+			if tt.burst > 0 {
+				opts.WithBurst(tt.burst)
+			}
+			limiter, err := NewRateLimiter(client, string(tt.algorithm), opts)
+			if err != nil {
+				t.Fatalf("NewRateLimiter() error = %v", err)
+			}
+
+			for i := 0; i < tt.capacity; i++ {
+				result, err := limiter.AcquireWithResult(ctx, "")
+				if err != nil {
+					t.Fatalf("AcquireWithResult() #%d error = %v", i+1, err)
+				}
+				if !result.Allowed() || result.TransactionID == "" {
+					t.Fatalf("AcquireWithResult() #%d = %+v, want allowed", i+1, result)
+				}
+				if want := tt.capacity - i - 1; result.Remaining != want {
+					t.Errorf("Remaining after #%d = %d, want %d", i+1, result.Remaining, want)
+				}
+				if result.Limit != "tpm" || result.Burst != tt.capacity || result.Quota != tt.perMinute {
+					t.Errorf("AcquireWithResult() #%d = %+v, want the tpm limit", i+1, result)
+				}
+			}
+
+			metrics, err := limiter.GetMetrics(ctx)
+			if err != nil {
+				t.Fatalf("GetMetrics() error = %v", err)
+			}
+			want := RateLimiterMetrics{
+				"algorithm":                   string(tt.algorithm),
+				"transactions_per_minute":     "3",
+				"max_transactions_per_minute": strconv.Itoa(tt.perMinute),
+				"tpm_utilization":             "100.0%",
+			}
+			if tt.wantBurst {
+				want["tpm_burst"] = strconv.Itoa(tt.capacity)
+			}
+			assertMetrics(t, metrics, want)
+
+			result, err := limiter.AcquireWithResult(ctx, "")
+			if !errors.Is(err, ErrRateLimitExceeded) {
+				t.Fatalf("AcquireWithResult() at the edge error = %v, want ErrRateLimitExceeded", err)
+			}
+			if result == nil || result.Allowed() || result.TransactionID != "" {
+				t.Fatalf("AcquireWithResult() at the edge = %+v, want denied", result)
+			}
+			retryAfter, ok := RetryAfter(err)
+			if !ok || retryAfter != tt.retryAfter {
+				t.Errorf("RetryAfter() = %v, %v, want %v", retryAfter, ok, tt.retryAfter)
+			}
+			if result.RetryAfter != retryAfter {
+				t.Errorf("result RetryAfter = %v, want %v", result.RetryAfter, retryAfter)
+			}
+
+			advance(tt.refill - time.Millisecond)
+			if _, err := limiter.AcquireWithResult(ctx, ""); !errors.Is(err, ErrRateLimitExceeded) {
+				t.Fatalf("AcquireWithResult() before the refill error = %v, want ErrRateLimitExceeded", err)
+			}
+
+			advance(time.Millisecond)
+			if _, err := limiter.AcquireWithResult(ctx, ""); err != nil {
+				t.Fatalf("AcquireWithResult() after the refill error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRateLimiterMetricsUtilization(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm RateLimitAlgorithm
+		burst     int
+		acquired  int
+		want      RateLimiterMetrics
+	}{
+		{
+			name:      "token bucket counts against the burst",
+			algorithm: AlgorithmTokenBucket,
+			burst:     4,
+			acquired:  1,
+			want: RateLimiterMetrics{
+				"transactions_per_second":     "1",
+				"max_transactions_per_second": "2",
+				"tps_burst":                   "4",
+				"tps_utilization":             "25.0%",
+			},
+		},
+		{
+			name:      "gcra counts against the burst",
+			algorithm: AlgorithmGCRA,
+			burst:     4,
+			acquired:  2,
+			want: RateLimiterMetrics{
+				"transactions_per_second":     "2",
+				"max_transactions_per_second": "2",
+				"tps_burst":                   "4",
+				"tps_utilization":             "50.0%",
+			},
+		},
+		{
+			name:      "fixed window counts against the limit",
+			algorithm: AlgorithmFixedWindow,
+			acquired:  1,
+			want: RateLimiterMetrics{
+				"transactions_per_second":     "1",
+				"max_transactions_per_second": "2",
+				"tps_utilization":             "50.0%",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t)
+			ctx := context.Background()
+			server.SetTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+			opts := NewRateLimiterOptions().
+				WithMaxTransactionsPerSecond(2).
+				WithAlgorithm(tt.algorithm).
+				WithNamespace("test-rate-limiters")
+			if tt.burst > 0 {
+				opts.WithBurst(tt.burst)
+			}
+			limiter, err := NewRateLimiter(client, string(tt.algorithm), opts)
+			if err != nil {
+				t.Fatalf("NewRateLimiter() error = %v", err)
+			}
+
+			for i := 0; i < tt.acquired; i++ {
+				if _, err := limiter.Acquire(ctx); err != nil {
+					t.Fatalf("Acquire() #%d error = %v", i+1, err)
+				}
+			}
+
+			metrics, err := limiter.GetMetrics(ctx)
+			if err != nil {
+				t.Fatalf("GetMetrics() error = %v", err)
+			}
+			tt.want["algorithm"] = string(tt.algorithm)
+			assertMetrics(t, metrics, tt.want)
+		})
+	}
+}
+
+// assertMetrics checks the metrics hold exactly the wanted key-value pairs
+func assertMetrics(t *testing.T, got, want RateLimiterMetrics) {
+	t.Helper()
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("metrics[%q] = %q, want %q", key, got[key], value)
+		}
+	}
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			t.Errorf("unexpected metric %q = %q", key, got[key])
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// luaNowMs sets the local variable now to the Redis server time in milliseconds, so lease
+// expirations do not depend on the clocks of the instances
+const luaNowMs = `
+	local clock = redis.call("TIME")
+	local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+`
+
+// luaExtendKey defines extend(key, ttl), which keeps a key holding leases alive at least ttl
+// milliseconds, or forever when ttl is 0
+const luaExtendKey = `
+	local function extend(key, ttl)
+		if ttl == 0 then
+			redis.call("PERSIST", key)
+			return
+		end
+		local current = redis.call("PTTL", key)
+		if current == -1 or current < ttl then
+			redis.call("PEXPIRE", key, ttl)
+		end
+	end
+`
+
+// luaLeaseExpiry defines expiry(ttl), the sorted set score of a lease of ttl milliseconds
+const luaLeaseExpiry = `
+	local function expiry(ttl)
+		if ttl == 0 then
+			return "+inf"
+		end
+		return now + ttl
+	end
+`
+
+// readLockScript adds a reader (ARGV[1]) with a lease of ARGV[2] milliseconds to the readers
+// (KEYS[2]) unless a writer holds (KEYS[1]) or waits for (KEYS[3]) the lock
+var readLockScript = RegisterScript("rw-lock.read", luaNowMs+luaExtendKey+luaLeaseExpiry+`
+	redis.call("ZREMRANGEBYSCORE", KEYS[2], "-inf", now)
+	if redis.call("EXISTS", KEYS[1]) == 1 or redis.call("EXISTS", KEYS[3]) == 1 then
+		return 0
+	end
+	local ttl = tonumber(ARGV[2])
+	redis.call("ZADD", KEYS[2], expiry(ttl), ARGV[1])
+	extend(KEYS[2], ttl)
+	return 1
+`)
+
+// writeLockScript sets the writer (KEYS[1]) to ARGV[1] with a TTL of ARGV[2] milliseconds when
+// there is no writer nor reader (KEYS[2]). While readers hold the lock, the writer records its
+// intent (KEYS[3]) for ARGV[3] milliseconds so new readers wait and writers are not starved.
+var writeLockScript = RegisterScript("rw-lock.write", luaNowMs+`
+	redis.call("ZREMRANGEBYSCORE", KEYS[2], "-inf", now)
+	if redis.call("EXISTS", KEYS[1]) == 1 then
+		return 0
+	end
+	local intent = redis.call("GET", KEYS[3])
+	if intent and intent ~= ARGV[1] then
+		return 0
+	end
+	if redis.call("ZCARD", KEYS[2]) > 0 then
+		redis.call("SET", KEYS[3], ARGV[1], "PX", ARGV[3])
+		return 0
+	end
+	if intent then
+		redis.call("DEL", KEYS[3])
+	end
+	if tonumber(ARGV[2]) > 0 then
+		redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	else
+		redis.call("SET", KEYS[1], ARGV[1])
+	end
+	return 1
+`)
+
+// refreshLeaseScript extends the lease of a member (ARGV[1]) of a sorted set of leases (KEYS[1])
+// to ARGV[2] milliseconds, if the lease has not expired. It is shared by readers and semaphore permits.
+var refreshLeaseScript = RegisterScript("lease.refresh", luaNowMs+luaExtendKey+luaLeaseExpiry+`
+	local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+	if not score then
+		return 0
+	end
+	if tonumber(score) <= now then
+		redis.call("ZREM", KEYS[1], ARGV[1])
+		return 0
+	end
+	local ttl = tonumber(ARGV[2])
+	redis.call("ZADD", KEYS[1], "XX", expiry(ttl), ARGV[1])
+	extend(KEYS[1], ttl)
+	return 1
+`)
+
+// countLeasesScript returns the number of unexpired leases in a sorted set (KEYS[1])
+var countLeasesScript = RegisterScript("lease.count", luaNowMs+`
+	return redis.call("ZCOUNT", KEYS[1], "(" .. now, "+inf")
+`)
+
+// rwLockMode is the mode in which an RWLock is held
+type rwLockMode int
+
+const (
+	rwLockUnlocked rwLockMode = iota
+	rwLockRead
+	rwLockWrite
+)
+
+// RWLock is a distributed reader/writer lock. Any number of readers hold it at the same time
+// unless a writer holds it, and a waiting writer keeps new readers out so it is not starved.
+// Each RWLock is one participant: create one per concurrent reader or writer.
+// Readers hold leases of TTL in a sorted set, so a crashed reader releases its share on expiry.
+type RWLock struct {
+	client    *Client
+	key       string
+	value     string
+	opts      *LockOptions
+	refresher autoRefresher
+	mu        sync.RWMutex
+	mode      rwLockMode
+}
+
+var _ RegisteredLock = (*RWLock)(nil)
+
+// NewRWLock creates a new distributed reader/writer lock
+func NewRWLock(client *Client, key string, opts *LockOptions) *RWLock {
+	if opts == nil {
+		opts = DefaultLockOptions()
+	}
+	lock := &RWLock{
+		client: client,
+		key:    key,
+		value:  generateLockValue(opts.Owner),
+		opts:   opts,
+	}
+
+	// Register lock if it has a cache name
+	if opts.CacheName != "" {
+		lockRegistry.RegisterLock(lock)
+	}
+
+	return lock
+}
+
+// buildLockKey constructs the full lock key using LockNamespace::lockKey format
+func (l *RWLock) buildLockKey() string {
+	if l.opts.LockNamespace != "" {
+		return l.opts.LockNamespace + "::" + l.key
+	}
+	return l.key
+}
+
+// writerKey is the key holding the writer. The keys of a lock share its cluster slot.
+func (l *RWLock) writerKey() string {
+	return slotKey(l.buildLockKey(), "writer")
+}
+
+// readersKey is the sorted set holding the reader leases
+func (l *RWLock) readersKey() string {
+	return slotKey(l.buildLockKey(), "readers")
+}
+
+// writerIntentKey is the key holding the writer waiting for the readers to leave
+func (l *RWLock) writerIntentKey() string {
+	return slotKey(l.buildLockKey(), "writer-intent")
+}
+
+// writerIntentTTL is how long a writer keeps new readers out after its last attempt
+func (l *RWLock) writerIntentTTL() time.Duration {
+	return max(2*l.opts.RetryDelay, 100*time.Millisecond)
+}
+
+// RLock acquires the lock for reading
+func (l *RWLock) RLock(ctx context.Context) error {
+	if err := l.acquire(ctx, rwLockRead, func(ctx context.Context) (bool, error) {
+		result, err := readLockScript.Run(ctx, l.client.GetClient(),
+			[]string{l.writerKey(), l.readersKey(), l.writerIntentKey()},
+			l.value, l.opts.TTL.Milliseconds()).Int64()
+		return result == 1, err
+	}); err != nil {
+		return fmt.Errorf("failed to acquire read lock: %w", err)
+	}
+	return nil
+}
+
+// Lock acquires the lock for writing
+func (l *RWLock) Lock(ctx context.Context) error {
+	if err := l.acquire(ctx, rwLockWrite, func(ctx context.Context) (bool, error) {
+		result, err := writeLockScript.Run(ctx, l.client.GetClient(),
+			[]string{l.writerKey(), l.readersKey(), l.writerIntentKey()},
+			l.value, l.opts.TTL.Milliseconds(), l.writerIntentTTL().Milliseconds()).Int64()
+		return result == 1, err
+	}); err != nil {
+		return fmt.Errorf("failed to acquire write lock: %w", err)
+	}
+	return nil
+}
+
+// acquire retries an acquisition in the given mode
+func (l *RWLock) acquire(ctx context.Context, mode rwLockMode, try func(ctx context.Context) (bool, error)) error {
+	if l.currentMode() != rwLockUnlocked {
+		return fmt.Errorf("lock is already held by this client")
+	}
+
+	acquired, err := acquireWithRetry(ctx, l.opts, try)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return fmt.Errorf("not acquired after %d attempts", l.opts.MaxRetries)
+	}
+
+	l.setMode(mode)
+	return nil
+}
+
+// RUnlock releases a read lock
+func (l *RWLock) RUnlock(ctx context.Context) error {
+	if l.currentMode() != rwLockRead {
+		return fmt.Errorf("read lock was not held by this client")
+	}
+	l.StopAutoRefresh()
+
+	removed, err := l.client.GetClient().ZRem(ctx, l.readersKey(), l.value).Result()
+	if err != nil {
+		return fmt.Errorf("failed to release read lock: %w", err)
+	}
+	l.released()
+
+	if removed == 0 {
+		return fmt.Errorf("read lock was not held by this client or has expired")
+	}
+	return nil
+}
+
+// Unlock releases a write lock
+func (l *RWLock) Unlock(ctx context.Context) error {
+	if l.currentMode() != rwLockWrite {
+		return fmt.Errorf("write lock was not held by this client")
+	}
+	l.StopAutoRefresh()
+
+	result, err := releaseLockScript.Run(ctx, l.client.GetClient(), []string{l.writerKey()}, l.value).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to release write lock: %w", err)
+	}
+	l.released()
+
+	if result == 0 {
+		return fmt.Errorf("write lock was not held by this client or has expired")
+	}
+	return nil
+}
+
+// Refresh extends the lease of the held read or write lock
+func (l *RWLock) Refresh(ctx context.Context) error {
+	var result int64
+	var err error
+	switch l.currentMode() {
+	case rwLockRead:
+		result, err = refreshLeaseScript.Run(ctx, l.client.GetClient(), []string{l.readersKey()},
+			l.value, l.opts.TTL.Milliseconds()).Int64()
+	case rwLockWrite:
+		result, err = refreshLockScript.Run(ctx, l.client.GetClient(), []string{l.writerKey()},
+			l.value, l.opts.TTL.Milliseconds()).Int64()
+	default:
+		return fmt.Errorf("lock was not held by this client")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock: %w", err)
+	}
+	if result == 0 {
+		l.setMode(rwLockUnlocked)
+		return fmt.Errorf("lock was not held by this client or has expired")
+	}
+	return nil
+}
+
+// AutoRefresh starts a goroutine that automatically refreshes the held lock
+// If the context is cancelled, the auto-refresh will stop
+func (l *RWLock) AutoRefresh(ctx context.Context) <-chan error {
+	return l.refresher.start(ctx, l.opts, l.Refresh)
+}
+
+// StopAutoRefresh stops the auto-refresh goroutine
+func (l *RWLock) StopAutoRefresh() {
+	l.refresher.stop()
+}
+
+// IsAutoRefreshing returns true if auto-refresh is currently running
+func (l *RWLock) IsAutoRefreshing() bool {
+	return l.refresher.isRunning()
+}
+
+// Readers returns the number of readers currently holding the lock across all instances
+func (l *RWLock) Readers(ctx context.Context) (int64, error) {
+	return countLeasesScript.Run(ctx, l.client.GetClient(), []string{l.readersKey()}).Int64()
+}
+
+// currentMode returns the mode in which this client holds the lock
+func (l *RWLock) currentMode() rwLockMode {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.mode
+}
+
+// IsAcquired returns true if the lock is currently held by this instance, for reading or writing
+func (l *RWLock) IsAcquired() bool {
+	return l.currentMode() != rwLockUnlocked
+}
+
+// IsWriteLocked returns true if the lock is currently held by this instance for writing
+func (l *RWLock) IsWriteLocked() bool {
+	return l.currentMode() == rwLockWrite
+}
+
+// GetCacheName returns the cache name for health check identification
+func (l *RWLock) GetCacheName() string {
+	return l.opts.CacheName
+}
+
+// GetKey returns the lock key
+func (l *RWLock) GetKey() string {
+	return l.key
+}
+
+// GetFullKey returns the full lock key with namespace
+func (l *RWLock) GetFullKey() string {
+	return l.buildLockKey()
+}
+
+// GetLockNamespace returns the namespace of the lock key
+func (l *RWLock) GetLockNamespace() string {
+	return l.opts.LockNamespace
+}
+
+// setMode updates the mode in which the lock is held
+func (l *RWLock) setMode(mode rwLockMode) {
+	l.mu.Lock()
+	l.mode = mode
+	l.mu.Unlock()
+}
+
+// released marks the lock as released and unregisters it
+func (l *RWLock) released() {
+	l.setMode(rwLockUnlocked)
+
+	// Unregister lock from registry
+	if l.opts.CacheName != "" {
+		lockRegistry.UnregisterLock(l.opts.CacheName)
+	}
+}
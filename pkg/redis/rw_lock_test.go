@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRWLockReadersExcludeWriters(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	first := NewRWLock(client, "document", testLockOptions(time.Second))
+	second := NewRWLock(client, "document", testLockOptions(time.Second))
+	writer := NewRWLock(client, "document", testLockOptions(time.Second))
+
+	for _, reader := range []*RWLock{first, second} {
+		if err := reader.RLock(ctx); err != nil {
+			t.Fatalf("RLock() error = %v", err)
+		}
+	}
+	if readers, err := first.Readers(ctx); err != nil || readers != 2 {
+		t.Errorf("Readers() = %d, %v, want 2", readers, err)
+	}
+	if err := writer.Lock(ctx); err == nil {
+		t.Fatal("Lock() while readers hold the lock succeeded")
+	}
+
+	for _, reader := range []*RWLock{first, second} {
+		if err := reader.RUnlock(ctx); err != nil {
+			t.Fatalf("RUnlock() error = %v", err)
+		}
+	}
+	if err := writer.Lock(ctx); err != nil {
+		t.Fatalf("Lock() after the readers released error = %v", err)
+	}
+	if !writer.IsWriteLocked() {
+		t.Error("IsWriteLocked() = false, want true")
+	}
+
+	if err := first.RLock(ctx); err == nil {
+		t.Error("RLock() while a writer holds the lock succeeded")
+	}
+	if err := NewRWLock(client, "document", testLockOptions(time.Second)).Lock(ctx); err == nil {
+		t.Error("Lock() while a writer holds the lock succeeded")
+	}
+
+	if err := writer.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if writer.IsAcquired() {
+		t.Error("IsAcquired() after Unlock() = true, want false")
+	}
+	if err := first.RLock(ctx); err != nil {
+		t.Errorf("RLock() after the writer released error = %v", err)
+	}
+}
+
+func TestRWLockWriterIntent(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	reader := NewRWLock(client, "report", testLockOptions(time.Second))
+	writer := NewRWLock(client, "report", testLockOptions(time.Second))
+	if err := reader.RLock(ctx); err != nil {
+		t.Fatalf("RLock() error = %v", err)
+	}
+
+	// The waiting writer keeps new readers out, so a steady flow of readers does not starve it
+	if err := writer.Lock(ctx); err == nil {
+		t.Fatal("Lock() while a reader holds the lock succeeded")
+	}
+	if err := NewRWLock(client, "report", testLockOptions(time.Second)).RLock(ctx); err == nil {
+		t.Fatal("RLock() while a writer waits succeeded")
+	}
+	if err := NewRWLock(client, "report", testLockOptions(time.Second)).Lock(ctx); err == nil {
+		t.Error("Lock() of another writer while a writer waits succeeded")
+	}
+
+	if err := reader.RUnlock(ctx); err != nil {
+		t.Fatalf("RUnlock() error = %v", err)
+	}
+	if err := writer.Lock(ctx); err != nil {
+		t.Fatalf("Lock() of the waiting writer error = %v", err)
+	}
+	if server.Exists(writer.writerIntentKey()) {
+		t.Error("the writer intent remains once the writer holds the lock")
+	}
+	if err := writer.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+
+	// A writer that gave up stops keeping readers out once its intent expires
+	if err := reader.RLock(ctx); err != nil {
+		t.Fatalf("RLock() error = %v", err)
+	}
+	if err := writer.Lock(ctx); err == nil {
+		t.Fatal("Lock() while a reader holds the lock succeeded")
+	}
+	server.FastForward(writer.writerIntentTTL())
+	if err := NewRWLock(client, "report", testLockOptions(time.Second)).RLock(ctx); err != nil {
+		t.Errorf("RLock() after the writer intent expired error = %v", err)
+	}
+}
+
+func TestRWLockReaderLeaseExpiry(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	ttl := 100 * time.Millisecond
+
+	crashed := NewRWLock(client, "lease", testLockOptions(ttl))
+	if err := crashed.RLock(ctx); err != nil {
+		t.Fatalf("RLock() error = %v", err)
+	}
+
+	// The reader stops refreshing its lease, Redis time passes the end of the lease
+	server.SetTime(time.Now().Add(ttl + 20*time.Millisecond))
+	if readers, err := crashed.Readers(ctx); err != nil || readers != 0 {
+		t.Errorf("Readers() after the lease expired = %d, %v, want 0", readers, err)
+	}
+	writer := NewRWLock(client, "lease", testLockOptions(time.Second))
+	if err := writer.Lock(ctx); err != nil {
+		t.Fatalf("Lock() after the reader lease expired error = %v", err)
+	}
+
+	if err := crashed.Refresh(ctx); err == nil {
+		t.Error("Refresh() of an expired read lock succeeded")
+	}
+	if crashed.IsAcquired() {
+		t.Error("IsAcquired() after a failed refresh = true, want false")
+	}
+	if err := crashed.RUnlock(ctx); err == nil {
+		t.Error("RUnlock() of an expired read lock succeeded")
+	}
+}
+
+func TestRWLockRefreshExtendsReaderLease(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	ttl := 100 * time.Millisecond
+	start := time.Now()
+	server.SetTime(start)
+
+	reader := NewRWLock(client, "refreshed", testLockOptions(ttl))
+	if err := reader.RLock(ctx); err != nil {
+		t.Fatalf("RLock() error = %v", err)
+	}
+	server.SetTime(start.Add(60 * time.Millisecond))
+	if err := reader.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	// Past the first lease, within the refreshed one
+	server.SetTime(start.Add(ttl + 20*time.Millisecond))
+	if readers, err := reader.Readers(ctx); err != nil || readers != 1 {
+		t.Errorf("Readers() after Refresh() = %d, %v, want 1", readers, err)
+	}
+	if err := reader.RUnlock(ctx); err != nil {
+		t.Errorf("RUnlock() error = %v", err)
+	}
+	if err := reader.RUnlock(ctx); err == nil {
+		t.Error("RUnlock() twice succeeded")
+	}
+}
@@ -0,0 +1,240 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Script is a Lua script called by its SHA1 digest with EVALSHA, so its source is only sent
+// to Redis once. When Redis no longer knows the script, after a restart or a failover to a
+// replica that never loaded it, the script is loaded again and the call retried.
+type Script struct {
+	name     string
+	src      string
+	hash     string
+	calls    int64 // atomic counter for calls
+	failures int64 // atomic counter for calls returning an error other than redis.Nil
+	reloads  int64 // atomic counter for NOSCRIPT reloads
+	nanos    int64 // atomic total latency in nanoseconds
+	maxNanos int64 // atomic maximum latency in nanoseconds
+}
+
+// ScriptStats represents the call metrics of a script
+type ScriptStats struct {
+	Name           string        `json:"name"`
+	Hash           string        `json:"hash"`
+	Calls          int64         `json:"calls"`
+	Failures       int64         `json:"failures"`
+	Reloads        int64         `json:"reloads"`
+	AverageLatency time.Duration `json:"average_latency"`
+	MaxLatency     time.Duration `json:"max_latency"`
+}
+
+// ScriptRegistry holds the scripts of the package by name
+type ScriptRegistry struct {
+	scripts map[string]*Script
+	mu      sync.RWMutex
+}
+
+// Global script registry
+var scriptRegistry = &ScriptRegistry{
+	scripts: make(map[string]*Script),
+}
+
+// NewScript creates a script that is not registered, for scripts of the application
+func NewScript(name, src string) *Script {
+	sum := sha1.Sum([]byte(src))
+	return &Script{
+		name: name,
+		src:  src,
+		hash: hex.EncodeToString(sum[:]),
+	}
+}
+
+// RegisterScript creates a script and adds it to the global registry, so it is preloaded by
+// LoadScripts and reported by GetScriptStats. Registering a name twice panics.
+func RegisterScript(name, src string) *Script {
+	return scriptRegistry.Register(name, src)
+}
+
+// Register creates a script and adds it to the registry
+func (sr *ScriptRegistry) Register(name, src string) *Script {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if _, exists := sr.scripts[name]; exists {
+		panic(fmt.Sprintf("script %s is already registered", name))
+	}
+	script := NewScript(name, src)
+	sr.scripts[name] = script
+	return script
+}
+
+// Get returns a registered script
+func (sr *ScriptRegistry) Get(name string) (*Script, bool) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	script, ok := sr.scripts[name]
+	return script, ok
+}
+
+// Scripts returns the registered scripts sorted by name
+func (sr *ScriptRegistry) Scripts() []*Script {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	scripts := make([]*Script, 0, len(sr.scripts))
+	for _, script := range sr.scripts {
+		scripts = append(scripts, script)
+	}
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].name < scripts[j].name })
+	return scripts
+}
+
+// Load loads every registered script, on every master of a Redis Cluster
+func (sr *ScriptRegistry) Load(ctx context.Context, client *Client) error {
+	for _, script := range sr.Scripts() {
+		if err := script.Load(ctx, client.GetClient()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadScripts loads the scripts of the package, so the first calls do not need a reload
+func LoadScripts(ctx context.Context, client *Client) error {
+	return scriptRegistry.Load(ctx, client)
+}
+
+// GetScriptStats returns the call metrics of the scripts of the package by name
+func GetScriptStats() map[string]ScriptStats {
+	scripts := scriptRegistry.Scripts()
+	stats := make(map[string]ScriptStats, len(scripts))
+	for _, script := range scripts {
+		stats[script.name] = script.Stats()
+	}
+	return stats
+}
+
+// Name returns the name of the script
+func (s *Script) Name() string {
+	return s.name
+}
+
+// Hash returns the SHA1 digest identifying the script in Redis
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Load loads the script in Redis
+func (s *Script) Load(ctx context.Context, client redis.Scripter) error {
+	if err := client.ScriptLoad(ctx, s.src).Err(); err != nil {
+		return fmt.Errorf("failed to load script %s: %w", s.name, err)
+	}
+	return nil
+}
+
+// Run calls the script with EVALSHA, loading it again when Redis answers NOSCRIPT
+func (s *Script) Run(ctx context.Context, client redis.Scripter, keys []string, args ...interface{}) *redis.Cmd {
+	start := time.Now()
+
+	cmd := client.EvalSha(ctx, s.hash, keys, args...)
+	if isNoScript(cmd.Err()) {
+		atomic.AddInt64(&s.reloads, 1)
+		if err := s.Load(ctx, client); err != nil {
+			// Loading may fail where EVAL works, for example when SCRIPT is not allowed by the ACL
+			cmd = client.Eval(ctx, s.src, keys, args...)
+		} else {
+			cmd = client.EvalSha(ctx, s.hash, keys, args...)
+		}
+	}
+
+	s.observe(time.Since(start), cmd.Err())
+	return cmd
+}
+
+// ScriptCall is a call of a script queued in a pipeline
+type ScriptCall struct {
+	script *Script
+	keys   []string
+	args   []interface{}
+	cmd    *redis.Cmd
+	start  time.Time
+}
+
+// Queue queues a call of the script with EVALSHA in a pipeline. The script cannot be loaded
+// in the middle of a pipeline, so the result is read with Result once the pipeline is executed.
+func (s *Script) Queue(ctx context.Context, pipe redis.Pipeliner, keys []string, args ...interface{}) *ScriptCall {
+	return &ScriptCall{
+		script: s,
+		keys:   keys,
+		args:   args,
+		cmd:    pipe.EvalSha(ctx, s.hash, keys, args...),
+		start:  time.Now(),
+	}
+}
+
+// Result returns the result of the call once its pipeline is executed. When Redis answered
+// NOSCRIPT, the script is loaded again and the call retried on client.
+func (sc *ScriptCall) Result(ctx context.Context, client redis.Scripter) *redis.Cmd {
+	s := sc.script
+	cmd := sc.cmd
+	if isNoScript(cmd.Err()) {
+		atomic.AddInt64(&s.reloads, 1)
+		if err := s.Load(ctx, client); err != nil {
+			cmd = client.Eval(ctx, s.src, sc.keys, sc.args...)
+		} else {
+			cmd = client.EvalSha(ctx, s.hash, sc.keys, sc.args...)
+		}
+	}
+
+	s.observe(time.Since(sc.start), cmd.Err())
+	return cmd
+}
+
+// observe records the latency and the outcome of a call
+func (s *Script) observe(latency time.Duration, err error) {
+	atomic.AddInt64(&s.calls, 1)
+	atomic.AddInt64(&s.nanos, int64(latency))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		atomic.AddInt64(&s.failures, 1)
+	}
+	for {
+		current := atomic.LoadInt64(&s.maxNanos)
+		if int64(latency) <= current || atomic.CompareAndSwapInt64(&s.maxNanos, current, int64(latency)) {
+			return
+		}
+	}
+}
+
+// Stats returns the call metrics of the script
+func (s *Script) Stats() ScriptStats {
+	stats := ScriptStats{
+		Name:       s.name,
+		Hash:       s.hash,
+		Calls:      atomic.LoadInt64(&s.calls),
+		Failures:   atomic.LoadInt64(&s.failures),
+		Reloads:    atomic.LoadInt64(&s.reloads),
+		MaxLatency: time.Duration(atomic.LoadInt64(&s.maxNanos)),
+	}
+	if stats.Calls > 0 {
+		stats.AverageLatency = time.Duration(atomic.LoadInt64(&s.nanos) / stats.Calls)
+	}
+	return stats
+}
+
+// isNoScript reports whether Redis does not know the script called with EVALSHA
+func isNoScript(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT")
+}
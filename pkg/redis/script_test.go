@@ -0,0 +1,168 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestScriptRunReloadsAfterFlush(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	script := NewScript("test.echo", `return ARGV[1]`)
+
+	if got, err := script.Run(ctx, client.GetClient(), nil, "first").Text(); err != nil || got != "first" {
+		t.Fatalf("Run() = %q, %v, want first", got, err)
+	}
+
+	// Redis forgets the scripts on restart or failover
+	if err := client.GetClient().ScriptFlush(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := script.Run(ctx, client.GetClient(), nil, "second").Text(); err != nil || got != "second" {
+		t.Fatalf("Run() after flush = %q, %v, want second", got, err)
+	}
+
+	stats := script.Stats()
+	if stats.Calls != 2 || stats.Reloads != 2 || stats.Failures != 0 {
+		t.Errorf("Stats() = %+v, want 2 calls, 2 reloads and no failures", stats)
+	}
+	if stats.AverageLatency <= 0 || stats.MaxLatency < stats.AverageLatency {
+		t.Errorf("Stats() latencies = %v average, %v max", stats.AverageLatency, stats.MaxLatency)
+	}
+}
+
+func TestScriptQueueReloadsAfterPipeline(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	script := NewScript("test.pipelined-echo", `return ARGV[1]`)
+
+	// The script is unknown to Redis, the pipelined calls answer NOSCRIPT
+	pipe := client.Pipeline()
+	first := script.Queue(ctx, pipe, nil, "first")
+	second := script.Queue(ctx, pipe, nil, "second")
+	if _, err := pipe.Exec(ctx); !isNoScript(err) {
+		t.Fatalf("Exec() error = %v, want NOSCRIPT", err)
+	}
+
+	for _, tt := range []struct {
+		call *ScriptCall
+		want string
+	}{{first, "first"}, {second, "second"}} {
+		if got, err := tt.call.Result(ctx, client.GetClient()).Text(); err != nil || got != tt.want {
+			t.Errorf("Result() = %q, %v, want %s", got, err, tt.want)
+		}
+	}
+
+	stats := script.Stats()
+	if stats.Calls != 2 || stats.Reloads != 2 || stats.Failures != 0 {
+		t.Errorf("Stats() = %+v, want 2 calls, 2 reloads and no failures", stats)
+	}
+}
+
+func TestScriptRunCountsFailures(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	script := NewScript("test.fail", `return redis.error_reply("boom")`)
+
+	if err := script.Run(ctx, client.GetClient(), nil).Err(); err == nil {
+		t.Fatal("Run() error = nil, want boom")
+	}
+	if stats := script.Stats(); stats.Calls != 1 || stats.Failures != 1 {
+		t.Errorf("Stats() = %+v, want 1 call and 1 failure", stats)
+	}
+}
+
+func TestLoadScripts(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	if err := LoadScripts(ctx, client); err != nil {
+		t.Fatalf("LoadScripts() error = %v", err)
+	}
+
+	scripts := scriptRegistry.Scripts()
+	hashes := make([]string, len(scripts))
+	for i, script := range scripts {
+		hashes[i] = script.Hash()
+	}
+	exists, err := client.GetClient().ScriptExists(ctx, hashes...).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, loaded := range exists {
+		if !loaded {
+			t.Errorf("script %s was not loaded", scripts[i].Name())
+		}
+	}
+
+	if _, ok := GetScriptStats()["lock.acquire"]; !ok {
+		t.Error("GetScriptStats() does not report lock.acquire")
+	}
+}
+
+func TestRegisterScriptTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterScript() with a registered name did not panic")
+		}
+	}()
+	RegisterScript("lock.acquire", `return 1`)
+}
+
+// BenchmarkRateLimitAcquireScript compares sending the full source of the rate limiter script
+// on every call (EVAL) with calling it by digest (EVALSHA)
+func BenchmarkRateLimitAcquireScript(b *testing.B) {
+	client, _ := newTestClient(b)
+	ctx := context.Background()
+	limiter, err := NewRateLimiter(client, "bench", NewRateLimiterOptions().
+		WithAlgorithm(AlgorithmGCRA).
+		WithMaxTransactionsPerSecond(1000000000).
+		WithMaxActiveTransactions(0))
+	if err != nil {
+		b.Fatal(err)
+	}
+	script := limiter.scripts().acquire
+	activeKey, tpsKey, tpmKey, tphKey, tpdKey := limiter.getKeyNames("")
+	keys := []string{activeKey, tpsKey, tpmKey, tphKey, tpdKey}
+
+	b.Run("eval", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := client.GetClient().Eval(ctx, script.src, keys, limiter.scriptArgs(strconv.Itoa(i))...).Err(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("evalsha", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := script.Run(ctx, client.GetClient(), keys, limiter.scriptArgs(strconv.Itoa(i))...).Err(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkLockScripts compares acquiring and releasing a lock with EVAL and with EVALSHA
+func BenchmarkLockScripts(b *testing.B) {
+	client, _ := newTestClient(b)
+	ctx := context.Background()
+	keys := []string{"bench-locks::job", fencingTokenKey("bench-locks::job")}
+	ttl := time.Second.Milliseconds()
+
+	b.Run("eval", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			client.GetClient().Eval(ctx, acquireLockScript.src, keys, "owner", ttl)
+			client.GetClient().Eval(ctx, releaseLockScript.src, keys[:1], "owner")
+		}
+	})
+
+	b.Run("evalsha", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			acquireLockScript.Run(ctx, client.GetClient(), keys, "owner", ttl)
+			releaseLockScript.Run(ctx, client.GetClient(), keys[:1], "owner")
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrSemaphoreFull is returned by Semaphore.Acquire when every permit is still held after the retries
+var ErrSemaphoreFull = errors.New("semaphore has no permit available")
+
+// acquireSemaphoreScript adds a permit (ARGV[1]) with a lease of ARGV[3] milliseconds to the
+// permits (KEYS[1]) if less than ARGV[2] unexpired permits are held
+var acquireSemaphoreScript = RegisterScript("semaphore.acquire", luaNowMs+luaExtendKey+luaLeaseExpiry+`
+	redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+	if redis.call("ZCARD", KEYS[1]) >= tonumber(ARGV[2]) then
+		return 0
+	end
+	local ttl = tonumber(ARGV[3])
+	redis.call("ZADD", KEYS[1], expiry(ttl), ARGV[1])
+	extend(KEYS[1], ttl)
+	return 1
+`)
+
+// Semaphore is a distributed counting semaphore allowing up to Limit concurrent holders across
+// all instances. Each holder gets a SemaphorePermit with a lease of TTL, so the permits of a
+// crashed instance are released on expiry. One Semaphore is shared by all goroutines of an instance.
+type Semaphore struct {
+	client  *Client
+	key     string
+	limit   int
+	opts    *LockOptions
+	mu      sync.Mutex
+	permits map[string]*SemaphorePermit
+}
+
+var _ RegisteredLock = (*Semaphore)(nil)
+
+// NewSemaphore creates a new distributed semaphore with the given number of permits
+func NewSemaphore(client *Client, key string, limit int, opts *LockOptions) *Semaphore {
+	if limit < 1 {
+		panic(fmt.Sprintf("invalid semaphore limit: %d, must be positive", limit))
+	}
+	if opts == nil {
+		opts = DefaultLockOptions()
+	}
+	semaphore := &Semaphore{
+		client:  client,
+		key:     key,
+		limit:   limit,
+		opts:    opts,
+		permits: make(map[string]*SemaphorePermit),
+	}
+
+	// Register semaphore if it has a cache name
+	if opts.CacheName != "" {
+		lockRegistry.RegisterLock(semaphore)
+	}
+
+	return semaphore
+}
+
+// buildLockKey constructs the full semaphore key using LockNamespace::key format
+func (s *Semaphore) buildLockKey() string {
+	if s.opts.LockNamespace != "" {
+		return s.opts.LockNamespace + "::" + s.key
+	}
+	return s.key
+}
+
+// Acquire waits for a permit, following the retry options.
+// It returns an error wrapping ErrSemaphoreFull when the retries are exhausted.
+func (s *Semaphore) Acquire(ctx context.Context) (*SemaphorePermit, error) {
+	permit := &SemaphorePermit{
+		semaphore: s,
+		id:        generateLockValue(s.opts.Owner),
+	}
+
+	acquired, err := acquireWithRetry(ctx, s.opts, permit.tryAcquire)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire semaphore permit: %w", err)
+	}
+	if !acquired {
+		return nil, fmt.Errorf("%w: %d permits of %s held after %d attempts", ErrSemaphoreFull, s.limit, s.buildLockKey(), s.opts.MaxRetries)
+	}
+
+	s.mu.Lock()
+	s.permits[permit.id] = permit
+	s.mu.Unlock()
+	return permit, nil
+}
+
+// WithPermit executes a function while holding a permit, refreshing it in the background
+func (s *Semaphore) WithPermit(ctx context.Context, fn func() error) error {
+	permit, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer permit.Release(context.WithoutCancel(ctx))
+
+	if s.opts.RefreshInterval > 0 && s.opts.TTL > 0 {
+		permit.AutoRefresh(ctx)
+	}
+	return fn()
+}
+
+// Holders returns the number of permits currently held across all instances
+func (s *Semaphore) Holders(ctx context.Context) (int64, error) {
+	return countLeasesScript.Run(ctx, s.client.GetClient(), []string{s.buildLockKey()}).Int64()
+}
+
+// HeldPermits returns the number of permits held by this instance
+func (s *Semaphore) HeldPermits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.permits)
+}
+
+// Limit returns the maximum number of concurrent holders
+func (s *Semaphore) Limit() int {
+	return s.limit
+}
+
+// IsAcquired returns true if this instance currently holds at least one permit
+func (s *Semaphore) IsAcquired() bool {
+	return s.HeldPermits() > 0
+}
+
+// GetCacheName returns the cache name for health check identification
+func (s *Semaphore) GetCacheName() string {
+	return s.opts.CacheName
+}
+
+// GetKey returns the semaphore key
+func (s *Semaphore) GetKey() string {
+	return s.key
+}
+
+// GetFullKey returns the full semaphore key with namespace
+func (s *Semaphore) GetFullKey() string {
+	return s.buildLockKey()
+}
+
+// GetLockNamespace returns the namespace of the semaphore key
+func (s *Semaphore) GetLockNamespace() string {
+	return s.opts.LockNamespace
+}
+
+// forget removes a permit that is no longer held
+func (s *Semaphore) forget(permit *SemaphorePermit) {
+	s.mu.Lock()
+	delete(s.permits, permit.id)
+	s.mu.Unlock()
+}
+
+// SemaphorePermit is a permit of a Semaphore held by this instance
+type SemaphorePermit struct {
+	semaphore *Semaphore
+	id        string
+	refresher autoRefresher
+	mu        sync.RWMutex
+	expiresAt time.Time
+}
+
+// tryAcquire makes a single attempt to take a permit
+func (p *SemaphorePermit) tryAcquire(ctx context.Context) (bool, error) {
+	s := p.semaphore
+	start := time.Now()
+	result, err := acquireSemaphoreScript.Run(ctx, s.client.GetClient(), []string{s.buildLockKey()},
+		p.id, s.limit, s.opts.TTL.Milliseconds()).Int64()
+	if err != nil || result == 0 {
+		return false, err
+	}
+
+	p.setExpiresAt(start)
+	return true, nil
+}
+
+// ID returns the unique identifier of the permit
+func (p *SemaphorePermit) ID() string {
+	return p.id
+}
+
+// Release returns the permit to the semaphore
+func (p *SemaphorePermit) Release(ctx context.Context) error {
+	p.StopAutoRefresh()
+	s := p.semaphore
+
+	removed, err := s.client.GetClient().ZRem(ctx, s.buildLockKey(), p.id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to release semaphore permit: %w", err)
+	}
+	s.forget(p)
+
+	if removed == 0 {
+		return fmt.Errorf("semaphore permit was not held or has expired")
+	}
+	return nil
+}
+
+// Refresh extends the lease of the permit
+func (p *SemaphorePermit) Refresh(ctx context.Context) error {
+	s := p.semaphore
+	start := time.Now()
+	result, err := refreshLeaseScript.Run(ctx, s.client.GetClient(), []string{s.buildLockKey()},
+		p.id, s.opts.TTL.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to refresh semaphore permit: %w", err)
+	}
+	if result == 0 {
+		s.forget(p)
+		return fmt.Errorf("semaphore permit was not held or has expired")
+	}
+
+	p.setExpiresAt(start)
+	return nil
+}
+
+// AutoRefresh starts a goroutine that automatically refreshes the permit
+// If the context is cancelled, the auto-refresh will stop
+func (p *SemaphorePermit) AutoRefresh(ctx context.Context) <-chan error {
+	return p.refresher.start(ctx, p.semaphore.opts, p.Refresh)
+}
+
+// StopAutoRefresh stops the auto-refresh goroutine
+func (p *SemaphorePermit) StopAutoRefresh() {
+	p.refresher.stop()
+}
+
+// Validity returns how long the permit is still guaranteed to be held, accounting for clock drift.
+// A permit without TTL never expires.
+func (p *SemaphorePermit) Validity() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.expiresAt.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return max(time.Until(p.expiresAt), 0)
+}
+
+// setExpiresAt records the end of a lease taken at start
+func (p *SemaphorePermit) setExpiresAt(start time.Time) {
+	opts := p.semaphore.opts
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if opts.TTL <= 0 {
+		p.expiresAt = time.Time{}
+		return
+	}
+	p.expiresAt = start.Add(opts.TTL - lockClockDrift(opts.TTL, opts.ClockDriftFactor))
+}
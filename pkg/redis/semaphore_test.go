@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSemaphorePermitLimit(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	semaphore := NewSemaphore(client, "limited", 2, testLockOptions(time.Second))
+
+	first, err := semaphore.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if _, err := semaphore.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// Another instance shares the same permits
+	other := NewSemaphore(client, "limited", 2, testLockOptions(time.Second))
+	if _, err := other.Acquire(ctx); !errors.Is(err, ErrSemaphoreFull) {
+		t.Fatalf("Acquire() of a third permit error = %v, want %v", err, ErrSemaphoreFull)
+	}
+	if holders, err := other.Holders(ctx); err != nil || holders != 2 {
+		t.Errorf("Holders() = %d, %v, want 2", holders, err)
+	}
+	if semaphore.HeldPermits() != 2 || other.IsAcquired() {
+		t.Errorf("HeldPermits() = %d and %d, want 2 and 0", semaphore.HeldPermits(), other.HeldPermits())
+	}
+
+	if err := first.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := other.Acquire(ctx); err != nil {
+		t.Errorf("Acquire() after Release() error = %v", err)
+	}
+}
+
+func TestSemaphorePermitLeaseExpiry(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	ttl := 100 * time.Millisecond
+
+	crashed := NewSemaphore(client, "lease", 1, testLockOptions(ttl))
+	permit, err := crashed.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if validity := permit.Validity(); validity <= 0 || validity > ttl {
+		t.Errorf("Validity() = %v, want within (0, %v]", validity, ttl)
+	}
+
+	// The holder stops refreshing its lease, Redis time passes the end of the lease
+	server.SetTime(time.Now().Add(ttl + 20*time.Millisecond))
+	other := NewSemaphore(client, "lease", 1, testLockOptions(ttl))
+	if _, err := other.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() after the lease expired error = %v", err)
+	}
+
+	if err := permit.Refresh(ctx); err == nil {
+		t.Error("Refresh() of an expired permit succeeded")
+	}
+	if crashed.IsAcquired() {
+		t.Error("IsAcquired() after a failed refresh = true, want false")
+	}
+	if err := permit.Release(ctx); err == nil {
+		t.Error("Release() of an expired permit succeeded")
+	}
+	if holders, err := other.Holders(ctx); err != nil || holders != 1 {
+		t.Errorf("Holders() = %d, %v, want the permit of the other instance", holders, err)
+	}
+}
+
+func TestSemaphoreWithPermitReleases(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	semaphore := NewSemaphore(client, "scoped", 1, testLockOptions(time.Second))
+	failure := errors.New("call failed")
+
+	for _, want := range []error{nil, failure} {
+		err := semaphore.WithPermit(ctx, func() error {
+			if holders, err := semaphore.Holders(ctx); err != nil || holders != 1 {
+				t.Errorf("Holders() within WithPermit() = %d, %v, want 1", holders, err)
+			}
+			return want
+		})
+		if !errors.Is(err, want) {
+			t.Errorf("WithPermit() error = %v, want %v", err, want)
+		}
+		if holders, err := semaphore.Holders(ctx); err != nil || holders != 0 || semaphore.IsAcquired() {
+			t.Errorf("Holders() after WithPermit() = %d, %v, want the permit released", holders, err)
+		}
+	}
+}
@@ -0,0 +1,698 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// streamPayloadField is the stream entry field holding the message
+	streamPayloadField = "payload"
+	// deadLetterSuffix is appended to a stream name to build its dead-letter stream
+	deadLetterSuffix = "::dead-letter"
+)
+
+// StreamConsumerHealthCheck represents the health check response for a Redis stream consumer
+type StreamConsumerHealthCheck struct {
+	Status  HealthStatus      `json:"status"`
+	Details map[string]string `json:"details"`
+}
+
+// StreamConfig defines the configuration options for Redis streams. It mirrors PubSubConfig,
+// so switching from pub/sub only requires setting a consumer group.
+type StreamConfig struct {
+	// PoolSize is the number of concurrent message handlers
+	PoolSize int
+	// LogLevel controls the logging verbosity
+	LogLevel LogLevel
+	// ReconnectDelay is the delay between reconnection attempts
+	ReconnectDelay time.Duration
+	// MaxReconnectAttempts is the maximum number of consecutive reconnection attempts
+	MaxReconnectAttempts int
+	// StreamNamespace is the namespace for organizing streams
+	StreamNamespace string
+	// Group is the consumer group sharing the messages of the streams
+	Group string
+	// Consumer identifies this consumer in the group, defaults to the hostname with a random suffix
+	Consumer string
+	// StartID is the first message delivered to a new group: 0 for the whole stream, $ for new messages only
+	StartID string
+	// BatchSize is the maximum number of messages read at once by each worker
+	BatchSize int64
+	// BlockTimeout is how long a read waits for new messages
+	BlockTimeout time.Duration
+	// MaxLen trims the streams to approximately this many messages when publishing, 0 disables trimming
+	MaxLen int64
+	// ClaimMinIdle is how long a message stays pending before another consumer reclaims it
+	ClaimMinIdle time.Duration
+	// ClaimInterval is the delay between pending messages reclaims
+	ClaimInterval time.Duration
+	// MaxDeliveries is the number of deliveries after which a failing message is moved to the
+	// dead-letter stream (StreamName::dead-letter), 0 retries forever
+	MaxDeliveries int64
+}
+
+// NewStreamConfig creates a new stream configuration with default values
+func NewStreamConfig() *StreamConfig {
+	return &StreamConfig{
+		PoolSize:             1,
+		LogLevel:             InfoLevel,
+		ReconnectDelay:       1 * time.Second,
+		MaxReconnectAttempts: 10,
+		StreamNamespace:      "",
+		Group:                "",
+		Consumer:             "",
+		StartID:              "0",
+		BatchSize:            10,
+		BlockTimeout:         5 * time.Second,
+		MaxLen:               0,
+		ClaimMinIdle:         1 * time.Minute,
+		ClaimInterval:        30 * time.Second,
+		MaxDeliveries:        5,
+	}
+}
+
+// WithPoolSize sets the number of concurrent message handlers
+func (sc *StreamConfig) WithPoolSize(poolSize int) *StreamConfig {
+	if poolSize < 1 {
+		panic(fmt.Sprintf("invalid pool size: %d, must be greater than 0", poolSize))
+	}
+	sc.PoolSize = poolSize
+	return sc
+}
+
+// WithLogLevel sets the logging verbosity
+func (sc *StreamConfig) WithLogLevel(logLevel LogLevel) *StreamConfig {
+	sc.LogLevel = logLevel
+	return sc
+}
+
+// WithReconnectDelay sets the delay between reconnection attempts
+func (sc *StreamConfig) WithReconnectDelay(delay time.Duration) *StreamConfig {
+	if delay < 0 {
+		panic(fmt.Sprintf("invalid reconnect delay: %v, must be non-negative", delay))
+	}
+	sc.ReconnectDelay = delay
+	return sc
+}
+
+// WithMaxReconnectAttempts sets the maximum number of consecutive reconnection attempts
+func (sc *StreamConfig) WithMaxReconnectAttempts(maxAttempts int) *StreamConfig {
+	if maxAttempts < 0 {
+		panic(fmt.Sprintf("invalid max reconnect attempts: %d, must be non-negative", maxAttempts))
+	}
+	sc.MaxReconnectAttempts = maxAttempts
+	return sc
+}
+
+// WithStreamNamespace sets the namespace for organizing streams
+func (sc *StreamConfig) WithStreamNamespace(namespace string) *StreamConfig {
+	sc.StreamNamespace = namespace
+	return sc
+}
+
+// WithGroup sets the consumer group sharing the messages of the streams
+func (sc *StreamConfig) WithGroup(group string) *StreamConfig {
+	if group == "" {
+		panic("consumer group must not be empty")
+	}
+	sc.Group = group
+	return sc
+}
+
+// WithConsumer sets the name of this consumer in the group
+func (sc *StreamConfig) WithConsumer(consumer string) *StreamConfig {
+	if consumer == "" {
+		panic("consumer name must not be empty")
+	}
+	sc.Consumer = consumer
+	return sc
+}
+
+// WithStartID sets the first message delivered to a new group
+func (sc *StreamConfig) WithStartID(startID string) *StreamConfig {
+	if startID == "" {
+		panic("start ID must not be empty")
+	}
+	sc.StartID = startID
+	return sc
+}
+
+// WithBatchSize sets the maximum number of messages read at once by each worker
+func (sc *StreamConfig) WithBatchSize(batchSize int64) *StreamConfig {
+	if batchSize < 1 {
+		panic(fmt.Sprintf("invalid batch size: %d, must be greater than 0", batchSize))
+	}
+	sc.BatchSize = batchSize
+	return sc
+}
+
+// WithBlockTimeout sets how long a read waits for new messages
+func (sc *StreamConfig) WithBlockTimeout(timeout time.Duration) *StreamConfig {
+	if timeout < time.Millisecond {
+		panic(fmt.Sprintf("invalid block timeout: %v, must be at least 1ms", timeout))
+	}
+	sc.BlockTimeout = timeout
+	return sc
+}
+
+// WithMaxLen sets the approximate maximum number of messages kept in each stream
+func (sc *StreamConfig) WithMaxLen(maxLen int64) *StreamConfig {
+	if maxLen < 0 {
+		panic(fmt.Sprintf("invalid max length: %d, must be non-negative", maxLen))
+	}
+	sc.MaxLen = maxLen
+	return sc
+}
+
+// WithClaimMinIdle sets how long a message stays pending before it is reclaimed
+func (sc *StreamConfig) WithClaimMinIdle(minIdle time.Duration) *StreamConfig {
+	if minIdle <= 0 {
+		panic(fmt.Sprintf("invalid claim min idle time: %v, must be positive", minIdle))
+	}
+	sc.ClaimMinIdle = minIdle
+	return sc
+}
+
+// WithClaimInterval sets the delay between pending messages reclaims
+func (sc *StreamConfig) WithClaimInterval(interval time.Duration) *StreamConfig {
+	if interval <= 0 {
+		panic(fmt.Sprintf("invalid claim interval: %v, must be positive", interval))
+	}
+	sc.ClaimInterval = interval
+	return sc
+}
+
+// WithMaxDeliveries sets the number of deliveries before a failing message is dead-lettered
+func (sc *StreamConfig) WithMaxDeliveries(maxDeliveries int64) *StreamConfig {
+	if maxDeliveries < 0 {
+		panic(fmt.Sprintf("invalid max deliveries: %d, must be non-negative", maxDeliveries))
+	}
+	sc.MaxDeliveries = maxDeliveries
+	return sc
+}
+
+// buildStreamName constructs the full stream name using StreamNamespace::streamName format
+func (sc *StreamConfig) buildStreamName(stream string) string {
+	if sc.StreamNamespace != "" {
+		return sc.StreamNamespace + "::" + stream
+	}
+	return stream
+}
+
+// DeadLetterStream returns the full name of the dead-letter stream of a stream
+func (sc *StreamConfig) DeadLetterStream(stream string) string {
+	return sc.buildStreamName(stream) + deadLetterSuffix
+}
+
+// StreamProducer appends messages to Redis streams, they are kept until consumed and trimmed
+type StreamProducer struct {
+	client redis.UniversalClient
+	config *StreamConfig
+}
+
+// NewStreamProducer creates a new stream producer
+func NewStreamProducer(client redis.UniversalClient, config *StreamConfig) *StreamProducer {
+	if config == nil {
+		config = NewStreamConfig()
+	}
+	return &StreamProducer{
+		client: client,
+		config: config,
+	}
+}
+
+// Publish appends a message to a stream
+func (p *StreamProducer) Publish(ctx context.Context, stream string, message interface{}) error {
+	_, err := p.add(ctx, p.config.buildStreamName(stream), map[string]interface{}{streamPayloadField: message})
+	return err
+}
+
+// PublishJSON appends a JSON message to a stream
+func (p *StreamProducer) PublishJSON(ctx context.Context, stream string, message interface{}) error {
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message to JSON: %w", err)
+	}
+	return p.Publish(ctx, stream, jsonData)
+}
+
+// add appends the fields to a stream, trimming it to MaxLen
+func (p *StreamProducer) add(ctx context.Context, stream string, values map[string]interface{}) (string, error) {
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}
+	if p.config.MaxLen > 0 {
+		args.MaxLen = p.config.MaxLen
+		args.Approx = true
+	}
+	return p.client.XAdd(ctx, args).Result()
+}
+
+// StreamConsumer consumes messages from Redis streams as a member of a consumer group.
+// A message is acknowledged only after the MessageHandler succeeds, messages left pending by a
+// failing handler or a crashed consumer are reclaimed after ClaimMinIdle, and moved to the
+// dead-letter stream after MaxDeliveries.
+type StreamConsumer struct {
+	client             redis.UniversalClient
+	config             *StreamConfig
+	producer           *StreamProducer
+	streams            []string
+	handler            MessageHandler
+	isRunning          int32 // atomic flag to track if consumer is running
+	messagesProcessed  int64 // atomic counter for processed messages
+	messagesFailed     int64 // atomic counter for failed deliveries
+	messagesReclaimed  int64 // atomic counter for reclaimed messages
+	messagesDeadLetter int64 // atomic counter for dead-lettered messages
+	reconnectAttempts  int32 // atomic counter for consecutive reconnect attempts
+	mu                 sync.RWMutex
+	wg                 sync.WaitGroup
+	ctx                context.Context    // internal context for lifecycle management
+	cancel             context.CancelFunc // cancel function to stop consumer
+}
+
+// NewStreamConsumer creates and returns a new StreamConsumer.
+//
+// If the provided StreamConfig is nil or its fields are zero, the defaults of
+// NewStreamConfig are used, except LogLevel which defaults to Silent as for NewSubscriber.
+//
+// Validations:
+//   - Group must be set.
+//   - PoolSize must be greater than 0.
+func NewStreamConsumer(client redis.UniversalClient, handler MessageHandler, config *StreamConfig) (*StreamConsumer, error) {
+	defaults := NewStreamConfig()
+	defaults.LogLevel = Silent
+	if config != nil {
+		merged := *config
+		if merged.PoolSize == 0 {
+			merged.PoolSize = defaults.PoolSize
+		}
+		if merged.LogLevel == 0 {
+			merged.LogLevel = defaults.LogLevel
+		}
+		if merged.ReconnectDelay == 0 {
+			merged.ReconnectDelay = defaults.ReconnectDelay
+		}
+		if merged.MaxReconnectAttempts == 0 {
+			merged.MaxReconnectAttempts = defaults.MaxReconnectAttempts
+		}
+		if merged.StartID == "" {
+			merged.StartID = defaults.StartID
+		}
+		if merged.BatchSize == 0 {
+			merged.BatchSize = defaults.BatchSize
+		}
+		if merged.BlockTimeout == 0 {
+			merged.BlockTimeout = defaults.BlockTimeout
+		}
+		if merged.ClaimMinIdle == 0 {
+			merged.ClaimMinIdle = defaults.ClaimMinIdle
+		}
+		if merged.ClaimInterval == 0 {
+			merged.ClaimInterval = defaults.ClaimInterval
+		}
+		defaults = &merged
+	}
+	config = defaults
+
+	if config.Group == "" {
+		return nil, fmt.Errorf("consumer group is required")
+	}
+	if config.PoolSize < 1 {
+		return nil, fmt.Errorf("pool size must be greater than 0")
+	}
+	if config.Consumer == "" {
+		config.Consumer = defaultLeaderIdentity()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &StreamConsumer{
+		client:   client,
+		config:   config,
+		producer: NewStreamProducer(client, config),
+		handler:  handler,
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+// Subscribe joins the consumer group on one or more streams, creating the streams and the group if needed
+func (c *StreamConsumer) Subscribe(ctx context.Context, streams ...string) error {
+	namespacedStreams := make([]string, len(streams))
+	for i, stream := range streams {
+		namespacedStreams[i] = c.config.buildStreamName(stream)
+		if err := c.createGroup(ctx, namespacedStreams[i]); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.streams = namespacedStreams
+	c.mu.Unlock()
+	return nil
+}
+
+// createGroup creates the consumer group of a stream, ignoring an existing group
+func (c *StreamConsumer) createGroup(ctx context.Context, stream string) error {
+	err := c.client.XGroupCreateMkStream(ctx, stream, c.config.Group, c.config.StartID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on stream %s: %w", c.config.Group, stream, err)
+	}
+	return nil
+}
+
+// Start begins consuming messages concurrently. It spawns PoolSize workers reading new messages
+// and one worker reclaiming pending messages, until the provided context is canceled or Stop() is called.
+func (c *StreamConsumer) Start(ctx context.Context) {
+	streams := c.getStreams()
+	if len(streams) == 0 {
+		c.logf(ErrorLevel, "not subscribed to any streams")
+		return
+	}
+
+	atomic.StoreInt32(&c.isRunning, 1)
+	defer atomic.StoreInt32(&c.isRunning, 0)
+
+	// Create a combined context that responds to both the provided context and internal cancellation
+	combinedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Monitor internal context for Stop() calls
+	go func() {
+		select {
+		case <-c.ctx.Done():
+			cancel()
+		case <-combinedCtx.Done():
+		}
+	}()
+
+	for i := 0; i < c.config.PoolSize; i++ {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.readMessages(combinedCtx, streams)
+		}()
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.reclaimMessages(combinedCtx, streams)
+	}()
+
+	c.wg.Wait()
+}
+
+// readMessages reads and handles new messages of the group until the context is cancelled
+func (c *StreamConsumer) readMessages(ctx context.Context, streams []string) {
+	args := &redis.XReadGroupArgs{
+		Group:    c.config.Group,
+		Consumer: c.config.Consumer,
+		Streams:  make([]string, 0, len(streams)*2),
+		Count:    c.config.BatchSize,
+		Block:    c.config.BlockTimeout,
+	}
+	args.Streams = append(args.Streams, streams...)
+	for range streams {
+		args.Streams = append(args.Streams, ">")
+	}
+
+	for ctx.Err() == nil {
+		results, err := c.client.XReadGroup(ctx, args).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			if ctx.Err() != nil {
+				return
+			}
+			if !c.recover(ctx, streams, err) {
+				return
+			}
+			continue
+		}
+		atomic.StoreInt32(&c.reconnectAttempts, 0)
+
+		for _, result := range results {
+			for _, message := range result.Messages {
+				c.handleMessage(ctx, result.Stream, message, 1)
+			}
+		}
+	}
+}
+
+// recover waits before reading again after an error, recreating the groups when they were lost.
+// It returns false when the maximum number of reconnection attempts is reached.
+func (c *StreamConsumer) recover(ctx context.Context, streams []string, err error) bool {
+	if strings.HasPrefix(err.Error(), "NOGROUP") {
+		// The stream or the group was deleted, for example by a Redis restart without persistence
+		for _, stream := range streams {
+			if err := c.createGroup(ctx, stream); err != nil {
+				c.logf(ErrorLevel, "failed to recreate consumer group: %v", err)
+			}
+		}
+	}
+
+	attempts := atomic.AddInt32(&c.reconnectAttempts, 1)
+	if int(attempts) > c.config.MaxReconnectAttempts {
+		c.logf(ErrorLevel, "max reconnection attempts reached, stopping stream consumer worker")
+		return false
+	}
+	c.logf(ErrorLevel, "failed to read streams, attempting to reconnect (attempt %d/%d): %v",
+		attempts, c.config.MaxReconnectAttempts, err)
+	return sleepContext(ctx, c.config.ReconnectDelay)
+}
+
+// reclaimMessages periodically claims the messages pending longer than ClaimMinIdle
+func (c *StreamConsumer) reclaimMessages(ctx context.Context, streams []string) {
+	ticker := time.NewTicker(c.config.ClaimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, stream := range streams {
+				if err := c.reclaimStream(ctx, stream); err != nil && ctx.Err() == nil {
+					c.logf(ErrorLevel, "failed to reclaim pending messages of stream %s: %v", stream, err)
+				}
+			}
+		}
+	}
+}
+
+// reclaimStream claims and handles the idle pending messages of a stream with XAUTOCLAIM
+func (c *StreamConsumer) reclaimStream(ctx context.Context, stream string) error {
+	start := "0-0"
+	for {
+		messages, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    c.config.Group,
+			Consumer: c.config.Consumer,
+			MinIdle:  c.config.ClaimMinIdle,
+			Start:    start,
+			Count:    c.config.BatchSize,
+		}).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			atomic.AddInt64(&c.messagesReclaimed, 1)
+			c.handleMessage(ctx, stream, message, c.deliveries(ctx, stream, message.ID))
+		}
+
+		if next == "0-0" || next == "" || ctx.Err() != nil {
+			return nil
+		}
+		start = next
+	}
+}
+
+// deliveries returns how many times a pending message was delivered
+func (c *StreamConsumer) deliveries(ctx context.Context, stream, id string) int64 {
+	pending, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  c.config.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return 1
+	}
+	return pending[0].RetryCount
+}
+
+// handleMessage processes a message, acknowledging it on success. A message that failed
+// MaxDeliveries times is moved to the dead-letter stream instead of staying pending.
+func (c *StreamConsumer) handleMessage(ctx context.Context, stream string, message redis.XMessage, deliveries int64) {
+	maxDeliveries := c.config.MaxDeliveries
+	if maxDeliveries > 0 && deliveries > maxDeliveries {
+		c.deadLetter(ctx, stream, message, deliveries, "max deliveries exceeded")
+		return
+	}
+
+	payload, _ := message.Values[streamPayloadField].(string)
+	if err := c.handler.HandleMessage(ctx, stream, payload); err != nil {
+		atomic.AddInt64(&c.messagesFailed, 1)
+		c.logf(ErrorLevel, "error processing message %s from stream %s (delivery %d): %v", message.ID, stream, deliveries, err)
+		if maxDeliveries > 0 && deliveries >= maxDeliveries {
+			c.deadLetter(ctx, stream, message, deliveries, err.Error())
+		}
+		return
+	}
+
+	if err := c.client.XAck(ctx, stream, c.config.Group, message.ID).Err(); err != nil {
+		c.logf(ErrorLevel, "failed to acknowledge message %s from stream %s: %v", message.ID, stream, err)
+		return
+	}
+
+	c.logf(InfoLevel, "successfully processed message %s from stream %s", message.ID, stream)
+	atomic.AddInt64(&c.messagesProcessed, 1)
+}
+
+// deadLetter moves a message to the dead-letter stream and acknowledges it
+func (c *StreamConsumer) deadLetter(ctx context.Context, stream string, message redis.XMessage, deliveries int64, reason string) {
+	values := map[string]interface{}{
+		"stream":     stream,
+		"id":         message.ID,
+		"group":      c.config.Group,
+		"consumer":   c.config.Consumer,
+		"deliveries": deliveries,
+		"error":      reason,
+	}
+	if payload, ok := message.Values[streamPayloadField]; ok {
+		values[streamPayloadField] = payload
+	}
+
+	if _, err := c.producer.add(ctx, stream+deadLetterSuffix, values); err != nil {
+		c.logf(ErrorLevel, "failed to dead-letter message %s from stream %s: %v", message.ID, stream, err)
+		return
+	}
+	if err := c.client.XAck(ctx, stream, c.config.Group, message.ID).Err(); err != nil {
+		c.logf(ErrorLevel, "failed to acknowledge dead-lettered message %s from stream %s: %v", message.ID, stream, err)
+		return
+	}
+
+	c.logf(ErrorLevel, "message %s from stream %s moved to %s after %d deliveries", message.ID, stream, stream+deadLetterSuffix, deliveries)
+	atomic.AddInt64(&c.messagesDeadLetter, 1)
+}
+
+// getStreams returns the full names of the subscribed streams
+func (c *StreamConsumer) getStreams() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.streams...)
+}
+
+// Pending returns the number of messages delivered to the group but not acknowledged yet
+func (c *StreamConsumer) Pending(ctx context.Context) (int64, error) {
+	var total int64
+	for _, stream := range c.getStreams() {
+		pending, err := c.client.XPending(ctx, stream, c.config.Group).Result()
+		if err != nil {
+			return 0, err
+		}
+		total += pending.Count
+	}
+	return total, nil
+}
+
+// Stop gracefully stops the consumer by canceling its internal context.
+// Messages being handled are left pending and reclaimed later if their handler does not finish.
+// It's safe to call Stop() multiple times.
+func (c *StreamConsumer) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// Close stops the consumer, waits for its workers and removes it from the group when it has no pending messages
+func (c *StreamConsumer) Close() error {
+	c.Stop()
+	c.wg.Wait()
+	atomic.StoreInt32(&c.isRunning, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, stream := range c.getStreams() {
+		pending, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream:   stream,
+			Group:    c.config.Group,
+			Start:    "-",
+			End:      "+",
+			Count:    1,
+			Consumer: c.config.Consumer,
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check pending messages of stream %s: %w", stream, err)
+		}
+		if len(pending) == 0 {
+			if err := c.client.XGroupDelConsumer(ctx, stream, c.config.Group, c.config.Consumer).Err(); err != nil {
+				return fmt.Errorf("failed to leave consumer group on stream %s: %w", stream, err)
+			}
+		}
+	}
+	return nil
+}
+
+// logf logs messages based on the configured log level
+func (c *StreamConsumer) logf(level LogLevel, format string, v ...interface{}) {
+	logAtLevel(c.config.LogLevel, level, format, v...)
+}
+
+// HealthCheck returns the health status and details of the stream consumer
+func (c *StreamConsumer) HealthCheck() StreamConsumerHealthCheck {
+	isRunning := atomic.LoadInt32(&c.isRunning) == 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Test Redis connectivity
+	redisAvailable := c.client.Ping(ctx).Err() == nil
+
+	// Determine status based on both running state and Redis connectivity
+	status := StatusDown
+	if isRunning && redisAvailable {
+		status = StatusUp
+	}
+
+	details := map[string]string{
+		"pool_size":              strconv.Itoa(c.config.PoolSize),
+		"log_level":              logLevelString(c.config.LogLevel),
+		"reconnect_delay":        c.config.ReconnectDelay.String(),
+		"max_reconnect_attempts": strconv.Itoa(c.config.MaxReconnectAttempts),
+		"group":                  c.config.Group,
+		"consumer":               c.config.Consumer,
+		"max_deliveries":         strconv.FormatInt(c.config.MaxDeliveries, 10),
+		"is_running":             strconv.FormatBool(isRunning),
+		"messages_processed":     strconv.FormatInt(atomic.LoadInt64(&c.messagesProcessed), 10),
+		"messages_failed":        strconv.FormatInt(atomic.LoadInt64(&c.messagesFailed), 10),
+		"messages_reclaimed":     strconv.FormatInt(atomic.LoadInt64(&c.messagesReclaimed), 10),
+		"messages_dead_lettered": strconv.FormatInt(atomic.LoadInt64(&c.messagesDeadLetter), 10),
+		"reconnect_attempts":     strconv.FormatInt(int64(atomic.LoadInt32(&c.reconnectAttempts)), 10),
+		"redis_available":        strconv.FormatBool(redisAvailable),
+		"streams":                fmt.Sprintf("%v", c.getStreams()),
+	}
+	if redisAvailable {
+		if pending, err := c.Pending(ctx); err == nil {
+			details["pending_messages"] = strconv.FormatInt(pending, 10)
+		}
+	}
+
+	return StreamConsumerHealthCheck{
+		Status:  status,
+		Details: details,
+	}
+}
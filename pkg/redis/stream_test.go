@@ -0,0 +1,186 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// streamRecorder records the payloads handled by stream consumers
+type streamRecorder struct {
+	mu       sync.Mutex
+	payloads []string
+}
+
+func (r *streamRecorder) record(payload string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payloads = append(r.payloads, payload)
+}
+
+func (r *streamRecorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.payloads...)
+}
+
+// testStreamConfig returns a configuration reading and reclaiming every few milliseconds
+func testStreamConfig(consumer string) *StreamConfig {
+	return NewStreamConfig().
+		WithStreamNamespace("test-streams").
+		WithGroup("workers").
+		WithConsumer(consumer).
+		WithBlockTimeout(20 * time.Millisecond).
+		WithClaimMinIdle(20 * time.Millisecond).
+		WithClaimInterval(20 * time.Millisecond)
+}
+
+// startTestStreamConsumer subscribes a consumer to the orders stream and starts it
+func startTestStreamConsumer(t *testing.T, client *Client, handler MessageHandler, config *StreamConfig) *StreamConsumer {
+	t.Helper()
+	consumer, err := NewStreamConsumer(client.GetClient(), handler, config)
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+	if err := consumer.Subscribe(context.Background(), "orders"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	go consumer.Start(context.Background())
+	t.Cleanup(func() { consumer.Close() })
+	return consumer
+}
+
+func TestStreamConsumerGroupSharesMessages(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	recorder := &streamRecorder{}
+	handler := HandlerFunc(func(ctx context.Context, stream string, payload string) error {
+		recorder.record(payload)
+		return nil
+	})
+
+	first := startTestStreamConsumer(t, client, handler, testStreamConfig("first"))
+	startTestStreamConsumer(t, client, handler, testStreamConfig("second"))
+
+	producer := NewStreamProducer(client.GetClient(), testStreamConfig("producer"))
+	want := []string{"1", "2", "3", "4"}
+	for _, payload := range want {
+		if err := producer.Publish(ctx, "orders", payload); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	// Each message is handled once by one member of the group
+	waitFor(t, time.Second, func() bool { return len(recorder.recorded()) >= len(want) })
+	time.Sleep(50 * time.Millisecond)
+	got := recorder.recorded()
+	sort.Strings(got)
+	if len(got) != len(want) {
+		t.Fatalf("handled payloads = %v, want %v once each", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("handled payloads = %v, want %v once each", got, want)
+		}
+	}
+
+	waitFor(t, time.Second, func() bool {
+		pending, err := first.Pending(ctx)
+		return err == nil && pending == 0
+	})
+}
+
+func TestStreamConsumerReclaimsPendingMessages(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	// A consumer reads a message and crashes before acknowledging it
+	crashed, err := NewStreamConsumer(client.GetClient(), HandlerFunc(func(context.Context, string, string) error {
+		return nil
+	}), testStreamConfig("crashed"))
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+	if err := crashed.Subscribe(ctx, "orders"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := NewStreamProducer(client.GetClient(), testStreamConfig("producer")).Publish(ctx, "orders", "order-1"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := client.GetClient().XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    "workers",
+		Consumer: "crashed",
+		Streams:  []string{"test-streams::orders", ">"},
+		Count:    1,
+	}).Err(); err != nil {
+		t.Fatalf("XReadGroup() error = %v", err)
+	}
+	if pending, err := crashed.Pending(ctx); err != nil || pending != 1 {
+		t.Fatalf("Pending() = %d, %v, want 1", pending, err)
+	}
+
+	recorder := &streamRecorder{}
+	rescuer := startTestStreamConsumer(t, client, HandlerFunc(func(ctx context.Context, stream string, payload string) error {
+		recorder.record(payload)
+		return nil
+	}), testStreamConfig("rescuer"))
+
+	waitFor(t, time.Second, func() bool { return len(recorder.recorded()) == 1 })
+	if got := recorder.recorded(); got[0] != "order-1" {
+		t.Errorf("reclaimed payload = %q, want order-1", got[0])
+	}
+	waitFor(t, time.Second, func() bool {
+		pending, err := rescuer.Pending(ctx)
+		return err == nil && pending == 0
+	})
+	if reclaimed := rescuer.HealthCheck().Details["messages_reclaimed"]; reclaimed != "1" {
+		t.Errorf("messages_reclaimed = %s, want 1", reclaimed)
+	}
+}
+
+func TestStreamConsumerDeadLettersFailingMessages(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	config := testStreamConfig("failing").WithMaxDeliveries(2)
+
+	var deliveries int
+	var mu sync.Mutex
+	consumer := startTestStreamConsumer(t, client, HandlerFunc(func(context.Context, string, string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries++
+		return errors.New("payment service unavailable")
+	}), config)
+
+	if err := NewStreamProducer(client.GetClient(), config).Publish(ctx, "orders", "order-1"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	deadLetter := config.DeadLetterStream("orders")
+	waitFor(t, 2*time.Second, func() bool {
+		length, err := client.GetClient().XLen(ctx, deadLetter).Result()
+		return err == nil && length == 1
+	})
+	messages, err := client.GetClient().XRange(ctx, deadLetter, "-", "+").Result()
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("XRange() of the dead-letter stream = %v, %v, want 1 message", messages, err)
+	}
+	values := messages[0].Values
+	if values[streamPayloadField] != "order-1" || values["deliveries"] != "2" || values["error"] != "payment service unavailable" {
+		t.Errorf("dead-lettered message = %v, want order-1 after 2 deliveries", values)
+	}
+
+	mu.Lock()
+	if deliveries != 2 {
+		t.Errorf("handler calls = %d, want 2", deliveries)
+	}
+	mu.Unlock()
+	if pending, err := consumer.Pending(ctx); err != nil || pending != 0 {
+		t.Errorf("Pending() after dead-lettering = %d, %v, want 0", pending, err)
+	}
+}
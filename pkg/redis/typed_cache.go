@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// KeyEncoder converts a typed key into the string key stored in Redis
+type KeyEncoder[K any] func(key K) string
+
+// Integer is the set of integer types accepted by IntKeyEncoder
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// StringKeyEncoder uses string keys as they are
+func StringKeyEncoder[K ~string]() KeyEncoder[K] {
+	return func(key K) string {
+		return string(key)
+	}
+}
+
+// IntKeyEncoder formats integer keys in base 10
+func IntKeyEncoder[K Integer]() KeyEncoder[K] {
+	return func(key K) string {
+		if key < 0 {
+			return strconv.FormatInt(int64(key), 10)
+		}
+		return strconv.FormatUint(uint64(key), 10)
+	}
+}
+
+// StringerKeyEncoder uses the String method of the key
+func StringerKeyEncoder[K fmt.Stringer]() KeyEncoder[K] {
+	return func(key K) string {
+		return key.String()
+	}
+}
+
+// JSONKeyEncoder encodes composite keys, such as structs, as JSON.
+// Field order follows the struct definition, so equal keys always produce the same string.
+func JSONKeyEncoder[K any]() KeyEncoder[K] {
+	return func(key K) string {
+		data, err := json.Marshal(key)
+		if err != nil {
+			panic(fmt.Sprintf("failed to encode cache key %v: %v", key, err))
+		}
+		return string(data)
+	}
+}
+
+// TypedCache is a type-safe wrapper around Cache. Keys are converted with a KeyEncoder,
+// and values are stored with a Codec behind a payload header, so entries written with a
+// previous codec keep being readable after the codec changes.
+type TypedCache[K comparable, V any] struct {
+	cache      *Cache
+	keyEncoder KeyEncoder[K]
+	codec      Codec
+}
+
+// NewTypedCache creates a typed cache. A nil codec defaults to JSONCodec.
+// The options are copied, and their Serializer and Deserializer are replaced by the codec.
+func NewTypedCache[K comparable, V any](client *Client, opts *CacheOptions, keyEncoder KeyEncoder[K], codec Codec) *TypedCache[K, V] {
+	if keyEncoder == nil {
+		panic("key encoder must not be nil")
+	}
+	if codec == nil {
+		codec = JSONCodec
+	}
+
+	cacheOpts := DefaultCacheOptions()
+	if opts != nil {
+		copied := *opts
+		cacheOpts = &copied
+	}
+	cacheOpts.WithCodec(codec)
+
+	return &TypedCache[K, V]{
+		cache:      NewCache(client, cacheOpts),
+		keyEncoder: keyEncoder,
+		codec:      codec,
+	}
+}
+
+// Cache returns the underlying Cache
+func (tc *TypedCache[K, V]) Cache() *Cache {
+	return tc.cache
+}
+
+// Codec returns the codec used to write values
+func (tc *TypedCache[K, V]) Codec() Codec {
+	return tc.codec
+}
+
+// Get retrieves a value, reporting whether the key was found
+func (tc *TypedCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	var value V
+	found, err := tc.cache.Lookup(ctx, tc.keyEncoder(key), &value)
+	return value, found, err
+}
+
+// Set stores a value with the cache TTL
+func (tc *TypedCache[K, V]) Set(ctx context.Context, key K, value V) error {
+	return tc.cache.Set(ctx, tc.keyEncoder(key), value)
+}
+
+// SetWithTTL stores a value with a custom TTL
+func (tc *TypedCache[K, V]) SetWithTTL(ctx context.Context, key K, value V, ttl time.Duration) error {
+	return tc.cache.SetWithTTL(ctx, tc.keyEncoder(key), value, ttl)
+}
+
+// SetWithTags stores a value and attaches tags to it for InvalidateTags
+func (tc *TypedCache[K, V]) SetWithTags(ctx context.Context, key K, value V, tags ...string) error {
+	return tc.cache.SetWithTags(ctx, tc.keyEncoder(key), value, tags...)
+}
+
+// InvalidateTags deletes every key carrying at least one of the tags
+func (tc *TypedCache[K, V]) InvalidateTags(ctx context.Context, tags ...string) error {
+	return tc.cache.InvalidateTags(ctx, tags...)
+}
+
+// Delete removes a value
+func (tc *TypedCache[K, V]) Delete(ctx context.Context, key K) error {
+	return tc.cache.Delete(ctx, tc.keyEncoder(key))
+}
+
+// Exists checks if a key exists
+func (tc *TypedCache[K, V]) Exists(ctx context.Context, key K) (bool, error) {
+	return tc.cache.Exists(ctx, tc.keyEncoder(key))
+}
+
+// GetOrSet retrieves a value, or loads and stores it with the loader on a miss.
+// It has the stampede protection of Cache.GetOrSet.
+func (tc *TypedCache[K, V]) GetOrSet(ctx context.Context, key K, loader func() (V, error)) (V, error) {
+	var value V
+	err := tc.cache.GetOrSet(ctx, tc.keyEncoder(key), &value, func() (interface{}, error) {
+		return loader()
+	})
+	return value, err
+}
+
+// GetOrSetWithTags works like GetOrSet, attaching the tags returned by the loader to the stored value
+func (tc *TypedCache[K, V]) GetOrSetWithTags(ctx context.Context, key K, loader func() (V, []string, error)) (V, error) {
+	var value V
+	err := tc.cache.GetOrSetWithTags(ctx, tc.keyEncoder(key), &value, func() (interface{}, []string, error) {
+		return loader()
+	})
+	return value, err
+}
+
+// MGet retrieves several values, missing keys are left out of the result
+func (tc *TypedCache[K, V]) MGet(ctx context.Context, keys []K) (map[K]V, error) {
+	encodedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		encodedKeys[i] = tc.keyEncoder(key)
+	}
+
+	payloads, err := tc.cache.MGet(ctx, encodedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[K]V, len(payloads))
+	for i, key := range keys {
+		payload, ok := payloads[encodedKeys[i]]
+		if !ok {
+			continue
+		}
+		var value V
+		if err := DecodeWithHeader(payload, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode key %s: %w", encodedKeys[i], err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// EncodeKey returns the string key stored for a typed key
+func (tc *TypedCache[K, V]) EncodeKey(key K) string {
+	return tc.keyEncoder(key)
+}
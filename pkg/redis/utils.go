@@ -3,6 +3,8 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -106,23 +108,31 @@ type KeyPattern struct {
 	Count   int64
 }
 
-// ScanKeys scans for keys matching a pattern
+// ScanKeys scans for keys matching a pattern, on every master of a Redis Cluster
 func ScanKeys(ctx context.Context, client *Client, pattern string, count int64) ([]string, error) {
+	var mu sync.Mutex
 	var keys []string
-	var cursor uint64
 
-	for {
-		scanKeys, nextCursor, err := client.Scan(ctx, cursor, pattern, count)
-		if err != nil {
-			return nil, fmt.Errorf("scan failed: %w", err)
-		}
-
-		keys = append(keys, scanKeys...)
-		cursor = nextCursor
-
-		if cursor == 0 {
-			break
+	err := client.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+		var cursor uint64
+		for {
+			scanKeys, nextCursor, err := node.Scan(ctx, cursor, pattern, count).Result()
+			if err != nil {
+				return fmt.Errorf("scan failed: %w", err)
+			}
+
+			mu.Lock()
+			keys = append(keys, scanKeys...)
+			mu.Unlock()
+			cursor = nextCursor
+
+			if cursor == 0 {
+				return nil
+			}
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return keys, nil
@@ -256,3 +266,23 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// slotKey builds the key of a companion of key stored in the same Redis Cluster slot, so that
+// both can be used by a single Lua script. The key is wrapped in a hash tag ({key}::suffix),
+// whose slot is the slot of the key itself, unless it already carries a hash tag.
+func slotKey(key, suffix string) string {
+	if hasHashTag(key) {
+		return key + "::" + suffix
+	}
+	return "{" + key + "}::" + suffix
+}
+
+// hasHashTag reports whether only a part of a key, between the first { and the next }, decides its cluster slot
+func hasHashTag(key string) bool {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return false
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	return end > 0
+}
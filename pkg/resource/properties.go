@@ -5,11 +5,12 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 )
 
 var properties map[string]any
-var envPattern = regexp.MustCompile(`\$\{([^:}]+)(?::([^}]+))?}`)
+var envPattern = regexp.MustCompile(`\$\{([^:}]+)(?::([^}]*))?}`)
 
 // init loads application properties from YAML
 func init() {
@@ -73,7 +74,7 @@ func resolveEnvVariable(value string) interface{} {
 		if envValue, exists := os.LookupEnv(envName); exists {
 			return envValue
 		}
-		if defaultValue != "" {
+		if defaultValue != "" || strings.HasSuffix(value, ":}") {
 			return defaultValue
 		}
 		return nil
@@ -165,3 +166,10 @@ func GetSizeInBytes(key string) uint {
 func GetStringSlice(key string) []string {
 	return viper.GetStringSlice(key)
 }
+
+// GetStringMap retrieves a value from the properties map by key and returns it as a map.
+// If the key is not found, it returns an empty map.
+// Example: GetStringMap("server") might return map[string]any{"port": 8080}.
+func GetStringMap(key string) map[string]any {
+	return viper.GetStringMap(key)
+}
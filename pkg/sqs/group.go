@@ -0,0 +1,100 @@
+package sqs
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// messageGroup represents messages of the same FIFO group in receive order.
+// Messages without a group id are wrapped in single-message groups with an empty id.
+type messageGroup struct {
+	id       string
+	messages []*types.Message
+}
+
+// groupMessages splits received messages by MessageGroupId, preserving the receive order inside each group
+func groupMessages(messages []types.Message) []messageGroup {
+	groups := make([]messageGroup, 0, len(messages))
+	indexByID := make(map[string]int)
+
+	for i := range messages {
+		msg := &messages[i]
+		groupID := messageGroupID(msg)
+
+		if groupID == "" {
+			groups = append(groups, messageGroup{messages: []*types.Message{msg}})
+			continue
+		}
+
+		if index, exists := indexByID[groupID]; exists {
+			groups[index].messages = append(groups[index].messages, msg)
+			continue
+		}
+
+		indexByID[groupID] = len(groups)
+		groups = append(groups, messageGroup{id: groupID, messages: []*types.Message{msg}})
+	}
+
+	return groups
+}
+
+// messageGroupID returns the MessageGroupId system attribute of a message, if any
+func messageGroupID(msg *types.Message) string {
+	if msg == nil {
+		return ""
+	}
+	return msg.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]
+}
+
+// groupLocker hands out one mutex per message group, so two messages of the
+// same group are never processed concurrently, even by different pollers
+type groupLocker struct {
+	mu    sync.Mutex
+	locks map[string]*groupLock
+}
+
+// groupLock is a reference counted mutex that is dropped once no poller uses it
+type groupLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// newGroupLocker creates an empty groupLocker
+func newGroupLocker() *groupLocker {
+	return &groupLocker{
+		locks: make(map[string]*groupLock),
+	}
+}
+
+// lock blocks until the group is free and returns the function that releases it
+func (g *groupLocker) lock(groupID string) func() {
+	g.mu.Lock()
+	lock, exists := g.locks[groupID]
+	if !exists {
+		lock = &groupLock{}
+		g.locks[groupID] = lock
+	}
+	lock.refs++
+	g.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		g.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(g.locks, groupID)
+		}
+		g.mu.Unlock()
+	}
+}
+
+// activeGroups returns the number of groups currently being processed or waiting
+func (g *groupLocker) activeGroups() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.locks)
+}
@@ -0,0 +1,202 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// ErrInvalidMessage is returned when a message body cannot be decoded or fails validation
+var ErrInvalidMessage = errors.New("invalid message")
+
+// Handler defines an interface that processes a SQS Message
+//
+// Handler does not receive the worker context. New code should implement
+// ContextHandler instead; existing handlers can be wrapped with AdaptHandler.
+type Handler interface {
+	HandleMessage(msg *types.Message) error
+}
+
+// ContextHandler defines an interface that processes a SQS Message with the worker context
+type ContextHandler interface {
+	HandleMessageContext(ctx context.Context, msg *types.Message) error
+}
+
+// HandlerFunc defines a function that handles a SQS Message
+type HandlerFunc func(msg *types.Message) error
+
+var _ Handler = HandlerFunc(nil)
+var _ ContextHandler = HandlerFunc(nil)
+
+// HandleMessage implements the Handler interface for HandlerFunc
+func (f HandlerFunc) HandleMessage(msg *types.Message) error {
+	return f(msg)
+}
+
+// HandleMessageContext implements the ContextHandler interface for HandlerFunc.
+// The context is ignored, which keeps existing HandlerFunc values usable with the Worker.
+func (f HandlerFunc) HandleMessageContext(_ context.Context, msg *types.Message) error {
+	return f(msg)
+}
+
+// ContextHandlerFunc defines a function that handles a SQS Message with the worker context
+type ContextHandlerFunc func(ctx context.Context, msg *types.Message) error
+
+var _ ContextHandler = ContextHandlerFunc(nil)
+
+// HandleMessageContext implements the ContextHandler interface for ContextHandlerFunc
+func (f ContextHandlerFunc) HandleMessageContext(ctx context.Context, msg *types.Message) error {
+	return f(ctx, msg)
+}
+
+// AdaptHandler wraps a legacy Handler so it can be used where a ContextHandler is expected
+func AdaptHandler(handler Handler) ContextHandler {
+	if handler == nil {
+		return nil
+	}
+	if contextHandler, ok := handler.(ContextHandler); ok {
+		return contextHandler
+	}
+	return ContextHandlerFunc(func(_ context.Context, msg *types.Message) error {
+		return handler.HandleMessage(msg)
+	})
+}
+
+// Validator is implemented by message payloads that can validate themselves after decoding
+type Validator interface {
+	Validate() error
+}
+
+// TypedHandler decodes the JSON body of a SQS Message into T, validates it
+// and only then calls the business handler.
+//
+// Validation runs in two steps: if T (or *T) implements Validator its Validate
+// method is called, followed by the optional validator set with WithValidator.
+// Decoding and validation failures are wrapped with ErrInvalidMessage.
+type TypedHandler[T any] struct {
+	handle                func(ctx context.Context, body T, msg *types.Message) error
+	validate              func(body T) error
+	disallowUnknownFields bool
+}
+
+// NewTypedHandler creates a new TypedHandler that calls handle with the decoded body
+func NewTypedHandler[T any](handle func(ctx context.Context, body T, msg *types.Message) error) *TypedHandler[T] {
+	if handle == nil {
+		panic("typed handler function cannot be nil")
+	}
+	return &TypedHandler[T]{
+		handle: handle,
+	}
+}
+
+// WithValidator sets an additional validation function that runs after decoding
+func (h *TypedHandler[T]) WithValidator(validate func(body T) error) *TypedHandler[T] {
+	h.validate = validate
+	return h
+}
+
+// WithDisallowUnknownFields rejects messages whose body contains fields not present in T
+func (h *TypedHandler[T]) WithDisallowUnknownFields(disallow bool) *TypedHandler[T] {
+	h.disallowUnknownFields = disallow
+	return h
+}
+
+var _ ContextHandler = (*TypedHandler[any])(nil)
+
+// HandleMessageContext implements the ContextHandler interface for TypedHandler
+func (h *TypedHandler[T]) HandleMessageContext(ctx context.Context, msg *types.Message) error {
+	body, err := h.Decode(msg)
+	if err != nil {
+		return err
+	}
+	return h.handle(ctx, body, msg)
+}
+
+// Decode decodes and validates the body of the given message into T
+func (h *TypedHandler[T]) Decode(msg *types.Message) (T, error) {
+	var body T
+
+	if msg == nil || msg.Body == nil {
+		return body, fmt.Errorf("%w: received nil message or message body", ErrInvalidMessage)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(*msg.Body))
+	if h.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&body); err != nil {
+		return body, fmt.Errorf("%w: failed to decode message ID %s: %v", ErrInvalidMessage, safeMessageID(msg), err)
+	}
+
+	if validator, ok := any(body).(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return body, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+		}
+	} else if validator, ok := any(&body).(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return body, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+		}
+	}
+
+	if h.validate != nil {
+		if err := h.validate(body); err != nil {
+			return body, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+		}
+	}
+
+	return body, nil
+}
+
+// messageContextKey is the type used for message metadata stored in the handler context
+type messageContextKey int
+
+const (
+	queueNameContextKey messageContextKey = iota
+	messageIDContextKey
+	receiveCountContextKey
+	correlationIDContextKey
+)
+
+// withMessageMetadata stores the queue name and message metadata in the context passed to handlers
+func withMessageMetadata(ctx context.Context, queueName string, msg *types.Message) context.Context {
+	ctx = context.WithValue(ctx, queueNameContextKey, queueName)
+	ctx = context.WithValue(ctx, messageIDContextKey, safeMessageID(msg))
+	if msg != nil {
+		if count, ok := msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]; ok {
+			ctx = context.WithValue(ctx, receiveCountContextKey, count)
+		}
+		if attribute, ok := msg.MessageAttributes[AttributeCorrelationID]; ok && attribute.StringValue != nil {
+			ctx = context.WithValue(ctx, correlationIDContextKey, *attribute.StringValue)
+		}
+	}
+	return ctx
+}
+
+// QueueNameFromContext returns the name of the queue the message being handled was received from
+func QueueNameFromContext(ctx context.Context) string {
+	value, _ := ctx.Value(queueNameContextKey).(string)
+	return value
+}
+
+// MessageIDFromContext returns the ID of the message being handled
+func MessageIDFromContext(ctx context.Context) string {
+	value, _ := ctx.Value(messageIDContextKey).(string)
+	return value
+}
+
+// ReceiveCountFromContext returns the approximate receive count of the message being handled,
+// or an empty string when the attribute was not requested
+func ReceiveCountFromContext(ctx context.Context) string {
+	value, _ := ctx.Value(receiveCountContextKey).(string)
+	return value
+}
+
+// CorrelationIDFromContext returns the CorrelationId message attribute of the message being handled
+func CorrelationIDFromContext(ctx context.Context) string {
+	value, _ := ctx.Value(correlationIDContextKey).(string)
+	return value
+}
@@ -0,0 +1,806 @@
+package sqs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+)
+
+const (
+	// memoryAccountID is the fake account used in the URLs and ARNs of in-memory queues
+	memoryAccountID = "000000000000"
+	// memoryDefaultVisibilityTimeout is the SQS default visibility timeout
+	memoryDefaultVisibilityTimeout = 30 * time.Second
+	// memoryDeduplicationInterval is the SQS FIFO deduplication interval
+	memoryDeduplicationInterval = 5 * time.Minute
+	// memoryPollInterval is how often a long poll re-checks delayed and invisible messages
+	memoryPollInterval = 20 * time.Millisecond
+)
+
+// MemoryClient is an in-memory implementation of SQSClient and SQSWorkerClient for tests
+// and offline development.
+//
+// It emulates the SQS semantics the Sender and Worker depend on: visibility timeouts,
+// receive counts, queue and message delays, long polling, batch limits, FIFO message
+// groups with deduplication, and redrive to a dead-letter queue through RedrivePolicy.
+// Queues are created with CreateQueue, or on first use when auto-creation is enabled.
+type MemoryClient struct {
+	mu               sync.Mutex
+	queues           map[string]*memoryQueue // keyed by queue URL
+	urls             map[string]string       // queue name to queue URL
+	now              func() time.Time
+	autoCreateQueues bool
+	sequence         int64
+	notify           chan struct{}
+}
+
+// memoryQueue holds the state of a single in-memory queue
+type memoryQueue struct {
+	name                      string
+	url                       string
+	arn                       string
+	fifo                      bool
+	contentBasedDeduplication bool
+	visibilityTimeout         time.Duration
+	delay                     time.Duration
+	redriveTarget             string
+	maxReceiveCount           int
+	redrivePolicy             string
+	createdAt                 time.Time
+	messages                  []*memoryMessage
+	deduplication             map[string]memoryDeduplication
+}
+
+// memoryMessage is a message stored in an in-memory queue
+type memoryMessage struct {
+	id              string
+	body            string
+	attributes      map[string]types.MessageAttributeValue
+	groupID         string
+	deduplicationID string
+	sequenceNumber  string
+	sentAt          time.Time
+	visibleAt       time.Time
+	firstReceivedAt time.Time
+	receiveCount    int
+	receiptHandle   string
+}
+
+// memoryDeduplication remembers a FIFO message accepted within the deduplication interval
+type memoryDeduplication struct {
+	messageID      string
+	sequenceNumber string
+	expiresAt      time.Time
+}
+
+// memoryRedrivePolicy is the JSON document of the RedrivePolicy queue attribute
+type memoryRedrivePolicy struct {
+	DeadLetterTargetArn string          `json:"deadLetterTargetArn"`
+	MaxReceiveCount     json.RawMessage `json:"maxReceiveCount"`
+}
+
+var _ Client = (*MemoryClient)(nil)
+
+// NewMemoryClient creates an empty MemoryClient that uses the wall clock
+func NewMemoryClient() *MemoryClient {
+	return &MemoryClient{
+		queues: make(map[string]*memoryQueue),
+		urls:   make(map[string]string),
+		now:    time.Now,
+		notify: make(chan struct{}),
+	}
+}
+
+// WithClock sets the function used to read the current time, so tests can move time forward
+// to expire visibility timeouts and delays
+func (c *MemoryClient) WithClock(now func() time.Time) *MemoryClient {
+	if now == nil {
+		panic("clock cannot be nil")
+	}
+	c.now = now
+	return c
+}
+
+// WithAutoCreateQueues creates missing queues on GetQueueUrl with default attributes.
+// Names ending with ".fifo" create FIFO queues.
+func (c *MemoryClient) WithAutoCreateQueues(autoCreate bool) *MemoryClient {
+	c.autoCreateQueues = autoCreate
+	return c
+}
+
+// CreateQueue creates a queue with the given attributes, or returns the URL of an existing one.
+// Supported attributes are VisibilityTimeout, DelaySeconds, FifoQueue,
+// ContentBasedDeduplication and RedrivePolicy.
+func (c *MemoryClient) CreateQueue(_ context.Context, params *sqs.CreateQueueInput, _ ...func(*sqs.Options)) (*sqs.CreateQueueOutput, error) {
+	if params == nil || params.QueueName == nil || *params.QueueName == "" {
+		return nil, invalidParameter("queue name is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.createQueueLocked(*params.QueueName, params.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &sqs.CreateQueueOutput{QueueUrl: aws.String(queue.url)}, nil
+}
+
+// GetQueueUrl returns the URL of the queue with the given name
+func (c *MemoryClient) GetQueueUrl(_ context.Context, params *sqs.GetQueueUrlInput, _ ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	if params == nil || params.QueueName == nil {
+		return nil, invalidParameter("queue name is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if url, exists := c.urls[*params.QueueName]; exists {
+		return &sqs.GetQueueUrlOutput{QueueUrl: aws.String(url)}, nil
+	}
+	if !c.autoCreateQueues {
+		return nil, &types.QueueDoesNotExist{Message: aws.String("queue does not exist: " + *params.QueueName)}
+	}
+
+	queue, err := c.createQueueLocked(*params.QueueName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String(queue.url)}, nil
+}
+
+// PurgeQueue deletes all messages of the queue
+func (c *MemoryClient) PurgeQueue(_ context.Context, params *sqs.PurgeQueueInput, _ ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.queueLocked(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	queue.messages = nil
+	return &sqs.PurgeQueueOutput{}, nil
+}
+
+// SendMessage stores a single message in the queue
+func (c *MemoryClient) SendMessage(_ context.Context, params *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.queueLocked(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, sequenceNumber, err := c.sendLocked(queue, params.MessageBody, params.MessageAttributes,
+		params.MessageGroupId, params.MessageDeduplicationId, params.DelaySeconds)
+	if err != nil {
+		return nil, err
+	}
+	c.broadcastLocked()
+
+	return &sqs.SendMessageOutput{
+		MessageId:        aws.String(messageID),
+		MD5OfMessageBody: aws.String(md5Hex(*params.MessageBody)),
+		SequenceNumber:   optionalString(sequenceNumber),
+	}, nil
+}
+
+// SendMessageBatch stores up to 10 messages in the queue, reporting per-entry failures
+func (c *MemoryClient) SendMessageBatch(_ context.Context, params *sqs.SendMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.queueLocked(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]*string, len(params.Entries))
+	for i, entry := range params.Entries {
+		ids[i] = entry.Id
+	}
+	if err := validateBatchEntryIDs(ids); err != nil {
+		return nil, err
+	}
+
+	totalSize := 0
+	for _, entry := range params.Entries {
+		if entry.MessageBody != nil {
+			totalSize += len(*entry.MessageBody)
+		}
+		totalSize += messageAttributesSize(entry.MessageAttributes)
+	}
+	if totalSize > MaxMessageSize {
+		return nil, &types.BatchRequestTooLong{Message: aws.String(fmt.Sprintf("batch requests cannot be longer than %d bytes", MaxMessageSize))}
+	}
+
+	output := &sqs.SendMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		messageID, sequenceNumber, err := c.sendLocked(queue, entry.MessageBody, entry.MessageAttributes,
+			entry.MessageGroupId, entry.MessageDeduplicationId, entry.DelaySeconds)
+		if err != nil {
+			output.Failed = append(output.Failed, batchErrorEntry(entry.Id, err))
+			continue
+		}
+		output.Successful = append(output.Successful, types.SendMessageBatchResultEntry{
+			Id:               entry.Id,
+			MessageId:        aws.String(messageID),
+			MD5OfMessageBody: aws.String(md5Hex(*entry.MessageBody)),
+			SequenceNumber:   optionalString(sequenceNumber),
+		})
+	}
+	c.broadcastLocked()
+
+	return output, nil
+}
+
+// ReceiveMessage returns up to MaxNumberOfMessages visible messages, waiting up to
+// WaitTimeSeconds for messages to become available (long polling)
+func (c *MemoryClient) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	maxMessages := int(params.MaxNumberOfMessages)
+	if maxMessages == 0 {
+		maxMessages = 1
+	}
+	if maxMessages < 1 || maxMessages > MaxBatchEntries {
+		return nil, invalidParameter(fmt.Sprintf("MaxNumberOfMessages must be between 1 and %d", MaxBatchEntries))
+	}
+	if params.WaitTimeSeconds < 0 || params.WaitTimeSeconds > 20 {
+		return nil, invalidParameter("WaitTimeSeconds must be between 0 and 20")
+	}
+
+	deadline := time.Now().Add(time.Duration(params.WaitTimeSeconds) * time.Second)
+
+	for {
+		c.mu.Lock()
+		queue, err := c.queueLocked(params.QueueUrl)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		messages := c.receiveLocked(queue, maxMessages, time.Duration(params.VisibilityTimeout)*time.Second,
+			newAttributeFilter(params.MessageSystemAttributeNames, params.AttributeNames), params.MessageAttributeNames)
+		notify := c.notify
+		c.mu.Unlock()
+
+		remaining := time.Until(deadline)
+		if len(messages) > 0 || remaining <= 0 {
+			return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+		}
+
+		wait := memoryPollInterval
+		if remaining < wait {
+			wait = remaining
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-notify:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// DeleteMessage removes the message identified by its latest receipt handle
+func (c *MemoryClient) DeleteMessage(_ context.Context, params *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.queueLocked(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := queue.deleteMessage(params.ReceiptHandle); err != nil {
+		return nil, err
+	}
+	c.broadcastLocked()
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+// DeleteMessageBatch removes up to 10 messages, reporting per-entry failures
+func (c *MemoryClient) DeleteMessageBatch(_ context.Context, params *sqs.DeleteMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.queueLocked(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]*string, len(params.Entries))
+	for i, entry := range params.Entries {
+		ids[i] = entry.Id
+	}
+	if err := validateBatchEntryIDs(ids); err != nil {
+		return nil, err
+	}
+
+	output := &sqs.DeleteMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		if err := queue.deleteMessage(entry.ReceiptHandle); err != nil {
+			output.Failed = append(output.Failed, batchErrorEntry(entry.Id, err))
+			continue
+		}
+		output.Successful = append(output.Successful, types.DeleteMessageBatchResultEntry{Id: entry.Id})
+	}
+	c.broadcastLocked()
+
+	return output, nil
+}
+
+// ChangeMessageVisibility changes the visibility timeout of an in-flight message.
+// A timeout of zero makes the message visible again immediately.
+func (c *MemoryClient) ChangeMessageVisibility(_ context.Context, params *sqs.ChangeMessageVisibilityInput, _ ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	if params.VisibilityTimeout < 0 || params.VisibilityTimeout > 43200 {
+		return nil, invalidParameter("VisibilityTimeout must be between 0 and 43200")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.queueLocked(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := queue.findByReceiptHandle(params.ReceiptHandle)
+	if msg == nil {
+		return nil, &types.ReceiptHandleIsInvalid{Message: aws.String("receipt handle is invalid")}
+	}
+
+	now := c.now()
+	if !msg.visibleAt.After(now) {
+		return nil, &types.MessageNotInflight{Message: aws.String("message is not in flight")}
+	}
+	msg.visibleAt = now.Add(time.Duration(params.VisibilityTimeout) * time.Second)
+	c.broadcastLocked()
+
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+// GetQueueAttributes returns the requested queue attributes, including the approximate message counts
+func (c *MemoryClient) GetQueueAttributes(_ context.Context, params *sqs.GetQueueAttributesInput, _ ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.queueLocked(params.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	visible, notVisible, delayed := 0, 0, 0
+	for _, msg := range queue.messages {
+		switch {
+		case !msg.visibleAt.After(now):
+			visible++
+		case msg.receiptHandle != "":
+			notVisible++
+		default:
+			delayed++
+		}
+	}
+
+	all := map[string]string{
+		string(types.QueueAttributeNameQueueArn):                              queue.arn,
+		string(types.QueueAttributeNameApproximateNumberOfMessages):           strconv.Itoa(visible),
+		string(types.QueueAttributeNameApproximateNumberOfMessagesNotVisible): strconv.Itoa(notVisible),
+		string(types.QueueAttributeNameApproximateNumberOfMessagesDelayed):    strconv.Itoa(delayed),
+		string(types.QueueAttributeNameVisibilityTimeout):                     strconv.Itoa(int(queue.visibilityTimeout / time.Second)),
+		string(types.QueueAttributeNameDelaySeconds):                          strconv.Itoa(int(queue.delay / time.Second)),
+		string(types.QueueAttributeNameMaximumMessageSize):                    strconv.Itoa(MaxMessageSize),
+		string(types.QueueAttributeNameCreatedTimestamp):                      strconv.FormatInt(queue.createdAt.Unix(), 10),
+	}
+	if queue.fifo {
+		all[string(types.QueueAttributeNameFifoQueue)] = "true"
+		all[string(types.QueueAttributeNameContentBasedDeduplication)] = strconv.FormatBool(queue.contentBasedDeduplication)
+	}
+	if queue.redrivePolicy != "" {
+		all[string(types.QueueAttributeNameRedrivePolicy)] = queue.redrivePolicy
+	}
+
+	attributes := make(map[string]string)
+	for _, name := range params.AttributeNames {
+		if name == types.QueueAttributeNameAll {
+			return &sqs.GetQueueAttributesOutput{Attributes: all}, nil
+		}
+		if value, exists := all[string(name)]; exists {
+			attributes[string(name)] = value
+		}
+	}
+
+	return &sqs.GetQueueAttributesOutput{Attributes: attributes}, nil
+}
+
+// createQueueLocked creates the queue if it does not exist yet
+func (c *MemoryClient) createQueueLocked(name string, attributes map[string]string) (*memoryQueue, error) {
+	if url, exists := c.urls[name]; exists {
+		return c.queues[url], nil
+	}
+
+	queue := &memoryQueue{
+		name:              name,
+		url:               fmt.Sprintf("http://sqs.memory.localhost/%s/%s", memoryAccountID, name),
+		arn:               fmt.Sprintf("arn:aws:sqs:memory:%s:%s", memoryAccountID, name),
+		fifo:              isFIFOQueue(name),
+		visibilityTimeout: memoryDefaultVisibilityTimeout,
+		createdAt:         c.now(),
+		deduplication:     make(map[string]memoryDeduplication),
+	}
+
+	for key, value := range attributes {
+		switch types.QueueAttributeName(key) {
+		case types.QueueAttributeNameVisibilityTimeout:
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < 0 || seconds > 43200 {
+				return nil, invalidAttribute(key, value)
+			}
+			queue.visibilityTimeout = time.Duration(seconds) * time.Second
+		case types.QueueAttributeNameDelaySeconds:
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < 0 || seconds > MaxDelaySeconds {
+				return nil, invalidAttribute(key, value)
+			}
+			queue.delay = time.Duration(seconds) * time.Second
+		case types.QueueAttributeNameFifoQueue:
+			fifo, err := strconv.ParseBool(value)
+			if err != nil || fifo != isFIFOQueue(name) {
+				return nil, invalidAttribute(key, value)
+			}
+		case types.QueueAttributeNameContentBasedDeduplication:
+			contentBased, err := strconv.ParseBool(value)
+			if err != nil || !queue.fifo {
+				return nil, invalidAttribute(key, value)
+			}
+			queue.contentBasedDeduplication = contentBased
+		case types.QueueAttributeNameRedrivePolicy:
+			var policy memoryRedrivePolicy
+			if err := json.Unmarshal([]byte(value), &policy); err != nil || policy.DeadLetterTargetArn == "" {
+				return nil, invalidAttribute(key, value)
+			}
+			maxReceiveCount, err := strconv.Atoi(strings.Trim(string(policy.MaxReceiveCount), `"`))
+			if err != nil || maxReceiveCount < 1 {
+				return nil, invalidAttribute(key, value)
+			}
+			queue.redriveTarget = policy.DeadLetterTargetArn
+			queue.maxReceiveCount = maxReceiveCount
+			queue.redrivePolicy = value
+		default:
+			// Other attributes have no effect on the in-memory queue
+		}
+	}
+
+	c.queues[queue.url] = queue
+	c.urls[name] = queue.url
+	return queue, nil
+}
+
+// queueLocked returns the queue identified by URL
+func (c *MemoryClient) queueLocked(queueURL *string) (*memoryQueue, error) {
+	if queueURL == nil {
+		return nil, invalidParameter("queue URL is required")
+	}
+	queue, exists := c.queues[*queueURL]
+	if !exists {
+		return nil, &types.QueueDoesNotExist{Message: aws.String("queue does not exist: " + *queueURL)}
+	}
+	return queue, nil
+}
+
+// queueByArnLocked returns the queue identified by ARN, used to resolve dead-letter queues
+func (c *MemoryClient) queueByArnLocked(arn string) *memoryQueue {
+	for _, queue := range c.queues {
+		if queue.arn == arn {
+			return queue
+		}
+	}
+	return nil
+}
+
+// sendLocked validates and stores a message, returning its message ID and FIFO sequence number
+func (c *MemoryClient) sendLocked(queue *memoryQueue, body *string, attributes map[string]types.MessageAttributeValue,
+	groupID *string, deduplicationID *string, delaySeconds int32) (string, string, error) {
+	if body == nil || *body == "" {
+		return "", "", invalidParameter("message body must not be empty")
+	}
+	if size := len(*body) + messageAttributesSize(attributes); size > MaxMessageSize {
+		return "", "", invalidParameter(fmt.Sprintf("message must be shorter than %d bytes, got %d", MaxMessageSize, size))
+	}
+	if delaySeconds < 0 || delaySeconds > MaxDelaySeconds {
+		return "", "", invalidParameter(fmt.Sprintf("DelaySeconds must be between 0 and %d", MaxDelaySeconds))
+	}
+
+	now := c.now()
+	msg := &memoryMessage{
+		id:         uuid.NewString(),
+		body:       *body,
+		attributes: attributes,
+		sentAt:     now,
+	}
+
+	if !queue.fifo {
+		if aws.ToString(groupID) != "" || aws.ToString(deduplicationID) != "" {
+			return "", "", invalidParameter("MessageGroupId and MessageDeduplicationId are only supported by FIFO queues")
+		}
+		delay := queue.delay
+		if delaySeconds > 0 {
+			delay = time.Duration(delaySeconds) * time.Second
+		}
+		msg.visibleAt = now.Add(delay)
+		queue.messages = append(queue.messages, msg)
+		return msg.id, "", nil
+	}
+
+	if delaySeconds > 0 {
+		return "", "", invalidParameter("per-message DelaySeconds is not supported by FIFO queues")
+	}
+	if aws.ToString(groupID) == "" {
+		return "", "", invalidParameter("MessageGroupId is required for FIFO queues")
+	}
+
+	dedupID := aws.ToString(deduplicationID)
+	if dedupID == "" {
+		if !queue.contentBasedDeduplication {
+			return "", "", invalidParameter("MessageDeduplicationId is required when ContentBasedDeduplication is disabled")
+		}
+		dedupID = contentDeduplicationID(*body)
+	}
+
+	// Messages with a deduplication ID seen within the interval are accepted but not delivered again
+	if previous, exists := queue.deduplication[dedupID]; exists && now.Before(previous.expiresAt) {
+		return previous.messageID, previous.sequenceNumber, nil
+	}
+
+	c.sequence++
+	msg.groupID = *groupID
+	msg.deduplicationID = dedupID
+	msg.sequenceNumber = fmt.Sprintf("%020d", c.sequence)
+	msg.visibleAt = now.Add(queue.delay)
+	queue.deduplication[dedupID] = memoryDeduplication{
+		messageID:      msg.id,
+		sequenceNumber: msg.sequenceNumber,
+		expiresAt:      now.Add(memoryDeduplicationInterval),
+	}
+	queue.messages = append(queue.messages, msg)
+
+	return msg.id, msg.sequenceNumber, nil
+}
+
+// receiveLocked marks up to maxMessages visible messages as in flight and returns copies of them.
+// FIFO groups with a message in flight are skipped, and messages that reached the
+// maxReceiveCount of the redrive policy are moved to the dead-letter queue.
+func (c *MemoryClient) receiveLocked(queue *memoryQueue, maxMessages int, visibilityTimeout time.Duration,
+	systemAttributes attributeFilter, messageAttributeNames []string) []types.Message {
+	now := c.now()
+	if visibilityTimeout == 0 {
+		visibilityTimeout = queue.visibilityTimeout
+	}
+
+	blockedGroups := make(map[string]bool)
+	if queue.fifo {
+		for _, msg := range queue.messages {
+			if msg.receiptHandle != "" && msg.visibleAt.After(now) {
+				blockedGroups[msg.groupID] = true
+			}
+		}
+	}
+
+	var deadLetterQueue *memoryQueue
+	if queue.redriveTarget != "" {
+		deadLetterQueue = c.queueByArnLocked(queue.redriveTarget)
+	}
+
+	received := make([]types.Message, 0, maxMessages)
+	remaining := queue.messages[:0]
+	for _, msg := range queue.messages {
+		if len(received) == maxMessages || msg.visibleAt.After(now) || blockedGroups[msg.groupID] {
+			remaining = append(remaining, msg)
+			continue
+		}
+
+		if deadLetterQueue != nil && msg.receiveCount >= queue.maxReceiveCount {
+			msg.receiveCount = 0
+			msg.receiptHandle = ""
+			msg.firstReceivedAt = time.Time{}
+			msg.visibleAt = now
+			deadLetterQueue.messages = append(deadLetterQueue.messages, msg)
+			continue
+		}
+
+		msg.receiveCount++
+		if msg.firstReceivedAt.IsZero() {
+			msg.firstReceivedAt = now
+		}
+		msg.receiptHandle = uuid.NewString()
+		msg.visibleAt = now.Add(visibilityTimeout)
+		received = append(received, msg.toMessage(systemAttributes, messageAttributeNames))
+		remaining = append(remaining, msg)
+	}
+	queue.messages = remaining
+
+	return received
+}
+
+// broadcastLocked wakes up long polls waiting for messages
+func (c *MemoryClient) broadcastLocked() {
+	close(c.notify)
+	c.notify = make(chan struct{})
+}
+
+// findByReceiptHandle returns the message with the given current receipt handle
+func (q *memoryQueue) findByReceiptHandle(receiptHandle *string) *memoryMessage {
+	if receiptHandle == nil || *receiptHandle == "" {
+		return nil
+	}
+	for _, msg := range q.messages {
+		if msg.receiptHandle == *receiptHandle {
+			return msg
+		}
+	}
+	return nil
+}
+
+// deleteMessage removes the message with the given current receipt handle
+func (q *memoryQueue) deleteMessage(receiptHandle *string) error {
+	msg := q.findByReceiptHandle(receiptHandle)
+	if msg == nil {
+		return &types.ReceiptHandleIsInvalid{Message: aws.String("receipt handle is invalid")}
+	}
+	for i, candidate := range q.messages {
+		if candidate == msg {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// toMessage converts the stored message to the SDK type, including only the requested attributes
+func (m *memoryMessage) toMessage(systemAttributes attributeFilter, messageAttributeNames []string) types.Message {
+	msg := types.Message{
+		MessageId:     aws.String(m.id),
+		ReceiptHandle: aws.String(m.receiptHandle),
+		Body:          aws.String(m.body),
+		MD5OfBody:     aws.String(md5Hex(m.body)),
+	}
+
+	attributes := map[types.MessageSystemAttributeName]string{
+		types.MessageSystemAttributeNameApproximateReceiveCount:          strconv.Itoa(m.receiveCount),
+		types.MessageSystemAttributeNameSentTimestamp:                    strconv.FormatInt(m.sentAt.UnixMilli(), 10),
+		types.MessageSystemAttributeNameApproximateFirstReceiveTimestamp: strconv.FormatInt(m.firstReceivedAt.UnixMilli(), 10),
+	}
+	if m.groupID != "" {
+		attributes[types.MessageSystemAttributeNameMessageGroupId] = m.groupID
+		attributes[types.MessageSystemAttributeNameMessageDeduplicationId] = m.deduplicationID
+		attributes[types.MessageSystemAttributeNameSequenceNumber] = m.sequenceNumber
+	}
+	for name, value := range attributes {
+		if systemAttributes.matches(string(name)) {
+			if msg.Attributes == nil {
+				msg.Attributes = make(map[string]string)
+			}
+			msg.Attributes[string(name)] = value
+		}
+	}
+
+	for name, value := range m.attributes {
+		if messageAttributeRequested(name, messageAttributeNames) {
+			if msg.MessageAttributes == nil {
+				msg.MessageAttributes = make(map[string]types.MessageAttributeValue)
+			}
+			msg.MessageAttributes[name] = value
+		}
+	}
+
+	return msg
+}
+
+// attributeFilter is the set of requested system attribute names
+type attributeFilter map[string]bool
+
+// newAttributeFilter merges the system attribute names with the deprecated AttributeNames field
+func newAttributeFilter(names []types.MessageSystemAttributeName, legacyNames []types.QueueAttributeName) attributeFilter {
+	filter := make(attributeFilter)
+	for _, name := range names {
+		filter[string(name)] = true
+	}
+	for _, name := range legacyNames {
+		filter[string(name)] = true
+	}
+	return filter
+}
+
+// matches reports whether the system attribute was requested
+func (f attributeFilter) matches(name string) bool {
+	return f["All"] || f[name]
+}
+
+// messageAttributeRequested reports whether the message attribute matches one of the
+// requested names, which can be "All", ".*", an exact name or a "prefix.*" pattern
+func messageAttributeRequested(name string, requested []string) bool {
+	for _, pattern := range requested {
+		switch {
+		case pattern == "All" || pattern == ".*" || pattern == name:
+			return true
+		case strings.HasSuffix(pattern, ".*") && strings.HasPrefix(name, strings.TrimSuffix(pattern, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// validateBatchEntryIDs applies the SQS batch entry count and ID uniqueness rules
+func validateBatchEntryIDs(ids []*string) error {
+	if len(ids) == 0 {
+		return &types.EmptyBatchRequest{Message: aws.String("batch request must contain at least one entry")}
+	}
+	if len(ids) > MaxBatchEntries {
+		return &types.TooManyEntriesInBatchRequest{Message: aws.String(fmt.Sprintf("batch request cannot contain more than %d entries", MaxBatchEntries))}
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if aws.ToString(id) == "" {
+			return &types.InvalidBatchEntryId{Message: aws.String("batch entry ID is required")}
+		}
+		if seen[*id] {
+			return &types.BatchEntryIdsNotDistinct{Message: aws.String("batch entry IDs must be distinct: " + *id)}
+		}
+		seen[*id] = true
+	}
+	return nil
+}
+
+// batchErrorEntry converts a per-entry error to a batch result error entry
+func batchErrorEntry(id *string, err error) types.BatchResultErrorEntry {
+	code := "InvalidParameterValue"
+	var receiptErr *types.ReceiptHandleIsInvalid
+	if errors.As(err, &receiptErr) {
+		code = receiptErr.ErrorCode()
+	}
+	return types.BatchResultErrorEntry{
+		Id:          id,
+		Code:        aws.String(code),
+		Message:     aws.String(err.Error()),
+		SenderFault: true,
+	}
+}
+
+// invalidParameter builds the error returned for invalid request parameters
+func invalidParameter(message string) error {
+	return fmt.Errorf("InvalidParameterValue: %s", message)
+}
+
+// invalidAttribute builds the error returned for invalid queue attributes
+func invalidAttribute(name string, value string) error {
+	return &types.InvalidAttributeValue{Message: aws.String(fmt.Sprintf("invalid value %q for queue attribute %s", value, name))}
+}
+
+// md5Hex returns the MD5 hex digest SQS reports for message bodies
+func md5Hex(value string) string {
+	sum := md5.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// optionalString returns nil for empty strings
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
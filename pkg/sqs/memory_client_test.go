@@ -0,0 +1,507 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// testClock is a manually advanced clock for MemoryClient
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// createTestQueue creates a queue and returns its URL
+func createTestQueue(t *testing.T, client *MemoryClient, name string, attributes map[string]string) string {
+	t.Helper()
+	output, err := client.CreateQueue(context.Background(), &sqs.CreateQueueInput{
+		QueueName:  aws.String(name),
+		Attributes: attributes,
+	})
+	if err != nil {
+		t.Fatalf("CreateQueue(%s) error = %v", name, err)
+	}
+	return *output.QueueUrl
+}
+
+// sendTestMessage sends a message with the given body and optional FIFO group
+func sendTestMessage(t *testing.T, client *MemoryClient, queueURL string, body string, groupID string) {
+	t.Helper()
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String(body),
+	}
+	if groupID != "" {
+		input.MessageGroupId = aws.String(groupID)
+	}
+	if _, err := client.SendMessage(context.Background(), input); err != nil {
+		t.Fatalf("SendMessage(%s) error = %v", body, err)
+	}
+}
+
+// receiveTestMessages receives without long polling
+func receiveTestMessages(t *testing.T, client *MemoryClient, queueURL string, max int32) []types.Message {
+	t.Helper()
+	output, err := client.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(queueURL),
+		MaxNumberOfMessages:         max,
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+		MessageAttributeNames:       []string{"All"},
+	})
+	if err != nil {
+		t.Fatalf("ReceiveMessage error = %v", err)
+	}
+	return output.Messages
+}
+
+// queueAttribute returns a single queue attribute
+func queueAttribute(t *testing.T, client *MemoryClient, queueURL string, name types.QueueAttributeName) string {
+	t.Helper()
+	output, err := client.GetQueueAttributes(context.Background(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{name},
+	})
+	if err != nil {
+		t.Fatalf("GetQueueAttributes error = %v", err)
+	}
+	return output.Attributes[string(name)]
+}
+
+// waitFor polls the condition until it is true or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("condition not met within %s", timeout)
+}
+
+func TestMemoryClientGetQueueUrl(t *testing.T) {
+	client := NewMemoryClient()
+
+	_, err := client.GetQueueUrl(context.Background(), &sqs.GetQueueUrlInput{QueueName: aws.String("missing")})
+	var notFound *types.QueueDoesNotExist
+	if !errors.As(err, &notFound) {
+		t.Fatalf("GetQueueUrl error = %v, want QueueDoesNotExist", err)
+	}
+
+	client.WithAutoCreateQueues(true)
+	output, err := client.GetQueueUrl(context.Background(), &sqs.GetQueueUrlInput{QueueName: aws.String("orders.fifo")})
+	if err != nil {
+		t.Fatalf("GetQueueUrl error = %v", err)
+	}
+	if got := queueAttribute(t, client, *output.QueueUrl, types.QueueAttributeNameFifoQueue); got != "true" {
+		t.Errorf("FifoQueue = %q, want true", got)
+	}
+}
+
+func TestMemoryClientVisibilityTimeout(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	queueURL := createTestQueue(t, client, "visibility", map[string]string{"VisibilityTimeout": "10"})
+
+	sendTestMessage(t, client, queueURL, "hello", "")
+
+	first := receiveTestMessages(t, client, queueURL, 10)
+	if len(first) != 1 {
+		t.Fatalf("received %d messages, want 1", len(first))
+	}
+	if got := first[0].Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]; got != "1" {
+		t.Errorf("ApproximateReceiveCount = %s, want 1", got)
+	}
+	if got := queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessagesNotVisible); got != "1" {
+		t.Errorf("ApproximateNumberOfMessagesNotVisible = %s, want 1", got)
+	}
+
+	clock.Advance(5 * time.Second)
+	if got := receiveTestMessages(t, client, queueURL, 10); len(got) != 0 {
+		t.Fatalf("received %d messages before the visibility timeout, want 0", len(got))
+	}
+
+	clock.Advance(6 * time.Second)
+	second := receiveTestMessages(t, client, queueURL, 10)
+	if len(second) != 1 {
+		t.Fatalf("received %d messages after the visibility timeout, want 1", len(second))
+	}
+	if got := second[0].Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]; got != "2" {
+		t.Errorf("ApproximateReceiveCount = %s, want 2", got)
+	}
+
+	// The first receipt handle is no longer valid
+	_, err := client.DeleteMessage(context.Background(), &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: first[0].ReceiptHandle,
+	})
+	var invalidHandle *types.ReceiptHandleIsInvalid
+	if !errors.As(err, &invalidHandle) {
+		t.Fatalf("DeleteMessage with stale handle error = %v, want ReceiptHandleIsInvalid", err)
+	}
+
+	if _, err := client.DeleteMessage(context.Background(), &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: second[0].ReceiptHandle,
+	}); err != nil {
+		t.Fatalf("DeleteMessage error = %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if got := receiveTestMessages(t, client, queueURL, 10); len(got) != 0 {
+		t.Fatalf("received %d messages after delete, want 0", len(got))
+	}
+}
+
+func TestMemoryClientChangeMessageVisibility(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	queueURL := createTestQueue(t, client, "change-visibility", nil)
+
+	sendTestMessage(t, client, queueURL, "hello", "")
+	received := receiveTestMessages(t, client, queueURL, 1)
+
+	if _, err := client.ChangeMessageVisibility(context.Background(), &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(queueURL),
+		ReceiptHandle:     received[0].ReceiptHandle,
+		VisibilityTimeout: 0,
+	}); err != nil {
+		t.Fatalf("ChangeMessageVisibility error = %v", err)
+	}
+
+	if got := receiveTestMessages(t, client, queueURL, 1); len(got) != 1 {
+		t.Fatalf("received %d messages after releasing visibility, want 1", len(got))
+	}
+}
+
+func TestMemoryClientDelays(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	queueURL := createTestQueue(t, client, "delays", map[string]string{"DelaySeconds": "30"})
+
+	sendTestMessage(t, client, queueURL, "queue-delay", "")
+	if _, err := client.SendMessage(context.Background(), &sqs.SendMessageInput{
+		QueueUrl:     aws.String(queueURL),
+		MessageBody:  aws.String("message-delay"),
+		DelaySeconds: 5,
+	}); err != nil {
+		t.Fatalf("SendMessage error = %v", err)
+	}
+
+	if got := queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessagesDelayed); got != "2" {
+		t.Errorf("ApproximateNumberOfMessagesDelayed = %s, want 2", got)
+	}
+
+	clock.Advance(5 * time.Second)
+	received := receiveTestMessages(t, client, queueURL, 10)
+	if len(received) != 1 || *received[0].Body != "message-delay" {
+		t.Fatalf("received %v after 5s, want only message-delay", bodies(received))
+	}
+
+	clock.Advance(25 * time.Second)
+	received = receiveTestMessages(t, client, queueURL, 10)
+	if len(received) != 1 || *received[0].Body != "queue-delay" {
+		t.Fatalf("received %v after 30s, want only queue-delay", bodies(received))
+	}
+}
+
+func TestMemoryClientLongPolling(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "long-polling", nil)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		sendTestMessage(t, client, queueURL, "late", "")
+	}()
+
+	start := time.Now()
+	output, err := client.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+		QueueUrl:        aws.String(queueURL),
+		WaitTimeSeconds: 5,
+	})
+	if err != nil {
+		t.Fatalf("ReceiveMessage error = %v", err)
+	}
+	if len(output.Messages) != 1 {
+		t.Fatalf("received %d messages, want 1", len(output.Messages))
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("long poll returned after %s, want as soon as the message arrived", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:        aws.String(queueURL),
+		WaitTimeSeconds: 5,
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ReceiveMessage with cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestMemoryClientSendMessageBatch(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "batch", nil)
+
+	tests := []struct {
+		name    string
+		entries []types.SendMessageBatchRequestEntry
+		wantErr any
+	}{
+		{
+			name:    "empty batch",
+			entries: nil,
+			wantErr: &types.EmptyBatchRequest{},
+		},
+		{
+			name:    "too many entries",
+			entries: batchEntries(11, 10),
+			wantErr: &types.TooManyEntriesInBatchRequest{},
+		},
+		{
+			name: "duplicated ids",
+			entries: []types.SendMessageBatchRequestEntry{
+				{Id: aws.String("1"), MessageBody: aws.String("a")},
+				{Id: aws.String("1"), MessageBody: aws.String("b")},
+			},
+			wantErr: &types.BatchEntryIdsNotDistinct{},
+		},
+		{
+			name:    "aggregate size above limit",
+			entries: batchEntries(2, MaxMessageSize/2+1),
+			wantErr: &types.BatchRequestTooLong{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.SendMessageBatch(context.Background(), &sqs.SendMessageBatchInput{
+				QueueUrl: aws.String(queueURL),
+				Entries:  tt.entries,
+			})
+			if err == nil {
+				t.Fatal("SendMessageBatch error = nil, want error")
+			}
+			if got, want := fmt.Sprintf("%T", err), fmt.Sprintf("%T", tt.wantErr); got != want {
+				t.Errorf("SendMessageBatch error type = %s, want %s", got, want)
+			}
+		})
+	}
+
+	output, err := client.SendMessageBatch(context.Background(), &sqs.SendMessageBatchInput{
+		QueueUrl: aws.String(queueURL),
+		Entries: []types.SendMessageBatchRequestEntry{
+			{Id: aws.String("ok"), MessageBody: aws.String("a")},
+			{Id: aws.String("bad"), MessageBody: aws.String("b"), DelaySeconds: MaxDelaySeconds + 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendMessageBatch error = %v", err)
+	}
+	if len(output.Successful) != 1 || *output.Successful[0].Id != "ok" {
+		t.Errorf("Successful = %v, want [ok]", output.Successful)
+	}
+	if len(output.Failed) != 1 || *output.Failed[0].Id != "bad" {
+		t.Errorf("Failed = %v, want [bad]", output.Failed)
+	}
+}
+
+func TestMemoryClientFIFOGroups(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	queueURL := createTestQueue(t, client, "groups.fifo", map[string]string{
+		"FifoQueue":                 "true",
+		"ContentBasedDeduplication": "true",
+	})
+
+	sendTestMessage(t, client, queueURL, "a-1", "a")
+	sendTestMessage(t, client, queueURL, "a-2", "a")
+	sendTestMessage(t, client, queueURL, "b-1", "b")
+
+	first := receiveTestMessages(t, client, queueURL, 1)
+	if len(first) != 1 || *first[0].Body != "a-1" {
+		t.Fatalf("first receive = %v, want [a-1]", bodies(first))
+	}
+	if got := first[0].Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]; got != "a" {
+		t.Errorf("MessageGroupId = %q, want a", got)
+	}
+
+	// Group a is blocked while a-1 is in flight
+	second := receiveTestMessages(t, client, queueURL, 10)
+	if len(second) != 1 || *second[0].Body != "b-1" {
+		t.Fatalf("second receive = %v, want [b-1]", bodies(second))
+	}
+
+	if _, err := client.DeleteMessage(context.Background(), &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: first[0].ReceiptHandle,
+	}); err != nil {
+		t.Fatalf("DeleteMessage error = %v", err)
+	}
+
+	third := receiveTestMessages(t, client, queueURL, 10)
+	if len(third) != 1 || *third[0].Body != "a-2" {
+		t.Fatalf("third receive = %v, want [a-2]", bodies(third))
+	}
+}
+
+func TestMemoryClientFIFODeduplication(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	queueURL := createTestQueue(t, client, "dedup.fifo", nil)
+
+	send := func(body string, dedupID string) error {
+		_, err := client.SendMessage(context.Background(), &sqs.SendMessageInput{
+			QueueUrl:               aws.String(queueURL),
+			MessageBody:            aws.String(body),
+			MessageGroupId:         aws.String("group"),
+			MessageDeduplicationId: optionalString(dedupID),
+		})
+		return err
+	}
+
+	if err := send("no-dedup-id", ""); err == nil {
+		t.Error("SendMessage without deduplication ID error = nil, want error")
+	}
+	if _, err := client.SendMessage(context.Background(), &sqs.SendMessageInput{
+		QueueUrl:               aws.String(queueURL),
+		MessageBody:            aws.String("no-group"),
+		MessageDeduplicationId: aws.String("x"),
+	}); err == nil {
+		t.Error("SendMessage without group error = nil, want error")
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := send("payload", "same-id"); err != nil {
+			t.Fatalf("SendMessage error = %v", err)
+		}
+	}
+	if got := queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessages); got != "1" {
+		t.Fatalf("ApproximateNumberOfMessages = %s, want 1", got)
+	}
+
+	clock.Advance(memoryDeduplicationInterval + time.Second)
+	if err := send("payload", "same-id"); err != nil {
+		t.Fatalf("SendMessage error = %v", err)
+	}
+	if got := queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessages); got != "2" {
+		t.Fatalf("ApproximateNumberOfMessages after the deduplication interval = %s, want 2", got)
+	}
+}
+
+func TestMemoryClientDeadLetterRedrive(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	dlqURL := createTestQueue(t, client, "orders-dlq", nil)
+	dlqArn := queueAttribute(t, client, dlqURL, types.QueueAttributeNameQueueArn)
+	queueURL := createTestQueue(t, client, "orders", map[string]string{
+		"VisibilityTimeout": "1",
+		"RedrivePolicy":     `{"deadLetterTargetArn":"` + dlqArn + `","maxReceiveCount":"2"}`,
+	})
+
+	sendTestMessage(t, client, queueURL, "poison", "")
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		received := receiveTestMessages(t, client, queueURL, 1)
+		if len(received) != 1 {
+			t.Fatalf("attempt %d received %d messages, want 1", attempt, len(received))
+		}
+		clock.Advance(2 * time.Second)
+	}
+
+	if got := receiveTestMessages(t, client, queueURL, 1); len(got) != 0 {
+		t.Fatalf("received %d messages after maxReceiveCount, want 0", len(got))
+	}
+
+	deadLetters := receiveTestMessages(t, client, dlqURL, 10)
+	if len(deadLetters) != 1 || *deadLetters[0].Body != "poison" {
+		t.Fatalf("dead-letter queue = %v, want [poison]", bodies(deadLetters))
+	}
+	if got := deadLetters[0].Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]; got != "1" {
+		t.Errorf("dead-letter ApproximateReceiveCount = %s, want 1", got)
+	}
+}
+
+func TestMemoryClientMessageAttributeFilter(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "attributes", nil)
+
+	if _, err := client.SendMessage(context.Background(), &sqs.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String("body"),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"trace.id":      stringAttribute("t-1"),
+			"trace.span":    stringAttribute("s-1"),
+			"CorrelationId": stringAttribute("c-1"),
+		},
+	}); err != nil {
+		t.Fatalf("SendMessage error = %v", err)
+	}
+
+	output, err := client.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(queueURL),
+		MessageAttributeNames: []string{"trace.*"},
+	})
+	if err != nil {
+		t.Fatalf("ReceiveMessage error = %v", err)
+	}
+
+	msg := output.Messages[0]
+	if len(msg.MessageAttributes) != 2 {
+		t.Errorf("MessageAttributes = %v, want only trace.* attributes", msg.MessageAttributes)
+	}
+	if msg.Attributes != nil {
+		t.Errorf("Attributes = %v, want none when no system attribute is requested", msg.Attributes)
+	}
+}
+
+// batchEntries builds n batch entries with bodies of the given size
+func batchEntries(n int, size int) []types.SendMessageBatchRequestEntry {
+	body := make([]byte, size)
+	for i := range body {
+		body[i] = 'x'
+	}
+	entries := make([]types.SendMessageBatchRequestEntry, n)
+	for i := range entries {
+		entries[i] = types.SendMessageBatchRequestEntry{
+			Id:          aws.String(strconv.Itoa(i)),
+			MessageBody: aws.String(string(body)),
+		}
+	}
+	return entries
+}
+
+// bodies returns the bodies of the messages, for failure messages
+func bodies(messages []types.Message) []string {
+	result := make([]string, len(messages))
+	for i, msg := range messages {
+		result[i] = aws.ToString(msg.Body)
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package sqs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler outcomes used to label the latency histograms
+const (
+	// OutcomeSuccess labels messages whose handler returned no error
+	OutcomeSuccess = "success"
+	// OutcomeFailure labels messages whose handler (or payload rehydration) failed
+	OutcomeFailure = "failure"
+)
+
+// defaultLatencyBuckets are the upper bounds in seconds of the handler latency histograms
+var defaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// MetricsProvider is implemented by components that expose worker metrics, such as Worker
+type MetricsProvider interface {
+	Metrics() WorkerMetrics
+}
+
+// WorkerMetrics is a point-in-time snapshot of the metrics of a Worker
+type WorkerMetrics struct {
+	QueueName     string
+	Running       bool
+	Stalled       bool
+	Received      uint64
+	Succeeded     uint64
+	Failed        uint64
+	Deleted       uint64
+	DeleteFailed  uint64
+	InFlight      int64
+	LastPoll      time.Time
+	SinceLastPoll time.Duration
+	// HandlerLatency holds one histogram per outcome (OutcomeSuccess, OutcomeFailure)
+	HandlerLatency map[string]HistogramSnapshot
+}
+
+// HistogramSnapshot is a point-in-time copy of a latency histogram.
+// Counts are cumulative, Counts[i] is the number of observations <= Buckets[i].
+type HistogramSnapshot struct {
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+// latencyHistogram is a fixed-bucket histogram of durations in seconds
+type latencyHistogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// newLatencyHistogram creates a histogram with the given bucket upper bounds
+func newLatencyHistogram(buckets []float64) *latencyHistogram {
+	return &latencyHistogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// observe records a duration
+func (h *latencyHistogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+	index := sort.SearchFloat64s(h.buckets, seconds)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if index < len(h.counts) {
+		h.counts[index]++
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// snapshot returns a copy of the histogram with cumulative bucket counts
+func (h *latencyHistogram) snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	counts := make([]uint64, len(h.counts))
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		counts[i] = cumulative
+	}
+
+	return HistogramSnapshot{
+		Buckets: append([]float64(nil), h.buckets...),
+		Counts:  counts,
+		Count:   h.count,
+		Sum:     h.sum,
+	}
+}
+
+// WritePrometheus writes the metrics of the given workers in the Prometheus text exposition format
+func WritePrometheus(w io.Writer, metrics ...WorkerMetrics) error {
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].QueueName < metrics[j].QueueName })
+	out := bufio.NewWriter(w)
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(m WorkerMetrics) uint64
+	}{
+		{"sqs_worker_messages_received_total", "Messages received from the queue.", func(m WorkerMetrics) uint64 { return m.Received }},
+		{"sqs_worker_messages_succeeded_total", "Messages handled without error.", func(m WorkerMetrics) uint64 { return m.Succeeded }},
+		{"sqs_worker_messages_failed_total", "Messages whose handler returned an error.", func(m WorkerMetrics) uint64 { return m.Failed }},
+		{"sqs_worker_messages_deleted_total", "Messages deleted after being handled.", func(m WorkerMetrics) uint64 { return m.Deleted }},
+		{"sqs_worker_messages_delete_failed_total", "Handled messages that could not be deleted.", func(m WorkerMetrics) uint64 { return m.DeleteFailed }},
+	}
+	for _, counter := range counters {
+		writeMetricHeader(out, counter.name, counter.help, "counter")
+		for _, m := range metrics {
+			fmt.Fprintf(out, "%s{queue=\"%s\"} %d\n", counter.name, escapeLabelValue(m.QueueName), counter.value(m))
+		}
+	}
+
+	gauges := []struct {
+		name  string
+		help  string
+		value func(m WorkerMetrics) float64
+	}{
+		{"sqs_worker_in_flight_messages", "Messages received and not yet finished.", func(m WorkerMetrics) float64 { return float64(m.InFlight) }},
+		{"sqs_worker_seconds_since_last_poll", "Seconds since the last successful receive.", func(m WorkerMetrics) float64 { return m.SinceLastPoll.Seconds() }},
+		{"sqs_worker_running", "Whether the worker is running (1) or not (0).", func(m WorkerMetrics) float64 { return boolToFloat(m.Running) }},
+		{"sqs_worker_stalled", "Whether the poller is stalled (1) or not (0).", func(m WorkerMetrics) float64 { return boolToFloat(m.Stalled) }},
+	}
+	for _, gauge := range gauges {
+		writeMetricHeader(out, gauge.name, gauge.help, "gauge")
+		for _, m := range metrics {
+			fmt.Fprintf(out, "%s{queue=\"%s\"} %s\n", gauge.name, escapeLabelValue(m.QueueName), formatFloat(gauge.value(m)))
+		}
+	}
+
+	const histogramName = "sqs_worker_handler_duration_seconds"
+	writeMetricHeader(out, histogramName, "Handler latency by outcome.", "histogram")
+	for _, m := range metrics {
+		outcomes := make([]string, 0, len(m.HandlerLatency))
+		for outcome := range m.HandlerLatency {
+			outcomes = append(outcomes, outcome)
+		}
+		sort.Strings(outcomes)
+
+		for _, outcome := range outcomes {
+			histogram := m.HandlerLatency[outcome]
+			labels := fmt.Sprintf("queue=\"%s\",outcome=\"%s\"", escapeLabelValue(m.QueueName), escapeLabelValue(outcome))
+			for i, bound := range histogram.Buckets {
+				fmt.Fprintf(out, "%s_bucket{%s,le=\"%s\"} %d\n", histogramName, labels, formatFloat(bound), histogram.Counts[i])
+			}
+			fmt.Fprintf(out, "%s_bucket{%s,le=\"+Inf\"} %d\n", histogramName, labels, histogram.Count)
+			fmt.Fprintf(out, "%s_sum{%s} %s\n", histogramName, labels, formatFloat(histogram.Sum))
+			fmt.Fprintf(out, "%s_count{%s} %d\n", histogramName, labels, histogram.Count)
+		}
+	}
+
+	return out.Flush()
+}
+
+// writeMetricHeader writes the HELP and TYPE lines of a metric family
+func writeMetricHeader(out *bufio.Writer, name string, help string, metricType string) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// escapeLabelValue escapes a label value for the exposition format
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatFloat formats a sample value for the exposition format
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// boolToFloat converts a boolean gauge to 0 or 1
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package sqs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	histogram := newLatencyHistogram([]float64{0.1, 1})
+	histogram.observe(50 * time.Millisecond)
+	histogram.observe(500 * time.Millisecond)
+	histogram.observe(2 * time.Second)
+
+	snapshot := histogram.snapshot()
+	if snapshot.Count != 3 {
+		t.Errorf("count = %d, want 3", snapshot.Count)
+	}
+	if snapshot.Counts[0] != 1 || snapshot.Counts[1] != 2 {
+		t.Errorf("cumulative counts = %v, want [1 2]", snapshot.Counts)
+	}
+	if snapshot.Sum < 2.54 || snapshot.Sum > 2.56 {
+		t.Errorf("sum = %f, want 2.55", snapshot.Sum)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	histogram := newLatencyHistogram([]float64{0.1, 1})
+	histogram.observe(50 * time.Millisecond)
+
+	var out strings.Builder
+	err := WritePrometheus(&out, WorkerMetrics{
+		QueueName:      `weather"queue`,
+		Running:        true,
+		Received:       5,
+		Succeeded:      4,
+		Failed:         1,
+		Deleted:        4,
+		InFlight:       2,
+		SinceLastPoll:  1500 * time.Millisecond,
+		HandlerLatency: map[string]HistogramSnapshot{OutcomeSuccess: histogram.snapshot()},
+	})
+	if err != nil {
+		t.Fatalf("WritePrometheus error = %v", err)
+	}
+
+	want := []string{
+		"# TYPE sqs_worker_messages_received_total counter",
+		`sqs_worker_messages_received_total{queue="weather\"queue"} 5`,
+		`sqs_worker_messages_failed_total{queue="weather\"queue"} 1`,
+		`sqs_worker_in_flight_messages{queue="weather\"queue"} 2`,
+		`sqs_worker_seconds_since_last_poll{queue="weather\"queue"} 1.5`,
+		`sqs_worker_running{queue="weather\"queue"} 1`,
+		"# TYPE sqs_worker_handler_duration_seconds histogram",
+		`sqs_worker_handler_duration_seconds_bucket{queue="weather\"queue",outcome="success",le="0.1"} 1`,
+		`sqs_worker_handler_duration_seconds_bucket{queue="weather\"queue",outcome="success",le="+Inf"} 1`,
+		`sqs_worker_handler_duration_seconds_count{queue="weather\"queue",outcome="success"} 1`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("output does not contain %q\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+)
+
+// AttributeOffloadedPayloadSize marks a message whose body was offloaded to a BlobStore
+// and carries the size in bytes of the original body
+const AttributeOffloadedPayloadSize = "OffloadedPayloadSize"
+
+// ErrPayloadNotFound is returned by a BlobStore when the key does not exist
+var ErrPayloadNotFound = errors.New("payload not found")
+
+// BlobStore stores message bodies that exceed the SQS message size limit.
+// Implementations can be backed by S3, a shared filesystem or any other object storage.
+type BlobStore interface {
+	Put(ctx context.Context, key string, payload []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// payloadPointer is the message body sent in place of an offloaded payload
+type payloadPointer struct {
+	OffloadKey string `json:"offloadKey"`
+}
+
+// FileBlobStore is a BlobStore backed by a local directory, intended for local runs and tests
+type FileBlobStore struct {
+	dir string
+}
+
+var _ BlobStore = (*FileBlobStore)(nil)
+
+// NewFileBlobStore creates a FileBlobStore rooted at dir, creating the directory if needed
+func NewFileBlobStore(dir string) (*FileBlobStore, error) {
+	if dir == "" {
+		return nil, errors.New("blob store directory cannot be empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob store directory %s: %w", dir, err)
+	}
+	return &FileBlobStore{dir: dir}, nil
+}
+
+// Put writes the payload to the file identified by key
+func (s *FileBlobStore) Put(_ context.Context, key string, payload []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory for key %s: %w", key, err)
+	}
+	if err := os.WriteFile(path, payload, 0o644); err != nil {
+		return fmt.Errorf("failed to write blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get reads the payload stored under key
+func (s *FileBlobStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrPayloadNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", key, err)
+	}
+	return payload, nil
+}
+
+// Delete removes the payload stored under key, missing keys are ignored
+func (s *FileBlobStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// path maps a key to a file inside the store directory, rejecting keys that escape it
+func (s *FileBlobStore) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || cleaned == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, cleaned), nil
+}
+
+// offloadPayload stores the payload and returns the pointer body that replaces it
+func offloadPayload(ctx context.Context, store BlobStore, queueName string, payload []byte) (string, error) {
+	key := queueName + "/" + uuid.NewString()
+	if err := store.Put(ctx, key, payload); err != nil {
+		return "", fmt.Errorf("failed to offload message payload for queue %s: %w", queueName, err)
+	}
+
+	pointer, err := json.Marshal(payloadPointer{OffloadKey: key})
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize payload pointer: %w", err)
+	}
+	return string(pointer), nil
+}
+
+// isOffloaded reports whether the message body was replaced by a payload pointer
+func isOffloaded(msg *types.Message) bool {
+	if msg == nil {
+		return false
+	}
+	_, ok := msg.MessageAttributes[AttributeOffloadedPayloadSize]
+	return ok
+}
+
+// rehydratePayload replaces the pointer body of an offloaded message with the stored payload
+// and returns the blob key, so it can be deleted once the message is processed
+func rehydratePayload(ctx context.Context, store BlobStore, msg *types.Message) (string, error) {
+	if !isOffloaded(msg) {
+		return "", nil
+	}
+	if store == nil {
+		return "", fmt.Errorf("message ID %s has an offloaded payload but no payload store is configured", safeMessageID(msg))
+	}
+	if msg.Body == nil {
+		return "", fmt.Errorf("message ID %s has an offloaded payload without pointer", safeMessageID(msg))
+	}
+
+	var pointer payloadPointer
+	if err := json.Unmarshal([]byte(*msg.Body), &pointer); err != nil || pointer.OffloadKey == "" {
+		return "", fmt.Errorf("message ID %s has an invalid payload pointer", safeMessageID(msg))
+	}
+
+	payload, err := store.Get(ctx, pointer.OffloadKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to load offloaded payload for message ID %s: %w", safeMessageID(msg), err)
+	}
+
+	body := string(payload)
+	msg.Body = &body
+	return pointer.OffloadKey, nil
+}
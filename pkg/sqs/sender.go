@@ -2,18 +2,99 @@ package sqs
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
+const (
+	// fifoQueueSuffix is the mandatory name suffix of SQS FIFO queues
+	fifoQueueSuffix = ".fifo"
+	// MaxMessageSize is the SQS limit in bytes for a single message and for the aggregate of a batch
+	MaxMessageSize = 256 * 1024
+	// MaxBatchEntries is the SQS limit of entries per SendMessageBatch call
+	MaxBatchEntries = 10
+	// MaxDelaySeconds is the SQS limit for the per-message delay
+	MaxDelaySeconds = 900
+)
+
+// Well-known message attribute names set by the Sender
+const (
+	// AttributeCorrelationID carries the correlation ID of the message
+	AttributeCorrelationID = "CorrelationId"
+	// AttributeSchemaVersion carries the schema version of the message body
+	AttributeSchemaVersion = "SchemaVersion"
+	// AttributeProducer carries the name of the application that produced the message
+	AttributeProducer = "Producer"
+)
+
+// DeduplicationMode controls how deduplication IDs are produced for FIFO queues
+type DeduplicationMode int
+
+const (
+	// DeduplicationExplicit only sends deduplication IDs provided by the caller.
+	// Messages without one rely on the queue ContentBasedDeduplication attribute.
+	DeduplicationExplicit DeduplicationMode = iota + 1
+	// DeduplicationContentBased derives a SHA-256 deduplication ID from the message
+	// body whenever the caller does not provide one
+	DeduplicationContentBased
+)
+
 // BatchMessage represents a message to be sent in batch
 type BatchMessage struct {
+	// MessageID identifies the message in the BatchResult, it defaults to the index of the message in the batch
 	MessageID string `json:"messageId"`
 	Body      any    `json:"body"`
+	// MessageGroupID is the FIFO message group, messages of the same group are delivered in order
+	MessageGroupID string `json:"messageGroupId,omitempty"`
+	// DeduplicationID is the explicit FIFO deduplication ID
+	DeduplicationID string `json:"deduplicationId,omitempty"`
+	// CorrelationID is sent as the CorrelationId message attribute
+	CorrelationID string `json:"correlationId,omitempty"`
+	// SchemaVersion is sent as the SchemaVersion message attribute
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+	// Attributes are additional string message attributes
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// DelaySeconds postpones the delivery of the message (0-900, standard queues only)
+	DelaySeconds int32 `json:"delaySeconds,omitempty"`
+}
+
+// SendOptions represents per-message options for SendMessageWithOptions
+type SendOptions struct {
+	// MessageGroupID is the FIFO message group, messages of the same group are delivered in order
+	MessageGroupID string
+	// DeduplicationID is the explicit FIFO deduplication ID
+	DeduplicationID string
+	// CorrelationID is sent as the CorrelationId message attribute
+	CorrelationID string
+	// SchemaVersion is sent as the SchemaVersion message attribute
+	SchemaVersion string
+	// Attributes are additional string message attributes
+	Attributes map[string]string
+	// DelaySeconds postpones the delivery of the message (0-900, standard queues only)
+	DelaySeconds int32
+}
+
+// SenderConfig defines the configuration options for a Sender
+type SenderConfig struct {
+	// DeduplicationMode controls how deduplication IDs are produced for FIFO queues
+	DeduplicationMode DeduplicationMode
+	// DefaultMessageGroupID is used for FIFO messages that do not set a message group
+	DefaultMessageGroupID string
+	// Producer is sent as the Producer message attribute of every message
+	Producer string
+	// PayloadStore enables offloading of bodies larger than OffloadThreshold
+	PayloadStore BlobStore
+	// OffloadThreshold is the message size in bytes above which the body is offloaded
+	OffloadThreshold int
 }
 
 // BatchResult represents the result of a batch send operation
@@ -31,37 +112,99 @@ type SQSClient interface {
 
 // Sender handles sending messages to SQS queues
 type Sender struct {
-	sqsClient SQSClient
+	sqsClient             SQSClient
+	deduplicationMode     DeduplicationMode
+	defaultMessageGroupID string
+	producer              string
+	payloadStore          BlobStore
+	offloadThreshold      int
+}
+
+// preparedMessage is a serialized message ready to be sent, along with its size in bytes
+type preparedMessage struct {
+	entry types.SendMessageBatchRequestEntry
+	size  int
 }
 
-// NewSender creates and returns a new Sender
+// NewSender creates and returns a new Sender with the default configuration
 func NewSender(sqsClient SQSClient) *Sender {
+	return NewSenderWithConfig(sqsClient, nil)
+}
+
+// NewSenderWithConfig creates and returns a new Sender.
+//
+// If the provided SenderConfig is nil or its fields are zero,
+// the following defaults will be used:
+//   - DeduplicationMode: DeduplicationExplicit
+//   - DefaultMessageGroupID: "" (FIFO messages must set their own group)
+//   - Producer: "" (no Producer attribute)
+//   - PayloadStore: nil (messages above the SQS limit fail)
+//   - OffloadThreshold: MaxMessageSize
+//
+// FIFO options are only sent to queues whose name ends with ".fifo",
+// so the same Sender can be used with standard queues.
+func NewSenderWithConfig(sqsClient SQSClient, config *SenderConfig) *Sender {
+	deduplicationMode := DeduplicationExplicit
+	offloadThreshold := MaxMessageSize
+	var defaultMessageGroupID, producer string
+	var payloadStore BlobStore
+
+	if config != nil {
+		if config.DeduplicationMode != 0 {
+			deduplicationMode = config.DeduplicationMode
+		}
+		if config.OffloadThreshold > 0 && config.OffloadThreshold < MaxMessageSize {
+			offloadThreshold = config.OffloadThreshold
+		}
+		defaultMessageGroupID = config.DefaultMessageGroupID
+		producer = config.Producer
+		payloadStore = config.PayloadStore
+	}
+
 	return &Sender{
-		sqsClient: sqsClient,
+		sqsClient:             sqsClient,
+		deduplicationMode:     deduplicationMode,
+		defaultMessageGroupID: defaultMessageGroupID,
+		producer:              producer,
+		payloadStore:          payloadStore,
+		offloadThreshold:      offloadThreshold,
 	}
 }
 
 // SendMessage serializes the provided body to JSON and sends it to the specified queue
 func (s *Sender) SendMessage(queueName string, body any) error {
+	return s.SendMessageWithOptions(queueName, body, nil)
+}
+
+// SendMessageWithOptions serializes the provided body to JSON and sends it to the specified queue
+// using the given per-message options
+func (s *Sender) SendMessageWithOptions(queueName string, body any, opts *SendOptions) error {
 	ctx := context.Background()
 
+	if opts == nil {
+		opts = &SendOptions{}
+	}
+
 	// Get queue URL
 	queueURL, err := s.getQueueURL(ctx, queueName)
 	if err != nil {
 		return fmt.Errorf("failed to get queue URL for %s: %w", queueName, err)
 	}
 
-	// Serialize body to JSON
-	jsonBody, err := json.Marshal(body)
+	// Serialize body and resolve attributes, FIFO options and offloading
+	prepared, err := s.prepareMessage(ctx, queueName, "", body, *opts)
 	if err != nil {
-		return fmt.Errorf("failed to serialize message body to JSON: %w", err)
+		return err
 	}
 
 	// Send message
-	messageBody := string(jsonBody)
 	_, err = s.sqsClient.SendMessage(ctx, &sqs.SendMessageInput{
-		QueueUrl:    &queueURL,
-		MessageBody: &messageBody,
+		QueueUrl:               &queueURL,
+		MessageBody:            prepared.entry.MessageBody,
+		MessageGroupId:         prepared.entry.MessageGroupId,
+		MessageDeduplicationId: prepared.entry.MessageDeduplicationId,
+		MessageAttributes:      prepared.entry.MessageAttributes,
+		DelaySeconds:           prepared.entry.DelaySeconds,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to send message to queue %s: %w", queueName, err)
@@ -70,7 +213,10 @@ func (s *Sender) SendMessage(queueName string, body any) error {
 	return nil
 }
 
-// SendMessageBatch sends multiple messages in batches of 10 to the specified queue using parallel processing
+// SendMessageBatch sends multiple messages to the specified queue using parallel processing.
+// Messages are split into batches of up to 10 entries whose aggregate size stays within the
+// 256KB SQS limit. Batches for FIFO queues are sent sequentially so the order inside each
+// message group is preserved.
 // Returns BatchResult with successful and failed message IDs
 func (s *Sender) SendMessageBatch(queueName string, messages []BatchMessage) (*BatchResult, error) {
 	if len(messages) == 0 {
@@ -88,43 +234,71 @@ func (s *Sender) SendMessageBatch(queueName string, messages []BatchMessage) (*B
 		return nil, fmt.Errorf("failed to get queue URL for %s: %w", queueName, err)
 	}
 
-	// Split messages into batches of 10 (SQS limit)
-	const batchSize = 10
-	var batches [][]BatchMessage
-	for i := 0; i < len(messages); i += batchSize {
-		end := i + batchSize
-		if end > len(messages) {
-			end = len(messages)
+	// Serialize all messages, messages that cannot be prepared are reported as failed
+	preparationFailed := make([]string, 0)
+	prepared := make([]preparedMessage, 0, len(messages))
+	for i, msg := range messages {
+		// SQS requires an ID for every batch entry
+		messageID := msg.MessageID
+		if messageID == "" {
+			messageID = strconv.Itoa(i)
+		}
+		preparedMsg, err := s.prepareMessage(ctx, queueName, messageID, msg.Body, SendOptions{
+			MessageGroupID:  msg.MessageGroupID,
+			DeduplicationID: msg.DeduplicationID,
+			CorrelationID:   msg.CorrelationID,
+			SchemaVersion:   msg.SchemaVersion,
+			Attributes:      msg.Attributes,
+			DelaySeconds:    msg.DelaySeconds,
+		})
+		if err != nil {
+			preparationFailed = append(preparationFailed, messageID)
+			continue
 		}
-		batches = append(batches, messages[i:end])
+		prepared = append(prepared, *preparedMsg)
 	}
 
-	// Channel to collect results from parallel batch sends
+	// Split messages into batches by entry count and aggregate size (SQS limits)
+	batches := splitBatches(prepared)
+
+	// Channel to collect results from batch sends
 	resultChan := make(chan *BatchResult)
 	var wg sync.WaitGroup
 
-	// Send all batches in parallel
-	for _, batch := range batches {
+	sendBatch := func(batchMessages []preparedMessage) *BatchResult {
+		batchResult, err := s.sendBatch(ctx, queueURL, batchMessages)
+		if err != nil {
+			// If the entire batch fails, mark all messages as failed
+			failedResult := &BatchResult{
+				Successful: []string{},
+				Failed:     make([]string, len(batchMessages)),
+			}
+			for i, msg := range batchMessages {
+				failedResult.Failed[i] = *msg.entry.Id
+			}
+			return failedResult
+		}
+		return batchResult
+	}
+
+	if isFIFOQueue(queueName) {
+		// Send batches one after another to keep the per-group order
 		wg.Add(1)
-		go func(batchMessages []BatchMessage) {
+		go func() {
 			defer wg.Done()
-
-			batchResult, err := s.sendBatch(ctx, queueURL, batchMessages)
-			if err != nil {
-				// If the entire batch fails, mark all messages as failed
-				failedResult := &BatchResult{
-					Successful: []string{},
-					Failed:     make([]string, len(batchMessages)),
-				}
-				for i, msg := range batchMessages {
-					failedResult.Failed[i] = msg.MessageID
-				}
-				resultChan <- failedResult
-				return
+			for _, batch := range batches {
+				resultChan <- sendBatch(batch)
 			}
-
-			resultChan <- batchResult
-		}(batch)
+		}()
+	} else {
+		// Send all batches in parallel
+		for _, batch := range batches {
+			wg.Add(1)
+			go func(batchMessages []preparedMessage) {
+				defer wg.Done()
+				resultChan <- sendBatch(batchMessages)
+			}(batch)
+		}
 	}
 
 	// Close channel after all goroutines complete
@@ -136,7 +310,7 @@ func (s *Sender) SendMessageBatch(queueName string, messages []BatchMessage) (*B
 	// Collect all results
 	finalResult := &BatchResult{
 		Successful: []string{},
-		Failed:     []string{},
+		Failed:     preparationFailed,
 	}
 
 	for batchResult := range resultChan {
@@ -147,40 +321,20 @@ func (s *Sender) SendMessageBatch(queueName string, messages []BatchMessage) (*B
 	return finalResult, nil
 }
 
-// sendBatch sends a single batch of up to 10 messages
-func (s *Sender) sendBatch(ctx context.Context, queueURL string, messages []BatchMessage) (*BatchResult, error) {
-	if len(messages) > 10 {
-		return nil, fmt.Errorf("batch size cannot exceed 10 messages, got %d", len(messages))
+// sendBatch sends a single batch of up to 10 prepared messages
+func (s *Sender) sendBatch(ctx context.Context, queueURL string, messages []preparedMessage) (*BatchResult, error) {
+	if len(messages) > MaxBatchEntries {
+		return nil, fmt.Errorf("batch size cannot exceed %d messages, got %d", MaxBatchEntries, len(messages))
 	}
 
-	entries := make([]types.SendMessageBatchRequestEntry, 0, len(messages))
-	serializationFailed := make([]string, 0)
-
-	// Prepare batch entries
-	for _, msg := range messages {
-		// Serialize body to JSON
-		jsonBody, err := json.Marshal(msg.Body)
-		if err != nil {
-			// Add to serialization failed list
-			serializationFailed = append(serializationFailed, msg.MessageID)
-			continue
-		}
-
-		messageBody := string(jsonBody)
-		entries = append(entries, types.SendMessageBatchRequestEntry{
-			Id:          &msg.MessageID,
-			MessageBody: &messageBody,
-		})
+	entries := make([]types.SendMessageBatchRequestEntry, len(messages))
+	for i, msg := range messages {
+		entries[i] = msg.entry
 	}
 
 	result := &BatchResult{
 		Successful: []string{},
-		Failed:     serializationFailed, // Start with serialization failures
-	}
-
-	// If no messages could be serialized, return early
-	if len(entries) == 0 {
-		return result, nil
+		Failed:     []string{},
 	}
 
 	// Send batch
@@ -209,6 +363,196 @@ func (s *Sender) sendBatch(ctx context.Context, queueURL string, messages []Batc
 	return result, nil
 }
 
+// prepareMessage serializes the body to JSON and builds the SQS entry with attributes,
+// delay and FIFO options. Bodies above the offload threshold are stored in the payload
+// store and replaced by a pointer.
+func (s *Sender) prepareMessage(ctx context.Context, queueName string, messageID string, body any, opts SendOptions) (*preparedMessage, error) {
+	// Serialize body to JSON
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize message body to JSON: %w", err)
+	}
+	messageBody := string(jsonBody)
+
+	if opts.DelaySeconds < 0 || opts.DelaySeconds > MaxDelaySeconds {
+		return nil, fmt.Errorf("delay seconds must be between 0 and %d, got %d", MaxDelaySeconds, opts.DelaySeconds)
+	}
+	if opts.DelaySeconds > 0 && isFIFOQueue(queueName) {
+		return nil, fmt.Errorf("per-message delay is not supported by FIFO queue %s", queueName)
+	}
+
+	// Resolve FIFO options before offloading, so content-based deduplication uses the real payload
+	messageGroupID, deduplicationID, err := s.fifoOptions(queueName, messageBody, opts.MessageGroupID, opts.DeduplicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := s.buildAttributes(opts)
+	attributesSize := messageAttributesSize(attributes)
+
+	// Offload large payloads
+	if len(messageBody)+attributesSize > s.offloadThreshold {
+		if s.payloadStore == nil {
+			return nil, fmt.Errorf("message size %d exceeds the limit of %d bytes for queue %s",
+				len(messageBody)+attributesSize, s.offloadThreshold, queueName)
+		}
+
+		pointerBody, err := offloadPayload(ctx, s.payloadStore, queueName, jsonBody)
+		if err != nil {
+			return nil, err
+		}
+		if attributes == nil {
+			attributes = make(map[string]types.MessageAttributeValue)
+		}
+		attributes[AttributeOffloadedPayloadSize] = numberAttribute(len(jsonBody))
+		messageBody = pointerBody
+		attributesSize = messageAttributesSize(attributes)
+	}
+
+	entry := types.SendMessageBatchRequestEntry{
+		Id:                     &messageID,
+		MessageBody:            &messageBody,
+		MessageGroupId:         messageGroupID,
+		MessageDeduplicationId: deduplicationID,
+		MessageAttributes:      attributes,
+		DelaySeconds:           opts.DelaySeconds,
+	}
+
+	return &preparedMessage{
+		entry: entry,
+		size:  len(messageBody) + attributesSize,
+	}, nil
+}
+
+// buildAttributes builds the message attributes from the send options and sender configuration
+func (s *Sender) buildAttributes(opts SendOptions) map[string]types.MessageAttributeValue {
+	if len(opts.Attributes) == 0 && opts.CorrelationID == "" && opts.SchemaVersion == "" && s.producer == "" {
+		return nil
+	}
+
+	attributes := make(map[string]types.MessageAttributeValue, len(opts.Attributes)+3)
+	for name, value := range opts.Attributes {
+		attributes[name] = stringAttribute(value)
+	}
+	if opts.CorrelationID != "" {
+		attributes[AttributeCorrelationID] = stringAttribute(opts.CorrelationID)
+	}
+	if opts.SchemaVersion != "" {
+		attributes[AttributeSchemaVersion] = stringAttribute(opts.SchemaVersion)
+	}
+	if s.producer != "" {
+		attributes[AttributeProducer] = stringAttribute(s.producer)
+	}
+	return attributes
+}
+
+// splitBatches groups prepared messages into batches that respect both the
+// entry count and the aggregate payload size limits of SendMessageBatch
+func splitBatches(messages []preparedMessage) [][]preparedMessage {
+	var batches [][]preparedMessage
+	var current []preparedMessage
+	currentSize := 0
+
+	for _, msg := range messages {
+		if len(current) > 0 && (len(current) == MaxBatchEntries || currentSize+msg.size > MaxMessageSize) {
+			batches = append(batches, current)
+			current = nil
+			currentSize = 0
+		}
+		current = append(current, msg)
+		currentSize += msg.size
+	}
+
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches
+}
+
+// stringAttribute builds a String message attribute value
+func stringAttribute(value string) types.MessageAttributeValue {
+	dataType := "String"
+	return types.MessageAttributeValue{
+		DataType:    &dataType,
+		StringValue: &value,
+	}
+}
+
+// numberAttribute builds a Number message attribute value
+func numberAttribute(value int) types.MessageAttributeValue {
+	dataType := "Number"
+	stringValue := strconv.Itoa(value)
+	return types.MessageAttributeValue{
+		DataType:    &dataType,
+		StringValue: &stringValue,
+	}
+}
+
+// messageAttributesSize returns the size SQS accounts for the given attributes
+// (name, data type and value of each attribute)
+func messageAttributesSize(attributes map[string]types.MessageAttributeValue) int {
+	size := 0
+	for name, value := range attributes {
+		size += len(name)
+		if value.DataType != nil {
+			size += len(*value.DataType)
+		}
+		if value.StringValue != nil {
+			size += len(*value.StringValue)
+		}
+		size += len(value.BinaryValue)
+	}
+	return size
+}
+
+// fifoOptions resolves the message group and deduplication IDs for the given queue.
+// Standard queues reject FIFO parameters, so nil values are returned for them.
+func (s *Sender) fifoOptions(queueName string, messageBody string, messageGroupID string, deduplicationID string) (*string, *string, error) {
+	if !isFIFOQueue(queueName) {
+		return nil, nil, nil
+	}
+
+	if messageGroupID == "" {
+		messageGroupID = s.defaultMessageGroupID
+	}
+	if messageGroupID == "" {
+		return nil, nil, errors.New("message group ID is required for FIFO queue " + queueName)
+	}
+
+	if deduplicationID == "" && s.deduplicationMode == DeduplicationContentBased {
+		deduplicationID = contentDeduplicationID(messageBody)
+	}
+	if deduplicationID == "" {
+		return &messageGroupID, nil, nil
+	}
+
+	return &messageGroupID, &deduplicationID, nil
+}
+
+// isFIFOQueue reports whether the queue name identifies a FIFO queue
+func isFIFOQueue(queueName string) bool {
+	return strings.HasSuffix(queueName, fifoQueueSuffix)
+}
+
+// contentDeduplicationID returns the SHA-256 hex digest of the message body
+func contentDeduplicationID(messageBody string) string {
+	sum := sha256.Sum256([]byte(messageBody))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseDeduplicationMode converts string deduplication mode to sqs.DeduplicationMode
+func ParseDeduplicationMode(mode string) DeduplicationMode {
+	switch mode {
+	case "content-based":
+		return DeduplicationContentBased
+	case "explicit":
+		return DeduplicationExplicit
+	default:
+		return DeduplicationExplicit
+	}
+}
+
 // getQueueURL retrieves the URL for the specified queue name
 func (s *Sender) getQueueURL(ctx context.Context, queueName string) (string, error) {
 	result, err := s.sqsClient.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
@@ -222,12 +566,3 @@ func (s *Sender) getQueueURL(ctx context.Context, queueName string) (string, err
 	}
 	return *result.QueueUrl, nil
 }
-
-// extractMessageIDs extracts message IDs from a slice of BatchMessage
-func extractMessageIDs(messages []BatchMessage) []string {
-	ids := make([]string, len(messages))
-	for i, msg := range messages {
-		ids[i] = msg.MessageID
-	}
-	return ids
-}
@@ -0,0 +1,337 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// recordingClient wraps a MemoryClient and records the SendMessageBatch requests
+type recordingClient struct {
+	*MemoryClient
+	mu      sync.Mutex
+	batches [][]types.SendMessageBatchRequestEntry
+}
+
+func (c *recordingClient) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	c.mu.Lock()
+	c.batches = append(c.batches, params.Entries)
+	c.mu.Unlock()
+	return c.MemoryClient.SendMessageBatch(ctx, params, optFns...)
+}
+
+type testPayload struct {
+	ID   string `json:"id"`
+	Data string `json:"data"`
+}
+
+func TestSenderSendMessageWithAttributes(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "attributes", nil)
+	sender := NewSenderWithConfig(client, &SenderConfig{Producer: "go-api"})
+
+	err := sender.SendMessageWithOptions("attributes", testPayload{ID: "1"}, &SendOptions{
+		CorrelationID: "request-1",
+		SchemaVersion: "2",
+		Attributes:    map[string]string{"Tenant": "acme"},
+	})
+	if err != nil {
+		t.Fatalf("SendMessageWithOptions error = %v", err)
+	}
+
+	received := receiveTestMessages(t, client, queueURL, 1)
+	if len(received) != 1 {
+		t.Fatalf("received %d messages, want 1", len(received))
+	}
+
+	want := map[string]string{
+		AttributeCorrelationID: "request-1",
+		AttributeSchemaVersion: "2",
+		AttributeProducer:      "go-api",
+		"Tenant":               "acme",
+	}
+	for name, value := range want {
+		attribute, ok := received[0].MessageAttributes[name]
+		if !ok || *attribute.StringValue != value {
+			t.Errorf("attribute %s = %v, want %s", name, attribute.StringValue, value)
+		}
+	}
+
+	var payload testPayload
+	if err := json.Unmarshal([]byte(*received[0].Body), &payload); err != nil || payload.ID != "1" {
+		t.Errorf("body = %s, want JSON payload with id 1", *received[0].Body)
+	}
+}
+
+func TestSenderDelaySeconds(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	queueURL := createTestQueue(t, client, "delayed", nil)
+	createTestQueue(t, client, "delayed.fifo", nil)
+	sender := NewSender(client)
+
+	if err := sender.SendMessageWithOptions("delayed", "later", &SendOptions{DelaySeconds: 10}); err != nil {
+		t.Fatalf("SendMessageWithOptions error = %v", err)
+	}
+	if got := receiveTestMessages(t, client, queueURL, 1); len(got) != 0 {
+		t.Fatalf("received %d messages before the delay, want 0", len(got))
+	}
+	clock.Advance(10 * time.Second)
+	if got := receiveTestMessages(t, client, queueURL, 1); len(got) != 1 {
+		t.Fatalf("received %d messages after the delay, want 1", len(got))
+	}
+
+	if err := sender.SendMessageWithOptions("delayed", "too-late", &SendOptions{DelaySeconds: MaxDelaySeconds + 1}); err == nil {
+		t.Error("SendMessageWithOptions with delay above the limit error = nil, want error")
+	}
+	if err := sender.SendMessageWithOptions("delayed.fifo", "fifo", &SendOptions{MessageGroupID: "g", DelaySeconds: 1}); err == nil {
+		t.Error("SendMessageWithOptions with delay on FIFO queue error = nil, want error")
+	}
+}
+
+func TestSenderFIFOOptions(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "orders.fifo", nil)
+
+	explicit := NewSender(client)
+	if err := explicit.SendMessageWithOptions("orders.fifo", "no-group", nil); err == nil {
+		t.Error("SendMessage without group on FIFO queue error = nil, want error")
+	}
+
+	sender := NewSenderWithConfig(client, &SenderConfig{
+		DeduplicationMode:     DeduplicationContentBased,
+		DefaultMessageGroupID: "default",
+	})
+	for i := 0; i < 2; i++ {
+		if err := sender.SendMessage("orders.fifo", testPayload{ID: "same"}); err != nil {
+			t.Fatalf("SendMessage error = %v", err)
+		}
+	}
+
+	received := receiveTestMessages(t, client, queueURL, 10)
+	if len(received) != 1 {
+		t.Fatalf("received %d messages, want 1 after content-based deduplication", len(received))
+	}
+	if got := received[0].Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]; got != "default" {
+		t.Errorf("MessageGroupId = %q, want default", got)
+	}
+}
+
+func TestSenderSendMessageBatchSplitsBySize(t *testing.T) {
+	client := &recordingClient{MemoryClient: NewMemoryClient()}
+	queueURL := createTestQueue(t, client.MemoryClient, "large", nil)
+	sender := NewSender(client)
+
+	data := strings.Repeat("x", 60*1024)
+	messages := make([]BatchMessage, 12)
+	for i := range messages {
+		messages[i] = BatchMessage{MessageID: string(rune('a' + i)), Body: testPayload{ID: "large", Data: data}}
+	}
+
+	result, err := sender.SendMessageBatch("large", messages)
+	if err != nil {
+		t.Fatalf("SendMessageBatch error = %v", err)
+	}
+	if len(result.Successful) != 12 || len(result.Failed) != 0 {
+		t.Fatalf("result = %d successful, %d failed, want 12 successful", len(result.Successful), len(result.Failed))
+	}
+
+	if len(client.batches) < 3 {
+		t.Errorf("sent %d batches, want at least 3 for 12 messages of 60KB", len(client.batches))
+	}
+	for _, batch := range client.batches {
+		size := 0
+		for _, entry := range batch {
+			size += len(*entry.MessageBody) + messageAttributesSize(entry.MessageAttributes)
+		}
+		if len(batch) > MaxBatchEntries || size > MaxMessageSize {
+			t.Errorf("batch with %d entries and %d bytes exceeds the SQS limits", len(batch), size)
+		}
+	}
+
+	if got := queueAttribute(t, client.MemoryClient, queueURL, types.QueueAttributeNameApproximateNumberOfMessages); got != "12" {
+		t.Errorf("ApproximateNumberOfMessages = %s, want 12", got)
+	}
+}
+
+func TestSenderSendMessageBatchReportsFailures(t *testing.T) {
+	client := NewMemoryClient()
+	createTestQueue(t, client, "partial", nil)
+	sender := NewSender(client)
+
+	result, err := sender.SendMessageBatch("partial", []BatchMessage{
+		{MessageID: "ok", Body: "small"},
+		{MessageID: "oversize", Body: strings.Repeat("x", MaxMessageSize)},
+		{MessageID: "delayed", Body: "small", DelaySeconds: -1},
+	})
+	if err != nil {
+		t.Fatalf("SendMessageBatch error = %v", err)
+	}
+	if len(result.Successful) != 1 || result.Successful[0] != "ok" {
+		t.Errorf("Successful = %v, want [ok]", result.Successful)
+	}
+	if len(result.Failed) != 2 {
+		t.Errorf("Failed = %v, want [oversize delayed]", result.Failed)
+	}
+
+	if _, err := sender.SendMessageBatch("missing", []BatchMessage{{MessageID: "1", Body: "x"}}); err == nil {
+		t.Error("SendMessageBatch to missing queue error = nil, want error")
+	}
+}
+
+// failingBatchClient wraps a MemoryClient whose SendMessageBatch always fails
+type failingBatchClient struct {
+	*MemoryClient
+}
+
+func (c *failingBatchClient) SendMessageBatch(context.Context, *sqs.SendMessageBatchInput, ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	return nil, errors.New("service unavailable")
+}
+
+func TestSenderSendMessageBatchWithoutIDs(t *testing.T) {
+	t.Run("failed batch", func(t *testing.T) {
+		client := &failingBatchClient{MemoryClient: NewMemoryClient()}
+		createTestQueue(t, client.MemoryClient, "failing", nil)
+		sender := NewSender(client)
+
+		result, err := sender.SendMessageBatch("failing", []BatchMessage{
+			{MessageID: "first", Body: "x"},
+			{Body: "y"},
+		})
+		if err != nil {
+			t.Fatalf("SendMessageBatch error = %v", err)
+		}
+		if len(result.Successful) != 0 || strings.Join(result.Failed, ",") != "first,1" {
+			t.Errorf("result = %+v, want [first 1] failed", result)
+		}
+	})
+
+	t.Run("successful batch", func(t *testing.T) {
+		client := NewMemoryClient()
+		createTestQueue(t, client, "without-ids", nil)
+		sender := NewSender(client)
+
+		result, err := sender.SendMessageBatch("without-ids", []BatchMessage{{Body: "x"}, {Body: "y"}})
+		if err != nil {
+			t.Fatalf("SendMessageBatch error = %v", err)
+		}
+		sort.Strings(result.Successful)
+		if strings.Join(result.Successful, ",") != "0,1" || len(result.Failed) != 0 {
+			t.Errorf("result = %+v, want [0 1] successful", result)
+		}
+	})
+}
+
+func TestSenderOffloadsLargePayloads(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "offload", nil)
+	dir := t.TempDir()
+	store, err := NewFileBlobStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileBlobStore error = %v", err)
+	}
+	sender := NewSenderWithConfig(client, &SenderConfig{PayloadStore: store, OffloadThreshold: 1024})
+
+	payload := testPayload{ID: "big", Data: strings.Repeat("x", 4096)}
+	if err := sender.SendMessage("offload", payload); err != nil {
+		t.Fatalf("SendMessage error = %v", err)
+	}
+
+	received := receiveTestMessages(t, client, queueURL, 1)
+	if len(received) != 1 {
+		t.Fatalf("received %d messages, want 1", len(received))
+	}
+	if !isOffloaded(&received[0]) {
+		t.Fatalf("message attributes = %v, want %s", received[0].MessageAttributes, AttributeOffloadedPayloadSize)
+	}
+	if len(*received[0].Body) >= 1024 {
+		t.Errorf("body size = %d, want a small pointer", len(*received[0].Body))
+	}
+
+	key, err := rehydratePayload(context.Background(), store, &received[0])
+	if err != nil {
+		t.Fatalf("rehydratePayload error = %v", err)
+	}
+	var decoded testPayload
+	if err := json.Unmarshal([]byte(*received[0].Body), &decoded); err != nil || decoded != payload {
+		t.Errorf("rehydrated body does not match the original payload")
+	}
+	if _, err := os.Stat(filepath.Join(dir, key)); err != nil {
+		t.Errorf("blob %s not found: %v", key, err)
+	}
+}
+
+func TestSenderRejectsOversizeWithoutStore(t *testing.T) {
+	client := NewMemoryClient()
+	createTestQueue(t, client, "oversize", nil)
+	sender := NewSender(client)
+
+	if err := sender.SendMessage("oversize", strings.Repeat("x", MaxMessageSize)); err == nil {
+		t.Error("SendMessage above the limit without payload store error = nil, want error")
+	}
+}
+
+func TestSplitBatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		want  []int
+	}{
+		{name: "empty", sizes: nil, want: nil},
+		{name: "entry limit", sizes: repeatSize(25, 10), want: []int{10, 10, 5}},
+		{name: "size limit", sizes: repeatSize(5, 100*1024), want: []int{2, 2, 1}},
+		{name: "exact size limit", sizes: []int{MaxMessageSize / 2, MaxMessageSize / 2, 1}, want: []int{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := make([]preparedMessage, len(tt.sizes))
+			for i, size := range tt.sizes {
+				messages[i] = preparedMessage{size: size}
+			}
+
+			batches := splitBatches(messages)
+			if len(batches) != len(tt.want) {
+				t.Fatalf("got %d batches, want %d", len(batches), len(tt.want))
+			}
+			for i, batch := range batches {
+				if len(batch) != tt.want[i] {
+					t.Errorf("batch %d has %d messages, want %d", i, len(batch), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseDeduplicationMode(t *testing.T) {
+	tests := map[string]DeduplicationMode{
+		"content-based": DeduplicationContentBased,
+		"explicit":      DeduplicationExplicit,
+		"":              DeduplicationExplicit,
+		"unknown":       DeduplicationExplicit,
+	}
+	for input, want := range tests {
+		if got := ParseDeduplicationMode(input); got != want {
+			t.Errorf("ParseDeduplicationMode(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+// repeatSize returns n copies of size
+func repeatSize(n int, size int) []int {
+	sizes := make([]int, n)
+	for i := range sizes {
+		sizes[i] = size
+	}
+	return sizes
+}
@@ -8,26 +8,12 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
-// HandlerFunc defines a function that handles a SQS Message
-type HandlerFunc func(msg *types.Message) error
-
-var _ Handler = HandlerFunc(nil)
-
-// HandleMessage implements the Handler interface for HandlerFunc
-func (f HandlerFunc) HandleMessage(msg *types.Message) error {
-	return f(msg)
-}
-
-// Handler defines an interface that processes a SQS Message
-type Handler interface {
-	HandleMessage(msg *types.Message) error
-}
-
 // LogLevel represents the logging level for the Worker
 type LogLevel int
 
@@ -66,12 +52,24 @@ type SQSWorkerClient interface {
 	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
 }
 
+// Client defines the SQS operations used by both the Sender and the Worker.
+// It is implemented by the AWS SDK client and by MemoryClient.
+type Client interface {
+	SQSClient
+	SQSWorkerClient
+}
+
 // WorkerConfig defines the configuration options for a Worker
 type WorkerConfig struct {
 	MaxNumberOfMessages int64
 	WaitTimeSeconds     int64
 	PoolSize            int64
 	LogLevel            LogLevel
+	// PayloadStore loads bodies offloaded by a Sender configured with the same store
+	PayloadStore BlobStore
+	// StallThreshold is the number of WaitTimeSeconds periods without a successful
+	// receive after which a running worker is reported as DOWN
+	StallThreshold int64
 }
 
 // Worker polls and processes messages from a SQS queue
@@ -83,11 +81,24 @@ type Worker struct {
 	waitTimeSeconds     int32
 	poolSize            int64
 	logLevel            LogLevel
-	handler             Handler
+	handler             ContextHandler
 	isRunning           int32 // atomic flag to track if worker is running
-	messagesProcessed   int64 // atomic counter for processed messages
+	groupLocks          *groupLocker
+	payloadStore        BlobStore
+	stallThreshold      int64
+	// atomic metrics counters
+	received     uint64
+	succeeded    uint64
+	failed       uint64
+	deleted      uint64
+	deleteFailed uint64
+	inFlight     int64
+	lastPoll     int64 // unix nanoseconds of the last successful receive
+	latency      map[string]*latencyHistogram
 }
 
+var _ MetricsProvider = (*Worker)(nil)
+
 // NewWorker creates and returns a new Worker.
 //
 // If the provided WorkerConfig is nil or its fields are zero,
@@ -96,16 +107,25 @@ type Worker struct {
 //   - WaitTimeSeconds: 20
 //   - PoolSize: 1
 //   - LogLevel: Silent
+//   - PayloadStore: nil (offloaded messages fail)
+//   - StallThreshold: 3
 //
 // Validations:
 //   - MaxNumberOfMessages must be between 1 and 10.
 //   - WaitTimeSeconds must be between 1 and 20.
 //   - PoolSize must be greater than 0.
-func NewWorker(sqsClient SQSWorkerClient, queueName string, handler Handler, config *WorkerConfig) (*Worker, error) {
+//   - StallThreshold must be at least 2.
+//
+// The handler receives the worker context, so it can observe shutdown and
+// deadlines. Handlers written against the legacy Handler interface can be
+// wrapped with AdaptHandler.
+func NewWorker(sqsClient SQSWorkerClient, queueName string, handler ContextHandler, config *WorkerConfig) (*Worker, error) {
 	var maxMessages int64 = 10
 	var waitTime int64 = 20
 	var poolSize int64 = 1
 	var logLevel LogLevel = Silent
+	var payloadStore BlobStore
+	var stallThreshold int64 = 3
 
 	if config != nil {
 		if config.MaxNumberOfMessages != 0 {
@@ -120,6 +140,10 @@ func NewWorker(sqsClient SQSWorkerClient, queueName string, handler Handler, con
 		if config.LogLevel != 0 {
 			logLevel = config.LogLevel
 		}
+		if config.StallThreshold != 0 {
+			stallThreshold = config.StallThreshold
+		}
+		payloadStore = config.PayloadStore
 	}
 
 	if maxMessages < 1 || maxMessages > 10 {
@@ -131,6 +155,12 @@ func NewWorker(sqsClient SQSWorkerClient, queueName string, handler Handler, con
 	if poolSize < 1 {
 		return nil, errors.New("poolSize must be greater than 0")
 	}
+	if stallThreshold < 2 {
+		return nil, errors.New("stallThreshold must be at least 2")
+	}
+	if handler == nil {
+		return nil, errors.New("handler cannot be nil")
+	}
 
 	ctx := context.Background()
 	result, err := sqsClient.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
@@ -153,13 +183,25 @@ func NewWorker(sqsClient SQSWorkerClient, queueName string, handler Handler, con
 		poolSize:            poolSize,
 		logLevel:            logLevel,
 		handler:             handler,
+		groupLocks:          newGroupLocker(),
+		payloadStore:        payloadStore,
+		stallThreshold:      stallThreshold,
+		latency: map[string]*latencyHistogram{
+			OutcomeSuccess: newLatencyHistogram(defaultLatencyBuckets),
+			OutcomeFailure: newLatencyHistogram(defaultLatencyBuckets),
+		},
 	}, nil
 }
 
 // Start begins polling messages and processing them concurrently.
 // It will spawn PoolSize number of workers that keep polling messages
 // until the provided context is canceled.
+//
+// Messages that carry a MessageGroupId (FIFO queues) are processed one at a
+// time per group, in the order they were received, across all pollers.
 func (w *Worker) Start(ctx context.Context) {
+	// The stall detection starts counting from the start of the worker
+	atomic.StoreInt64(&w.lastPoll, time.Now().UnixNano())
 	atomic.StoreInt32(&w.isRunning, 1)
 	defer atomic.StoreInt32(&w.isRunning, 0)
 
@@ -186,43 +228,164 @@ func (w *Worker) pollMessages(ctx context.Context) {
 				QueueUrl:            &w.queueURL,
 				MaxNumberOfMessages: w.maxNumberOfMessages,
 				WaitTimeSeconds:     w.waitTimeSeconds,
+				MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+					types.MessageSystemAttributeNameMessageGroupId,
+					types.MessageSystemAttributeNameApproximateReceiveCount,
+				},
+				MessageAttributeNames: []string{"All"},
 			})
 			if err != nil {
 				w.logf(ErrorLevel, "failed to receive messages: %v", err)
 				continue
 			}
 
-			for _, msg := range output.Messages {
-				msgCopy := msg
-				go w.handleMessage(ctx, &msgCopy)
+			atomic.StoreInt64(&w.lastPoll, time.Now().UnixNano())
+			atomic.AddUint64(&w.received, uint64(len(output.Messages)))
+			atomic.AddInt64(&w.inFlight, int64(len(output.Messages)))
+
+			for _, group := range groupMessages(output.Messages) {
+				go w.handleGroup(ctx, group)
 			}
 		}
 	}
 }
 
-func (w *Worker) handleMessage(ctx context.Context, msg *types.Message) {
+// handleGroup processes the messages of a single message group sequentially.
+// Messages without a group are received as single-message groups and run concurrently.
+func (w *Worker) handleGroup(ctx context.Context, group messageGroup) {
+	if group.id != "" {
+		unlock := w.groupLocks.lock(group.id)
+		defer unlock()
+	}
+
+	for i, msg := range group.messages {
+		succeeded := w.handleMessage(ctx, msg)
+		atomic.AddInt64(&w.inFlight, -1)
+		if succeeded {
+			continue
+		}
+		if group.id != "" && i < len(group.messages)-1 {
+			// Stop here to keep the group order, the remaining messages
+			// become visible again after the visibility timeout
+			skipped := len(group.messages) - i - 1
+			atomic.AddInt64(&w.inFlight, -int64(skipped))
+			w.logf(ErrorLevel, "skipping %d messages of group %s after a failure", skipped, group.id)
+			return
+		}
+	}
+}
+
+// handleMessage processes and deletes a single message, returning true when it succeeded
+func (w *Worker) handleMessage(ctx context.Context, msg *types.Message) bool {
 	if msg == nil {
-		return
+		return true
 	}
 
-	err := w.handler.HandleMessage(msg)
+	start := time.Now()
+
+	// Load the original body of messages offloaded by the Sender
+	offloadKey, err := rehydratePayload(ctx, w.payloadStore, msg)
 	if err != nil {
+		w.recordFailure(start)
+		w.logf(ErrorLevel, "error rehydrating message ID %s: %v", safeMessageID(msg), err)
+		return false
+	}
+
+	msgCtx := withMessageMetadata(ctx, w.queueName, msg)
+
+	err = w.handler.HandleMessageContext(msgCtx, msg)
+	if err != nil {
+		w.recordFailure(start)
 		w.logf(ErrorLevel, "error processing message ID %s: %v", safeMessageID(msg), err)
-		return
+		return false
 	}
 
+	atomic.AddUint64(&w.succeeded, 1)
+	w.latency[OutcomeSuccess].observe(time.Since(start))
+
 	_, err = w.sqsClient.DeleteMessage(ctx, &sqs.DeleteMessageInput{
 		QueueUrl:      &w.queueURL,
 		ReceiptHandle: msg.ReceiptHandle,
 	})
 	if err != nil {
+		atomic.AddUint64(&w.deleteFailed, 1)
 		w.logf(ErrorLevel, "failed to delete message ID %s: %v", safeMessageID(msg), err)
-	} else {
-		w.logf(InfoLevel, "successfully deleted message ID %s", safeMessageID(msg))
-		atomic.AddInt64(&w.messagesProcessed, 1)
+		return false
+	}
+
+	w.logf(InfoLevel, "successfully deleted message ID %s", safeMessageID(msg))
+
+	if offloadKey != "" {
+		if err := w.payloadStore.Delete(ctx, offloadKey); err != nil {
+			w.logf(ErrorLevel, "failed to delete offloaded payload of message ID %s: %v", safeMessageID(msg), err)
+		}
+	}
+
+	atomic.AddUint64(&w.deleted, 1)
+	return true
+}
+
+// recordFailure counts a failed message and records its handling latency
+func (w *Worker) recordFailure(start time.Time) {
+	atomic.AddUint64(&w.failed, 1)
+	w.latency[OutcomeFailure].observe(time.Since(start))
+}
+
+// Metrics returns a snapshot of the worker metrics
+func (w *Worker) Metrics() WorkerMetrics {
+	lastPoll := w.lastPollTime()
+	var sinceLastPoll time.Duration
+	if !lastPoll.IsZero() {
+		sinceLastPoll = time.Since(lastPoll)
+	}
+
+	latency := make(map[string]HistogramSnapshot, len(w.latency))
+	for outcome, histogram := range w.latency {
+		latency[outcome] = histogram.snapshot()
+	}
+
+	return WorkerMetrics{
+		QueueName:      w.queueName,
+		Running:        atomic.LoadInt32(&w.isRunning) == 1,
+		Stalled:        w.isStalled(),
+		Received:       atomic.LoadUint64(&w.received),
+		Succeeded:      atomic.LoadUint64(&w.succeeded),
+		Failed:         atomic.LoadUint64(&w.failed),
+		Deleted:        atomic.LoadUint64(&w.deleted),
+		DeleteFailed:   atomic.LoadUint64(&w.deleteFailed),
+		InFlight:       atomic.LoadInt64(&w.inFlight),
+		LastPoll:       lastPoll,
+		SinceLastPoll:  sinceLastPoll,
+		HandlerLatency: latency,
 	}
 }
 
+// lastPollTime returns the time of the last successful receive, or zero before the first start
+func (w *Worker) lastPollTime() time.Time {
+	lastPoll := atomic.LoadInt64(&w.lastPoll)
+	if lastPoll == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, lastPoll)
+}
+
+// isStalled reports whether a running worker has not received for StallThreshold × WaitTimeSeconds
+func (w *Worker) isStalled() bool {
+	if atomic.LoadInt32(&w.isRunning) != 1 {
+		return false
+	}
+	lastPoll := w.lastPollTime()
+	if lastPoll.IsZero() {
+		return false
+	}
+	return time.Since(lastPoll) > w.stallTimeout()
+}
+
+// stallTimeout returns the time without a successful receive after which the poller is stalled
+func (w *Worker) stallTimeout() time.Duration {
+	return time.Duration(w.stallThreshold) * time.Duration(w.waitTimeSeconds) * time.Second
+}
+
 func (w *Worker) logf(level LogLevel, format string, v ...interface{}) {
 	if w.logLevel == Silent {
 		log.Debugf(format, v...)
@@ -256,10 +419,19 @@ func ParseLogLevel(level string) LogLevel {
 	}
 }
 
-// HealthCheck returns the health status and details of the SQS worker
+// HealthCheck returns the health status and details of the SQS worker,
+// waiting up to 5 seconds for the queue connectivity test
 func (w *Worker) HealthCheck() WorkerHealthCheck {
-	isRunning := atomic.LoadInt32(&w.isRunning) == 1
-	messagesProcessed := atomic.LoadInt64(&w.messagesProcessed)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return w.HealthCheckContext(ctx)
+}
+
+// HealthCheckContext returns the health status and details of the SQS worker.
+// The queue connectivity test is abandoned, reporting the queue unavailable, when ctx is done.
+func (w *Worker) HealthCheckContext(ctx context.Context) WorkerHealthCheck {
+	metrics := w.Metrics()
+	isRunning := metrics.Running
 
 	var status HealthStatus
 	if isRunning {
@@ -268,8 +440,13 @@ func (w *Worker) HealthCheck() WorkerHealthCheck {
 		status = StatusDown
 	}
 
+	// A running worker that stopped receiving is not consuming the queue
+	if metrics.Stalled {
+		status = StatusDown
+	}
+
 	// Test queue connectivity by attempting to get queue attributes
-	queueAvailable := w.testQueueConnectivity()
+	queueAvailable := w.testQueueConnectivity(ctx)
 	if !queueAvailable {
 		status = StatusDown
 	}
@@ -282,10 +459,24 @@ func (w *Worker) HealthCheck() WorkerHealthCheck {
 		"wait_time_seconds":      strconv.FormatInt(int64(w.waitTimeSeconds), 10),
 		"log_level":              w.getLogLevelString(),
 		"is_running":             strconv.FormatBool(isRunning),
-		"messages_processed":     strconv.FormatInt(messagesProcessed, 10),
+		"messages_processed":     strconv.FormatUint(metrics.Deleted, 10),
+		"messages_received":      strconv.FormatUint(metrics.Received, 10),
+		"messages_succeeded":     strconv.FormatUint(metrics.Succeeded, 10),
+		"messages_failed":        strconv.FormatUint(metrics.Failed, 10),
+		"messages_deleted":       strconv.FormatUint(metrics.Deleted, 10),
+		"messages_delete_failed": strconv.FormatUint(metrics.DeleteFailed, 10),
+		"messages_in_flight":     strconv.FormatInt(metrics.InFlight, 10),
+		"active_message_groups":  strconv.Itoa(w.groupLocks.activeGroups()),
+		"poller_stalled":         strconv.FormatBool(metrics.Stalled),
+		"stall_timeout":          w.stallTimeout().String(),
 		"queue_available":        strconv.FormatBool(queueAvailable),
 	}
 
+	if !metrics.LastPoll.IsZero() {
+		details["last_poll"] = metrics.LastPoll.UTC().Format(time.RFC3339)
+		details["seconds_since_last_poll"] = strconv.FormatFloat(metrics.SinceLastPoll.Seconds(), 'f', 1, 64)
+	}
+
 	return WorkerHealthCheck{
 		Status:  status,
 		Details: details,
@@ -293,8 +484,7 @@ func (w *Worker) HealthCheck() WorkerHealthCheck {
 }
 
 // testQueueConnectivity tests if the queue is accessible
-func (w *Worker) testQueueConnectivity() bool {
-	ctx := context.Background()
+func (w *Worker) testQueueConnectivity(ctx context.Context) bool {
 	_, err := w.sqsClient.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
 		QueueUrl: &w.queueURL,
 		AttributeNames: []types.QueueAttributeName{
@@ -0,0 +1,375 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// startTestWorker starts the worker in background and returns a function that stops it
+func startTestWorker(t *testing.T, worker *Worker) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		worker.Start(ctx)
+	}()
+	stop := func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("worker did not stop within 5s")
+		}
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+// queueDrained reports whether the queue has no visible or in-flight messages
+func queueDrained(t *testing.T, client *MemoryClient, queueURL string) bool {
+	return queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessages) == "0" &&
+		queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessagesNotVisible) == "0"
+}
+
+func TestNewWorkerValidation(t *testing.T) {
+	client := NewMemoryClient()
+	createTestQueue(t, client, "validation", nil)
+	handler := HandlerFunc(func(msg *types.Message) error { return nil })
+
+	tests := []struct {
+		name      string
+		queueName string
+		handler   ContextHandler
+		config    *WorkerConfig
+		wantErr   bool
+	}{
+		{name: "defaults", queueName: "validation", handler: handler},
+		{name: "too many messages", queueName: "validation", handler: handler, config: &WorkerConfig{MaxNumberOfMessages: 11}, wantErr: true},
+		{name: "wait time too long", queueName: "validation", handler: handler, config: &WorkerConfig{WaitTimeSeconds: 21}, wantErr: true},
+		{name: "negative pool size", queueName: "validation", handler: handler, config: &WorkerConfig{PoolSize: -1}, wantErr: true},
+		{name: "nil handler", queueName: "validation", wantErr: true},
+		{name: "missing queue", queueName: "missing", handler: handler, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWorker(client, tt.queueName, tt.handler, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewWorker error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkerProcessesAndDeletesMessages(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "process", nil)
+	sender := NewSenderWithConfig(client, &SenderConfig{Producer: "test"})
+
+	var processed int64
+	var correlationIDs sync.Map
+	handler := ContextHandlerFunc(func(ctx context.Context, msg *types.Message) error {
+		if QueueNameFromContext(ctx) != "process" || MessageIDFromContext(ctx) == "" {
+			return errors.New("missing message metadata in context")
+		}
+		correlationIDs.Store(CorrelationIDFromContext(ctx), true)
+		atomic.AddInt64(&processed, 1)
+		return nil
+	})
+
+	worker, err := NewWorker(client, "process", handler, &WorkerConfig{WaitTimeSeconds: 1, PoolSize: 2})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+
+	for _, id := range []string{"c-1", "c-2", "c-3"} {
+		if err := sender.SendMessageWithOptions("process", testPayload{ID: id}, &SendOptions{CorrelationID: id}); err != nil {
+			t.Fatalf("SendMessageWithOptions error = %v", err)
+		}
+	}
+
+	startTestWorker(t, worker)
+	waitFor(t, 5*time.Second, func() bool { return queueDrained(t, client, queueURL) })
+
+	if got := atomic.LoadInt64(&processed); got != 3 {
+		t.Errorf("processed %d messages, want 3", got)
+	}
+	for _, id := range []string{"c-1", "c-2", "c-3"} {
+		if _, ok := correlationIDs.Load(id); !ok {
+			t.Errorf("correlation ID %s not found in handler context", id)
+		}
+	}
+	if got := worker.HealthCheck().Details["messages_processed"]; got != "3" {
+		t.Errorf("messages_processed = %s, want 3", got)
+	}
+}
+
+func TestWorkerRetriesFailedMessages(t *testing.T) {
+	clock := newTestClock()
+	client := NewMemoryClient().WithClock(clock.Now)
+	queueURL := createTestQueue(t, client, "retry", map[string]string{"VisibilityTimeout": "30"})
+	sendTestMessage(t, client, queueURL, `{"id":"1"}`, "")
+
+	var attempts int64
+	var lastReceiveCount atomic.Value
+	handler := ContextHandlerFunc(func(ctx context.Context, msg *types.Message) error {
+		lastReceiveCount.Store(ReceiveCountFromContext(ctx))
+		if atomic.AddInt64(&attempts, 1) == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	worker, err := NewWorker(client, "retry", handler, &WorkerConfig{WaitTimeSeconds: 1})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+	startTestWorker(t, worker)
+
+	waitFor(t, 5*time.Second, func() bool { return atomic.LoadInt64(&attempts) == 1 })
+	if got := queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessagesNotVisible); got != "1" {
+		t.Fatalf("failed message not in flight, ApproximateNumberOfMessagesNotVisible = %s", got)
+	}
+
+	clock.Advance(31 * time.Second)
+	waitFor(t, 5*time.Second, func() bool { return queueDrained(t, client, queueURL) })
+
+	if got := atomic.LoadInt64(&attempts); got != 2 {
+		t.Errorf("handler called %d times, want 2", got)
+	}
+	if got := lastReceiveCount.Load(); got != "2" {
+		t.Errorf("receive count on retry = %v, want 2", got)
+	}
+}
+
+func TestWorkerRejectsInvalidTypedMessages(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "typed", nil)
+	sendTestMessage(t, client, queueURL, `not-json`, "")
+
+	var handled int64
+	handler := NewTypedHandler(func(ctx context.Context, body testPayload, msg *types.Message) error {
+		atomic.AddInt64(&handled, 1)
+		return nil
+	})
+
+	worker, err := NewWorker(client, "typed", handler, &WorkerConfig{WaitTimeSeconds: 1})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+	startTestWorker(t, worker)
+
+	waitFor(t, 5*time.Second, func() bool {
+		return queueAttribute(t, client, queueURL, types.QueueAttributeNameApproximateNumberOfMessagesNotVisible) == "1"
+	})
+	if got := atomic.LoadInt64(&handled); got != 0 {
+		t.Errorf("typed handler called %d times for an invalid body, want 0", got)
+	}
+}
+
+func TestWorkerPreservesFIFOGroupOrder(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "ordered.fifo", nil)
+	sender := NewSenderWithConfig(client, &SenderConfig{DeduplicationMode: DeduplicationContentBased})
+
+	groups := []string{"a", "b", "c"}
+	const perGroup = 8
+	messages := make([]BatchMessage, 0, len(groups)*perGroup)
+	for i := 0; i < perGroup; i++ {
+		for _, group := range groups {
+			id := group + "-" + string(rune('0'+i))
+			messages = append(messages, BatchMessage{MessageID: id, Body: testPayload{ID: id, Data: group}, MessageGroupID: group})
+		}
+	}
+	if result, err := sender.SendMessageBatch("ordered.fifo", messages); err != nil || len(result.Failed) != 0 {
+		t.Fatalf("SendMessageBatch result = %v, error = %v", result, err)
+	}
+
+	var mu sync.Mutex
+	order := make(map[string][]string)
+	handler := NewTypedHandler(func(ctx context.Context, body testPayload, msg *types.Message) error {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		order[body.Data] = append(order[body.Data], body.ID)
+		mu.Unlock()
+		return nil
+	})
+
+	worker, err := NewWorker(client, "ordered.fifo", handler, &WorkerConfig{WaitTimeSeconds: 1, PoolSize: 3})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+	startTestWorker(t, worker)
+	waitFor(t, 5*time.Second, func() bool { return queueDrained(t, client, queueURL) })
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, group := range groups {
+		got := order[group]
+		if len(got) != perGroup {
+			t.Fatalf("group %s processed %d messages, want %d", group, len(got), perGroup)
+		}
+		for i, id := range got {
+			if want := group + "-" + string(rune('0'+i)); id != want {
+				t.Errorf("group %s message %d = %s, want %s", group, i, id, want)
+			}
+		}
+	}
+}
+
+func TestWorkerRehydratesOffloadedPayloads(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "offloaded", nil)
+	dir := t.TempDir()
+	store, err := NewFileBlobStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileBlobStore error = %v", err)
+	}
+	sender := NewSenderWithConfig(client, &SenderConfig{PayloadStore: store, OffloadThreshold: 512})
+
+	payload := testPayload{ID: "large", Data: strings.Repeat("y", 2048)}
+	if err := sender.SendMessage("offloaded", payload); err != nil {
+		t.Fatalf("SendMessage error = %v", err)
+	}
+
+	var received atomic.Value
+	handler := NewTypedHandler(func(ctx context.Context, body testPayload, msg *types.Message) error {
+		received.Store(body)
+		return nil
+	})
+
+	worker, err := NewWorker(client, "offloaded", handler, &WorkerConfig{WaitTimeSeconds: 1, PayloadStore: store})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+	startTestWorker(t, worker)
+	waitFor(t, 5*time.Second, func() bool { return queueDrained(t, client, queueURL) })
+
+	if got, _ := received.Load().(testPayload); got != payload {
+		t.Errorf("handler received a different payload than the one sent")
+	}
+
+	// The blob is removed once the message is deleted
+	waitFor(t, 5*time.Second, func() bool {
+		entries, err := os.ReadDir(dir + "/offloaded")
+		return err == nil && len(entries) == 0
+	})
+}
+
+func TestWorkerHealthCheck(t *testing.T) {
+	client := NewMemoryClient()
+	createTestQueue(t, client, "health", nil)
+	worker, err := NewWorker(client, "health", HandlerFunc(func(msg *types.Message) error { return nil }),
+		&WorkerConfig{WaitTimeSeconds: 1, LogLevel: ErrorLevel})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+
+	if got := worker.HealthCheck().Status; got != StatusDown {
+		t.Errorf("status before start = %s, want %s", got, StatusDown)
+	}
+
+	stop := startTestWorker(t, worker)
+	waitFor(t, 5*time.Second, func() bool { return worker.HealthCheck().Status == StatusUp })
+
+	health := worker.HealthCheck()
+	if health.Details["queue_available"] != "true" || health.Details["log_level"] != "error" {
+		t.Errorf("details = %v, want available queue and error log level", health.Details)
+	}
+
+	stop()
+	if got := worker.HealthCheck().Status; got != StatusDown {
+		t.Errorf("status after stop = %s, want %s", got, StatusDown)
+	}
+}
+
+// failingReceiveClient is a MemoryClient whose ReceiveMessage always fails, simulating a stalled poller
+type failingReceiveClient struct {
+	*MemoryClient
+}
+
+func (c *failingReceiveClient) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(50 * time.Millisecond):
+	}
+	return nil, errors.New("connection refused")
+}
+
+func TestWorkerMetrics(t *testing.T) {
+	client := NewMemoryClient()
+	queueURL := createTestQueue(t, client, "metrics", nil)
+	sendTestMessage(t, client, queueURL, `{"id":"ok-1"}`, "")
+	sendTestMessage(t, client, queueURL, `{"id":"ok-2"}`, "")
+	sendTestMessage(t, client, queueURL, `{"id":"fail"}`, "")
+
+	handler := NewTypedHandler(func(ctx context.Context, body testPayload, msg *types.Message) error {
+		if body.ID == "fail" {
+			return errors.New("handler failure")
+		}
+		return nil
+	})
+
+	worker, err := NewWorker(client, "metrics", handler, &WorkerConfig{WaitTimeSeconds: 1})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+	startTestWorker(t, worker)
+
+	waitFor(t, 5*time.Second, func() bool {
+		m := worker.Metrics()
+		return m.Deleted == 2 && m.Failed == 1 && m.InFlight == 0
+	})
+
+	metrics := worker.Metrics()
+	if metrics.Received != 3 || metrics.Succeeded != 2 || metrics.DeleteFailed != 0 {
+		t.Errorf("metrics = %+v, want 3 received, 2 succeeded and no delete failures", metrics)
+	}
+	if got := metrics.HandlerLatency[OutcomeSuccess].Count; got != 2 {
+		t.Errorf("success latency count = %d, want 2", got)
+	}
+	if got := metrics.HandlerLatency[OutcomeFailure].Count; got != 1 {
+		t.Errorf("failure latency count = %d, want 1", got)
+	}
+	if metrics.LastPoll.IsZero() || metrics.Stalled {
+		t.Errorf("last poll = %s, stalled = %v, want a recent poll", metrics.LastPoll, metrics.Stalled)
+	}
+}
+
+func TestWorkerStalledPollerIsDown(t *testing.T) {
+	memoryClient := NewMemoryClient()
+	createTestQueue(t, memoryClient, "stalled", nil)
+	client := &failingReceiveClient{MemoryClient: memoryClient}
+
+	worker, err := NewWorker(client, "stalled", HandlerFunc(func(msg *types.Message) error { return nil }),
+		&WorkerConfig{WaitTimeSeconds: 1, StallThreshold: 2})
+	if err != nil {
+		t.Fatalf("NewWorker error = %v", err)
+	}
+	startTestWorker(t, worker)
+
+	waitFor(t, 5*time.Second, func() bool { return worker.Metrics().Running })
+	if got := worker.HealthCheck().Status; got != StatusUp {
+		t.Fatalf("status right after start = %s, want %s", got, StatusUp)
+	}
+
+	waitFor(t, 5*time.Second, func() bool { return worker.HealthCheck().Status == StatusDown })
+	if got := worker.HealthCheck().Details["poller_stalled"]; got != "true" {
+		t.Errorf("poller_stalled = %s, want true", got)
+	}
+
+	if _, err := NewWorker(client, "stalled", HandlerFunc(func(msg *types.Message) error { return nil }),
+		&WorkerConfig{StallThreshold: 1}); err == nil {
+		t.Error("NewWorker with StallThreshold 1 error = nil, want error")
+	}
+}